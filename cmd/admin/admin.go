@@ -0,0 +1,28 @@
+package main
+
+import (
+	permissionbiz "github.com/xdorro/golang-grpc-base-project/internal/module/permission/biz"
+	privacybiz "github.com/xdorro/golang-grpc-base-project/internal/module/privacy/biz"
+	retentionbiz "github.com/xdorro/golang-grpc-base-project/internal/module/retention/biz"
+	rolebiz "github.com/xdorro/golang-grpc-base-project/internal/module/role/biz"
+	userbiz "github.com/xdorro/golang-grpc-base-project/internal/module/user/biz"
+	"github.com/xdorro/golang-grpc-base-project/internal/service"
+	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/lifecycle"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+)
+
+// admin holds the biz layer built by wire and the output of the commands.
+type admin struct {
+	Lifecycle     lifecycle.ILifecycle
+	Casbin        casbin.ICasbin
+	Session       session.ISession
+	UserBiz       userbiz.IUserBiz
+	RoleBiz       rolebiz.IRoleBiz
+	PermissionBiz permissionbiz.IPermissionBiz
+	RetentionBiz  retentionbiz.IRetentionBiz
+	PrivacyBiz    privacybiz.IPrivacyBiz
+	Service       service.IService
+
+	out *printer
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/bulkio"
+)
+
+// formatFlag defines the file format flag, the format is inferred from the file extension when not set.
+func formatFlag(fs *flag.FlagSet) func(file string) (string, error) {
+	format := fs.String("format", "", "file format, csv or ndjson, from the file extension by default")
+
+	return func(file string) (string, error) {
+		if *format == "" {
+			return bulkio.FormatOf(file), nil
+		}
+
+		if err := bulkio.CheckFormat(*format); err != nil {
+			_, _ = fmt.Fprintln(fs.Output(), err)
+			fs.Usage()
+			return "", errFlags
+		}
+
+		return *format, nil
+	}
+}
+
+// userImport imports the users of a CSV or NDJSON file. The file is read before connecting to the stores.
+func userImport(fs *flag.FlagSet, args []string) (action, error) {
+	file := fs.String("file", "", "file to read, - for stdin")
+	dryRun := fs.Bool("dry-run", false, "validate the rows without writing them")
+	upsert := fs.Bool("upsert", false, "update the users whose email already exists")
+	updatePassword := fs.Bool("update-password", false, "replace the password of the updated users")
+	updateRole := fs.Bool("update-role", false, "replace the role of the updated users")
+	format := formatFlag(fs)
+	if err := parse(fs, args, "file"); err != nil {
+		return nil, err
+	}
+
+	ft, err := format(*file)
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	}
+
+	users, err := readUsers(r, ft)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", *file, err)
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.userImport(ctx, users, &userbulkv1.ImportOptions{
+			DryRun:         *dryRun,
+			Upsert:         *upsert,
+			UpdatePassword: *updatePassword,
+			UpdateRole:     *updateRole,
+		})
+	}, nil
+}
+
+// userImport imports the users and prints the result of every row, it fails if a row failed.
+func (a *admin) userImport(ctx context.Context, users []*userbulkv1.ImportUser, opts *userbulkv1.ImportOptions) error {
+	res, err := a.UserBiz.ImportUsers(ctx, opts, func() (*userbulkv1.ImportUser, error) {
+		if len(users) == 0 {
+			return nil, io.EOF
+		}
+
+		user := users[0]
+		users = users[1:]
+
+		return user, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(res.Results))
+	for _, result := range res.Results {
+		msg := result.Error
+		if len(result.Violations) > 1 {
+			violations := make([]string, 0, len(result.Violations))
+			for _, v := range result.Violations {
+				violations = append(violations, v.Field+": "+v.Description)
+			}
+			msg = strings.Join(violations, "; ")
+		}
+
+		rows = append(rows, []string{
+			fmt.Sprint(result.Row), result.Email, result.Action, result.Id, result.InviteToken, msg,
+		})
+	}
+
+	if err = a.out.print(res, []string{"ROW", "EMAIL", "ACTION", "ID", "INVITE TOKEN", "ERROR"}, rows); err != nil {
+		return err
+	}
+
+	if res.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", res.Failed, len(res.Results))
+	}
+
+	return nil
+}
+
+// userExport exports the users to a CSV or NDJSON file, or to stdout.
+func userExport(fs *flag.FlagSet, args []string) (action, error) {
+	file := fs.String("file", "", "file to write, stdout by default")
+	format := formatFlag(fs)
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+
+	ft, err := format(*file)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.userExport(ctx, *file, ft)
+	}, nil
+}
+
+// userExport writes the users to the file, or to stdout, without their password.
+func (a *admin) userExport(ctx context.Context, file, format string) error {
+	if file == "" {
+		_, err := a.writeUsers(ctx, a.out.w, format)
+		return err
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	count, err := a.writeUsers(ctx, f, format)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return a.out.print(map[string]any{"file": file, "users": count},
+		[]string{"FILE", "USERS"}, [][]string{{file, fmt.Sprint(count)}})
+}
+
+// writeUsers writes the users in the format and returns their number.
+func (a *admin) writeUsers(ctx context.Context, w io.Writer, format string) (int, error) {
+	writer, err := bulkio.NewWriter(w, format)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = a.UserBiz.ExportUsers(ctx, func(users []*userbulkv1.User) error {
+		for _, user := range users {
+			if err := writer.Write(user); err != nil {
+				return err
+			}
+		}
+
+		count += len(users)
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	return count, writer.Flush()
+}
+
+// readUsers reads all the users of the import file.
+func readUsers(r io.Reader, format string) ([]*userbulkv1.ImportUser, error) {
+	reader, err := bulkio.NewReader(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*userbulkv1.ImportUser, 0)
+	for {
+		user, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return users, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+}
@@ -0,0 +1,262 @@
+// Command admin bootstraps and operates an environment from the deployment scripts, it builds
+// the same biz layer as the server and talks to the stores directly:
+//
+//	go run ./cmd/admin -env local config check
+//	go run ./cmd/admin -env local seed
+//	go run ./cmd/admin -env local role create -name admin -all
+//	go run ./cmd/admin -env local user create -name Admin -email admin@gmail.com -password-stdin -role admin
+//	go run ./cmd/admin -env local -o json session list -email admin@gmail.com
+//
+// The results are written to stdout as a table, or as JSON with -o json, and the logs to stderr.
+// It exits with 1 if the command failed and with 2 on a usage error.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+var (
+	// errUsage is returned when the command is unknown, the commands are printed.
+	errUsage = errors.New("invalid usage")
+	// errFlags is returned when the flags of a command are invalid, the flag set printed them.
+	errFlags = errors.New("invalid flags")
+)
+
+// action runs a command against the stores.
+type action func(ctx context.Context, a *admin) error
+
+// command is a subcommand, it parses its flags before the stores are connected
+// and returns its action.
+type command struct {
+	usage string
+	parse func(fs *flag.FlagSet, args []string) (action, error)
+}
+
+// commands are the subcommands by group and name.
+var commands = map[string]map[string]*command{
+	"user": {
+		"create":         {"-name NAME -email EMAIL (-password PASSWORD | -password-stdin) [-role ROLE]", userCreate},
+		"reset-password": {"-email EMAIL (-password PASSWORD | -password-stdin) [-keep-sessions]", userResetPassword},
+		"assign-role":    {"-email EMAIL -role ROLE", userAssignRole},
+		"list":           {"", userList},
+		"import":         {"-file FILE [-format csv|ndjson] [-dry-run] [-upsert [-update-password] [-update-role]]", userImport},
+		"export":         {"[-file FILE] [-format csv|ndjson]", userExport},
+		"suspend":        {"-email EMAIL -reason REASON", userSuspend},
+		"reactivate":     {"-email EMAIL [-reason REASON]", userReactivate},
+		"deactivate":     {"-email EMAIL -reason REASON", userDeactivate},
+		"restore":        {"-id ID", userRestore},
+		"purge":          {"-id ID", userPurge},
+	},
+	"role": {
+		"list":   {"", roleList},
+		"create": {"-name NAME (-permission SLUG[,SLUG] | -all)", roleCreate},
+		"grant":  {"-name NAME (-permission SLUG[,SLUG] | -all)", roleGrant},
+		"revoke": {"-name NAME -permission SLUG[,SLUG]", roleRevoke},
+	},
+	"session": {
+		"list":   {"-email EMAIL", sessionList},
+		"revoke": {"-email EMAIL (-id ID | -all)", sessionRevoke},
+	},
+	"policy": {
+		"export": {"[-file FILE]", policyExport},
+		"import": {"-file FILE [-replace] [-dry-run]", policyImport},
+	},
+	"retention": {
+		"run": {"[-dry-run]", retentionRun},
+	},
+	"privacy": {
+		"export":   {"-email EMAIL [-file FILE]", privacyExport},
+		"erasures": {"[-status pending|rejected|completed]", privacyErasures},
+		"approve":  {"-id ID", privacyApprove},
+		"reject":   {"-id ID -note NOTE", privacyReject},
+	},
+	"seed": {
+		"": {"", seed},
+	},
+}
+
+func main() {
+	// -env is option for command line
+	env := flag.String("env", "local", "environment")
+	format := flag.String("o", formatTable, "output format, table or json")
+	verbose := flag.Bool("v", false, "log the info messages")
+	timeout := flag.Duration("timeout", time.Minute, "timeout of the command")
+	flag.Usage = usage
+	flag.Parse()
+
+	level := zerolog.WarnLevel
+	if *verbose {
+		level = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(level)
+	log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}).
+		With().
+		Timestamp().
+		Logger()
+	zerolog.DefaultContextLogger = &log.Logger
+
+	out, err := newPrinter(os.Stdout, *format)
+	if err != nil {
+		fail(err)
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		fail(errUsage)
+	}
+
+	// the config is checked before connecting to the stores
+	if args[0] == "config" {
+		if len(args) != 2 || args[1] != "check" {
+			fail(errUsage)
+		}
+
+		if err = configCheck(out, *env); err != nil {
+			fail(err)
+		}
+
+		return
+	}
+
+	fs, cmd, args := lookup(args)
+	if cmd == nil {
+		fail(errUsage)
+	}
+
+	run, err := cmd.parse(fs, args)
+	if err != nil {
+		fail(err)
+	}
+
+	cfg, err := config.Check(*env)
+	if err != nil {
+		fail(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	a := initAdmin(cfg)
+	a.out = out
+
+	err = run(ctx, a)
+	cancel()
+
+	// the stores are closed by the lifecycle, as in the server
+	if stopErr := a.Lifecycle.Stop(); stopErr != nil {
+		log.Warn().Err(stopErr).Msg("Failed to close the stores")
+	}
+
+	if err != nil {
+		fail(err)
+	}
+}
+
+// lookup returns the subcommand of the arguments, its flag set and its arguments.
+func lookup(args []string) (*flag.FlagSet, *command, []string) {
+	group, ok := commands[args[0]]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	name, rest := "", args[1:]
+	if _, ok = group[""]; !ok {
+		if len(args) < 2 {
+			return nil, nil, nil
+		}
+
+		name, rest = args[1], args[2:]
+	}
+
+	cmd, ok := group[name]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	fs := flag.NewFlagSet(strings.TrimSpace(args[0]+" "+name), flag.ContinueOnError)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(fs.Output(), "Usage: %s [flags] %s %s\n", os.Args[0], fs.Name(), cmd.usage)
+		fs.PrintDefaults()
+	}
+
+	return fs, cmd, rest
+}
+
+// parse parses the flags of the command, the string flags named are required.
+func parse(fs *flag.FlagSet, args []string, required ...string) error {
+	if err := fs.Parse(args); err != nil {
+		return errFlags
+	}
+
+	if fs.NArg() > 0 {
+		_, _ = fmt.Fprintf(fs.Output(), "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return errFlags
+	}
+
+	for _, name := range required {
+		if f := fs.Lookup(name); f == nil || f.Value.String() == "" {
+			_, _ = fmt.Fprintf(fs.Output(), "flag is required: -%s\n", name)
+			fs.Usage()
+			return errFlags
+		}
+	}
+
+	return nil
+}
+
+// usage prints the commands and the global flags.
+func usage() {
+	w := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(w, "Usage: %s [flags] <command> [command flags]\n\nCommands:\n", os.Args[0])
+	_, _ = fmt.Fprintln(w, "  config check")
+
+	groups := make([]string, 0, len(commands))
+	for group := range commands {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		names := make([]string, 0, len(commands[group]))
+		for name := range commands[group] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			line := strings.Join(strings.Fields(strings.Join([]string{group, name, commands[group][name].usage}, " ")), " ")
+			_, _ = fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+
+	_, _ = fmt.Fprintln(w, "\nFlags:")
+	flag.PrintDefaults()
+}
+
+// fail prints the error and exits, with 2 on a usage error.
+func fail(err error) {
+	switch {
+	case errors.Is(err, errFlags):
+		os.Exit(2)
+	case errors.Is(err, errUsage):
+		if err != errUsage {
+			_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		}
+		usage()
+		os.Exit(2)
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", describe(err))
+	os.Exit(1)
+}
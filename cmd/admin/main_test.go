@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		args []string
+		name string
+		rest []string
+	}{
+		{args: []string{"user", "create", "-name", "Admin"}, name: "user create", rest: []string{"-name", "Admin"}},
+		{args: []string{"seed"}, name: "seed", rest: []string{}},
+		{args: []string{"user"}},
+		{args: []string{"user", "unknown"}},
+		{args: []string{"unknown", "list"}},
+	}
+
+	for _, tt := range tests {
+		fs, cmd, rest := lookup(tt.args)
+		if tt.name == "" {
+			if cmd != nil {
+				t.Errorf("lookup(%v) = %s, want no command", tt.args, fs.Name())
+			}
+			continue
+		}
+
+		if cmd == nil || fs.Name() != tt.name || !reflect.DeepEqual(rest, tt.rest) {
+			t.Errorf("lookup(%v) = %v %v, want %s %v", tt.args, fs, rest, tt.name, tt.rest)
+		}
+	}
+}
+
+func TestParseCommands(t *testing.T) {
+	policies := filepath.Join(t.TempDir(), "policies.csv")
+	if err := os.WriteFile(policies, []byte("p, admin, /user.v1.UserService/*\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args []string
+		want error
+	}{
+		{args: []string{"user", "create", "-name", "Admin", "-email", "admin@example.com", "-password", "secret"}},
+		{args: []string{"user", "create", "-name", "Admin", "-email", "admin@example.com"}, want: errFlags},
+		{args: []string{"user", "create", "-email", "admin@example.com", "-password", "secret"}, want: errFlags},
+		{args: []string{"user", "list", "extra"}, want: errFlags},
+		{args: []string{"user", "list", "-unknown"}, want: errFlags},
+		{args: []string{"user", "suspend", "-email", "a@example.com"}, want: errFlags},
+		{args: []string{"user", "suspend", "-email", "a@example.com", "-reason", "abuse"}},
+		{args: []string{"user", "reactivate", "-email", "a@example.com"}},
+		{args: []string{"user", "export", "-format", "xml"}, want: errFlags},
+		{args: []string{"role", "create", "-name", "admin", "-all"}},
+		{args: []string{"role", "create", "-name", "admin"}, want: errFlags},
+		{args: []string{"role", "grant", "-name", "admin", "-all", "-permission", "/a"}, want: errFlags},
+		{args: []string{"session", "revoke", "-email", "a@example.com", "-all"}},
+		{args: []string{"session", "revoke", "-email", "a@example.com", "-all", "-id", "1"}, want: errFlags},
+		{args: []string{"policy", "import", "-file", policies}},
+		{args: []string{"privacy", "reject", "-id", "1"}, want: errFlags},
+		{args: []string{"seed"}},
+	}
+
+	for _, tt := range tests {
+		fs, cmd, rest := lookup(tt.args)
+		if cmd == nil {
+			t.Fatalf("lookup(%v) = nil, want a command", tt.args)
+		}
+		fs.SetOutput(io.Discard)
+
+		run, err := cmd.parse(fs, rest)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("parse(%v) error = %v, want %v", tt.args, err, tt.want)
+		}
+
+		if tt.want == nil && run == nil {
+			t.Errorf("parse(%v) = nil, want an action", tt.args)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" /a, ,/b ,")
+	if want := []string{"/a", "/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitList() = %v, want %v", got, want)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	rules := [][]string{
+		{policyType, "admin", "/user.v1.UserService/*"},
+		{groupingType, "user-1", "admin"},
+	}
+
+	var buf bytes.Buffer
+	if err := writePolicies(&buf, rules); err != nil {
+		t.Fatalf("writePolicies() error = %v", err)
+	}
+
+	got, err := readPolicies(strings.NewReader("# exported\n" + buf.String()))
+	if err != nil {
+		t.Fatalf("readPolicies() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, rules) {
+		t.Errorf("readPolicies() = %v, want %v", got, rules)
+	}
+
+	for _, input := range []string{"x, admin, /a\n", "p, admin\n", "p, , /a\n"} {
+		if _, err = readPolicies(strings.NewReader(input)); err == nil {
+			t.Errorf("readPolicies(%q) error = nil, want an error", input)
+		}
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	if _, err := newPrinter(io.Discard, "yaml"); !errors.Is(err, errUsage) {
+		t.Errorf("newPrinter(yaml) error = %v, want %v", err, errUsage)
+	}
+
+	var buf bytes.Buffer
+	p, _ := newPrinter(&buf, formatTable)
+	if err := p.print(nil, []string{"ROLE", "PERMISSION"}, [][]string{{"admin", "/a"}}); err != nil {
+		t.Fatalf("print() error = %v", err)
+	}
+
+	if want := "ROLE   PERMISSION\nadmin  /a\n"; buf.String() != want {
+		t.Errorf("print() table = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	p, _ = newPrinter(&buf, formatJSON)
+	if err := p.print(map[string]string{"role": "admin"}, nil, nil); err != nil {
+		t.Fatalf("print() error = %v", err)
+	}
+
+	if want := "{\n  \"role\": \"admin\"\n}\n"; buf.String() != want {
+		t.Errorf("print() json = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	err := connect.NewError(connect.CodeInvalidArgument, errors.New("invalid request"))
+	detail, detailErr := connect.NewErrorDetail(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "email", Description: "must be an email"},
+			{Field: "name", Description: "is required"},
+		},
+	})
+	if detailErr != nil {
+		t.Fatal(detailErr)
+	}
+	err.AddDetail(detail)
+
+	want := "invalid_argument: invalid request\n  email: must be an email\n  name: is required"
+	if got := describe(err); got != want {
+		t.Errorf("describe() = %q, want %q", got, want)
+	}
+
+	if got := describe(errors.New("plain")); got != "plain" {
+		t.Errorf("describe() = %q, want plain", got)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+// seed runs the permission seeder, reconciling the permissions as configured.
+func seed(fs *flag.FlagSet, args []string) (action, error) {
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.seed(ctx)
+	}, nil
+}
+
+// seed runs the seeder and prints its report.
+func (a *admin) seed(ctx context.Context) error {
+	report, err := a.Service.Seed(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0)
+	for _, slug := range report.Inserted {
+		rows = append(rows, []string{"insert", slug})
+	}
+	for from, to := range report.Renamed {
+		rows = append(rows, []string{"rename", from + " -> " + to})
+	}
+	for _, slug := range report.Orphaned {
+		rows = append(rows, []string{"orphan", slug})
+	}
+	for _, policy := range report.Policies {
+		rows = append(rows, []string{"remove policy", strings.Join(policy, ", ")})
+	}
+	for _, slug := range report.Protected {
+		rows = append(rows, []string{"protect", slug})
+	}
+
+	if report.DryRun {
+		for _, row := range rows {
+			row[0] += " (dry run)"
+		}
+	}
+
+	return a.out.print(report, []string{"CHANGE", "PERMISSION"}, rows)
+}
+
+// configCheck reads and validates the config of the environment, without connecting to the stores.
+// The redacted config is printed with -o json.
+func configCheck(out *printer, env string) error {
+	cfg, err := config.Check(env)
+
+	result := map[string]any{"env": env, "valid": err == nil}
+	row := []string{env, fmt.Sprint(err == nil), ""}
+	if err != nil {
+		result["error"] = err.Error()
+		row[2] = err.Error()
+	} else {
+		result["config"] = cfg.Redacted()
+	}
+
+	if printErr := out.print(result, []string{"ENV", "VALID", "ERROR"}, [][]string{row}); printErr != nil {
+		return printErr
+	}
+
+	return err
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+const (
+	// formatTable writes the results as an aligned table.
+	formatTable = "table"
+	// formatJSON writes the results as indented JSON.
+	formatJSON = "json"
+)
+
+// printer writes the results of the commands in the output format.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// newPrinter returns a printer of the format.
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	if format != formatTable && format != formatJSON {
+		return nil, fmt.Errorf("%w: output format must be %s or %s", errUsage, formatTable, formatJSON)
+	}
+
+	return &printer{w: w, format: format}, nil
+}
+
+// print writes the value as JSON, or the rows under the header as a table.
+func (p *printer) print(value any, header []string, rows [][]string) error {
+	if p.format == formatJSON {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// describe returns the message of the error with its field violations.
+func describe(err error) string {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return err.Error()
+	}
+
+	msg := connectErr.Error()
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		if err != nil {
+			continue
+		}
+
+		if badRequest, ok := value.(*errdetails.BadRequest); ok && len(badRequest.GetFieldViolations()) > 1 {
+			for _, v := range badRequest.GetFieldViolations() {
+				msg += fmt.Sprintf("\n  %s: %s", v.GetField(), v.GetDescription())
+			}
+		}
+	}
+
+	return msg
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// policyType is the type of the permission policies, role and permission slug.
+	policyType = "p"
+	// groupingType is the type of the grouping policies, subject and role.
+	groupingType = "g"
+)
+
+// policyExport writes the casbin policies in the casbin CSV format, to stdout or to the file.
+func policyExport(fs *flag.FlagSet, args []string) (action, error) {
+	file := fs.String("file", "", "file to write, stdout by default")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+
+	return func(_ context.Context, a *admin) error {
+		return a.policyExport(*file)
+	}, nil
+}
+
+// policyExport writes the policies to the file, or prints them.
+func (a *admin) policyExport(file string) error {
+	rules := a.policies()
+
+	if file == "" {
+		if a.out.format == formatJSON {
+			return a.out.print(rules, nil, nil)
+		}
+
+		return writePolicies(a.out.w, rules)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err = writePolicies(f, rules); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return a.out.print(map[string]any{"file": file, "policies": len(rules)},
+		[]string{"FILE", "POLICIES"}, [][]string{{file, fmt.Sprint(len(rules))}})
+}
+
+// policyImport adds the casbin policies of the file, with -replace the policies missing
+// from the file are removed. The file is read before connecting to the stores.
+func policyImport(fs *flag.FlagSet, args []string) (action, error) {
+	file := fs.String("file", "", "file to read, - for stdin")
+	replace := fs.Bool("replace", false, "remove the policies missing from the file")
+	dryRun := fs.Bool("dry-run", false, "print the changes without applying them")
+	if err := parse(fs, args, "file"); err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	}
+
+	rules, err := readPolicies(r)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", *file, err)
+	}
+
+	return func(_ context.Context, a *admin) error {
+		return a.policyImport(rules, *replace, *dryRun)
+	}, nil
+}
+
+// policyImport applies the changes between the policies and the rules, and prints them.
+func (a *admin) policyImport(rules [][]string, replace, dryRun bool) error {
+	existing := a.policies()
+	current := make(map[string]bool, len(existing))
+	for _, rule := range existing {
+		current[strings.Join(rule, ",")] = true
+	}
+
+	imported := make(map[string]bool)
+	added := make([][]string, 0)
+	for _, rule := range rules {
+		key := strings.Join(rule, ",")
+		if !current[key] && !imported[key] {
+			added = append(added, rule)
+		}
+		imported[key] = true
+	}
+
+	removed := make([][]string, 0)
+	if replace {
+		for _, rule := range existing {
+			if !imported[strings.Join(rule, ",")] {
+				removed = append(removed, rule)
+			}
+		}
+	}
+
+	if !dryRun {
+		// the new policies are added first, a failure never leaves the roles without policies
+		if err := a.applyPolicies(added, true); err != nil {
+			return err
+		}
+
+		if err := a.applyPolicies(removed, false); err != nil {
+			return err
+		}
+	}
+
+	rows := make([][]string, 0, len(added)+len(removed))
+	for _, rule := range added {
+		rows = append(rows, []string{"add", strings.Join(rule, ", ")})
+	}
+	for _, rule := range removed {
+		rows = append(rows, []string{"remove", strings.Join(rule, ", ")})
+	}
+
+	result := map[string]any{"dry_run": dryRun, "added": added, "removed": removed}
+	return a.out.print(result, []string{"CHANGE", "POLICY"}, rows)
+}
+
+// policies returns the permission and grouping policies, prefixed with their type and sorted.
+func (a *admin) policies() [][]string {
+	enforcer := a.Casbin.Enforcer()
+	rules := make([][]string, 0)
+
+	for _, rule := range enforcer.GetPolicy() {
+		rules = append(rules, append([]string{policyType}, rule...))
+	}
+	for _, rule := range enforcer.GetGroupingPolicy() {
+		rules = append(rules, append([]string{groupingType}, rule...))
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return strings.Join(rules[i], ",") < strings.Join(rules[j], ",")
+	})
+
+	return rules
+}
+
+// applyPolicies adds or removes the policies prefixed with their type.
+func (a *admin) applyPolicies(rules [][]string, add bool) error {
+	byType := map[string][][]string{}
+	for _, rule := range rules {
+		byType[rule[0]] = append(byType[rule[0]], rule[1:])
+	}
+
+	enforcer := a.Casbin.Enforcer()
+	var err error
+	switch {
+	case len(byType[policyType]) > 0 && add:
+		_, err = enforcer.AddPolicies(byType[policyType])
+	case len(byType[policyType]) > 0:
+		_, err = enforcer.RemovePolicies(byType[policyType])
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(byType[groupingType]) > 0 && add:
+		_, err = enforcer.AddGroupingPolicies(byType[groupingType])
+	case len(byType[groupingType]) > 0:
+		_, err = enforcer.RemoveGroupingPolicies(byType[groupingType])
+	}
+
+	return err
+}
+
+// writePolicies writes the policies in the casbin CSV format.
+func writePolicies(w io.Writer, rules [][]string) error {
+	for _, rule := range rules {
+		if _, err := fmt.Fprintln(w, strings.Join(rule, ", ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readPolicies reads the policies in the casbin CSV format, the lines starting with # are comments.
+func readPolicies(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rules := make([][]string, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rules, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+
+		line, _ := reader.FieldPos(0)
+		if record[0] != policyType && record[0] != groupingType {
+			return nil, fmt.Errorf("line %d: policy type must be %s or %s", line, policyType, groupingType)
+		}
+		if len(record) != 3 || record[1] == "" || record[2] == "" {
+			return nil, fmt.Errorf("line %d: policy must have 2 values", line)
+		}
+
+		rules = append(rules, record)
+	}
+}
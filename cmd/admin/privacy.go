@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	privacyv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1"
+)
+
+// reviewer is the reviewer of the erasures reviewed with the admin CLI.
+const reviewer = "admin-cli"
+
+// privacyExport writes everything stored for a user as a JSON archive.
+func privacyExport(fs *flag.FlagSet, args []string) (action, error) {
+	email := fs.String("email", "", "email of the user")
+	file := fs.String("file", "", "file to write, stdout by default")
+	if err := parse(fs, args, "email"); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.privacyExport(ctx, *email, *file)
+	}, nil
+}
+
+// privacyExport writes the archive of the user to the file, or to stdout.
+func (a *admin) privacyExport(ctx context.Context, email, file string) error {
+	user, err := a.UserBiz.FindUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	archive, err := a.PrivacyBiz.ExportData(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+
+	if file == "" {
+		return writeArchive(a.out.w, archive)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err = writeArchive(f, archive); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return a.out.print(map[string]any{"file": file, "user": user.Id},
+		[]string{"FILE", "USER"}, [][]string{{file, user.Id}})
+}
+
+// writeArchive writes the archive as indented JSON, in the json mapping of the proto
+// as the archives exported with the privacy service.
+func writeArchive(w io.Writer, archive *privacyv1.DataArchive) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(archive)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// privacyErasures lists the erasures, the pending ones by default.
+func privacyErasures(fs *flag.FlagSet, args []string) (action, error) {
+	status := fs.String("status", privacyv1.ErasurePending, "status of the erasures, all of them if empty")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.privacyErasures(ctx, *status)
+	}, nil
+}
+
+// privacyErasures prints the erasures with the status, page by page.
+func (a *admin) privacyErasures(ctx context.Context, status string) error {
+	erasures := make([]*privacyv1.Erasure, 0)
+	for page := int64(1); ; page++ {
+		res, err := a.PrivacyBiz.FindAllErasures(ctx, page, status)
+		if err != nil {
+			return err
+		}
+
+		erasures = append(erasures, res.Data...)
+		if page >= res.TotalPage {
+			break
+		}
+	}
+
+	rows := make([][]string, 0, len(erasures))
+	for _, erasure := range erasures {
+		rows = append(rows, []string{
+			erasure.Id, erasure.Subject, erasure.Status,
+			time.Unix(erasure.RequestedAt, 0).Format(time.RFC3339), erasure.Reason,
+		})
+	}
+
+	return a.out.print(erasures, []string{"ID", "SUBJECT", "STATUS", "REQUESTED AT", "REASON"}, rows)
+}
+
+// privacyApprove erases the user of a pending erasure.
+func privacyApprove(fs *flag.FlagSet, args []string) (action, error) {
+	id := fs.String("id", "", "id of the erasure")
+	if err := parse(fs, args, "id"); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		res, err := a.PrivacyBiz.ApproveErasure(ctx, *id, reviewer)
+		if err != nil {
+			return err
+		}
+
+		return a.printErasure(res)
+	}, nil
+}
+
+// privacyReject rejects a pending erasure.
+func privacyReject(fs *flag.FlagSet, args []string) (action, error) {
+	id := fs.String("id", "", "id of the erasure")
+	note := fs.String("note", "", "reason of the rejection, given to the user")
+	if err := parse(fs, args, "id", "note"); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		res, err := a.PrivacyBiz.RejectErasure(ctx, *id, reviewer, *note)
+		if err != nil {
+			return err
+		}
+
+		return a.printErasure(res)
+	}, nil
+}
+
+// printErasure prints the reviewed erasure.
+func (a *admin) printErasure(erasure *privacyv1.Erasure) error {
+	return a.out.print(erasure, []string{"ID", "SUBJECT", "STATUS", "REDACTED AUDITS"},
+		[][]string{{erasure.Id, erasure.Subject, erasure.Status, fmt.Sprint(erasure.RedactedAudits)}})
+}
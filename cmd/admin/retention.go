@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+// retentionRun purges or anonymizes the soft deleted records past their retention age.
+func retentionRun(fs *flag.FlagSet, args []string) (action, error) {
+	dryRun := fs.Bool("dry-run", false, "only count the records")
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.retentionRun(ctx, *dryRun)
+	}, nil
+}
+
+// retentionRun runs the retention and prints the number of records.
+func (a *admin) retentionRun(ctx context.Context, dryRun bool) error {
+	res, err := a.RetentionBiz.Run(ctx, dryRun)
+	if err != nil {
+		return err
+	}
+
+	return a.out.print(res, []string{"MODE", "PURGED USERS", "ANONYMIZED USERS", "PURGED PERMISSIONS", "DRY RUN"},
+		[][]string{{
+			res.Mode, fmt.Sprint(res.PurgedUsers), fmt.Sprint(res.AnonymizedUsers),
+			fmt.Sprint(res.PurgedPermissions), fmt.Sprint(res.DryRun),
+		}})
+}
+
+// userRestore restores a soft deleted user.
+func userRestore(fs *flag.FlagSet, args []string) (action, error) {
+	return userRecord(fs, args, "restored", func(a *admin) func(context.Context, string) error {
+		return a.RetentionBiz.RestoreUser
+	})
+}
+
+// userPurge removes a soft deleted user with its sessions.
+func userPurge(fs *flag.FlagSet, args []string) (action, error) {
+	return userRecord(fs, args, "purged", func(a *admin) func(context.Context, string) error {
+		return a.RetentionBiz.PurgeUser
+	})
+}
+
+// userRecord applies the method of the retention to the soft deleted user with the id, the deleted
+// users have no email lookup since their emails may be reused.
+func userRecord(fs *flag.FlagSet, args []string, result string,
+	method func(a *admin) func(context.Context, string) error,
+) (action, error) {
+	id := fs.String("id", "", "id of the deleted user")
+	if err := parse(fs, args, "id"); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		if err := method(a)(ctx, *id); err != nil {
+			return err
+		}
+
+		res := map[string]any{"id": *id, "result": result}
+		return a.out.print(res, []string{"ID", "RESULT"}, [][]string{{*id, result}})
+	}, nil
+}
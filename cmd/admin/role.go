@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	permissionv1 "github.com/xdorro/proto-base-project/proto-gen-go/permission/v1"
+	rolev1 "github.com/xdorro/proto-base-project/proto-gen-go/role/v1"
+)
+
+// permissionFlags defines the permission flags, -all selects every permission.
+// The returned function checks that one of them is set.
+func permissionFlags(fs *flag.FlagSet) (*string, *bool, func() error) {
+	slugs := fs.String("permission", "", "comma separated permission slugs")
+	all := fs.Bool("all", false, "all the permissions")
+
+	return slugs, all, func() error {
+		if *all == (*slugs != "") {
+			_, _ = fmt.Fprintln(fs.Output(), "one of -permission and -all is required")
+			fs.Usage()
+			return errFlags
+		}
+
+		return nil
+	}
+}
+
+// roleList lists the roles with their permissions.
+func roleList(fs *flag.FlagSet, args []string) (action, error) {
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.roleList(ctx)
+	}, nil
+}
+
+// roleList prints the roles with their number of permissions.
+func (a *admin) roleList(ctx context.Context) error {
+	res, err := a.RoleBiz.FindAllRoles(ctx, connect.NewRequest(&rolev1.FindAllRolesRequest{}))
+	if err != nil {
+		return err
+	}
+
+	roles := make([]*rolev1.Role, 0, len(res.Msg.GetData()))
+	rows := make([][]string, 0, len(res.Msg.GetData()))
+	for _, role := range res.Msg.GetData() {
+		found, err := a.RoleBiz.FindRoleByName(ctx, connect.NewRequest(&rolev1.CommonNameRequest{Name: role.GetName()}))
+		if err != nil {
+			return err
+		}
+
+		roles = append(roles, found.Msg)
+		rows = append(rows, []string{found.Msg.GetName(), fmt.Sprint(len(found.Msg.GetPermissions()))})
+	}
+
+	return a.out.print(roles, []string{"NAME", "PERMISSIONS"}, rows)
+}
+
+// roleCreate creates a role with its permissions.
+func roleCreate(fs *flag.FlagSet, args []string) (action, error) {
+	name := fs.String("name", "", "name of the role")
+	slugs, all, check := permissionFlags(fs)
+	if err := parse(fs, args, "name"); err != nil {
+		return nil, err
+	}
+
+	if err := check(); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		permissions, err := a.selectPermissions(ctx, *slugs, *all)
+		if err != nil {
+			return err
+		}
+
+		_, err = a.RoleBiz.CreateRole(ctx, connect.NewRequest(&rolev1.CreateRoleRequest{
+			Name:        *name,
+			Permissions: permissions,
+		}))
+		if err != nil {
+			return err
+		}
+
+		return a.printRole(ctx, *name)
+	}, nil
+}
+
+// roleGrant adds permissions to a role.
+func roleGrant(fs *flag.FlagSet, args []string) (action, error) {
+	name := fs.String("name", "", "name of the role")
+	slugs, all, check := permissionFlags(fs)
+	if err := parse(fs, args, "name"); err != nil {
+		return nil, err
+	}
+
+	if err := check(); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.roleGrant(ctx, *name, *slugs, *all)
+	}, nil
+}
+
+// roleGrant adds the permissions missing from the role and prints it.
+func (a *admin) roleGrant(ctx context.Context, name, slugs string, all bool) error {
+	permissions, err := a.selectPermissions(ctx, slugs, all)
+	if err != nil {
+		return err
+	}
+
+	role, err := a.RoleBiz.FindRoleByName(ctx, connect.NewRequest(&rolev1.CommonNameRequest{Name: name}))
+	if err != nil {
+		return err
+	}
+
+	granted := make(map[string]bool)
+	for _, slug := range role.Msg.GetPermissions() {
+		granted[slug] = true
+	}
+
+	for _, slug := range permissions {
+		if !granted[slug] {
+			granted[slug] = true
+			role.Msg.Permissions = append(role.Msg.Permissions, slug)
+		}
+	}
+
+	return a.updateRole(ctx, role.Msg)
+}
+
+// roleRevoke removes permissions from a role.
+func roleRevoke(fs *flag.FlagSet, args []string) (action, error) {
+	name := fs.String("name", "", "name of the role")
+	slugs := fs.String("permission", "", "comma separated permission slugs")
+	if err := parse(fs, args, "name", "permission"); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.roleRevoke(ctx, *name, splitList(*slugs))
+	}, nil
+}
+
+// roleRevoke removes the permissions from the role and prints it, the last permission is kept
+// since a role without permissions does not exist.
+func (a *admin) roleRevoke(ctx context.Context, name string, slugs []string) error {
+	role, err := a.RoleBiz.FindRoleByName(ctx, connect.NewRequest(&rolev1.CommonNameRequest{Name: name}))
+	if err != nil {
+		return err
+	}
+
+	revoked := make(map[string]bool)
+	for _, slug := range slugs {
+		revoked[slug] = true
+	}
+
+	permissions := make([]string, 0, len(role.Msg.GetPermissions()))
+	for _, slug := range role.Msg.GetPermissions() {
+		if !revoked[slug] {
+			permissions = append(permissions, slug)
+		}
+	}
+
+	if len(permissions) == 0 {
+		return errors.New("refusing to revoke every permission of the role, it would delete the role")
+	}
+
+	role.Msg.Permissions = permissions
+	return a.updateRole(ctx, role.Msg)
+}
+
+// selectPermissions returns the permission slugs of the list, or all the permissions.
+func (a *admin) selectPermissions(ctx context.Context, slugs string, all bool) ([]string, error) {
+	if !all {
+		return splitList(slugs), nil
+	}
+
+	permissions := make([]string, 0)
+	for page := int64(1); ; page++ {
+		res, err := a.PermissionBiz.FindAllPermissions(ctx, connect.NewRequest(&permissionv1.FindAllPermissionsRequest{
+			Page: page,
+		}))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, per := range res.Msg.GetData() {
+			permissions = append(permissions, per.GetSlug())
+		}
+
+		if page >= res.Msg.GetTotalPage() {
+			break
+		}
+	}
+
+	if len(permissions) == 0 {
+		return nil, errors.New("no permission found, run the seed command first")
+	}
+
+	return permissions, nil
+}
+
+// updateRole replaces the permissions of the role and prints it.
+func (a *admin) updateRole(ctx context.Context, role *rolev1.Role) error {
+	_, err := a.RoleBiz.UpdateRole(ctx, connect.NewRequest(&rolev1.UpdateRoleRequest{
+		Name:        role.GetName(),
+		Permissions: role.GetPermissions(),
+	}))
+	if err != nil {
+		return err
+	}
+
+	return a.printRole(ctx, role.GetName())
+}
+
+// printRole prints the role with one row by permission.
+func (a *admin) printRole(ctx context.Context, name string) error {
+	role, err := a.RoleBiz.FindRoleByName(ctx, connect.NewRequest(&rolev1.CommonNameRequest{Name: name}))
+	if err != nil {
+		return err
+	}
+
+	permissions := append([]string(nil), role.Msg.GetPermissions()...)
+	sort.Strings(permissions)
+
+	rows := make([][]string, 0, len(permissions))
+	for _, slug := range permissions {
+		rows = append(rows, []string{role.Msg.GetName(), slug})
+	}
+
+	return a.out.print(role.Msg, []string{"ROLE", "PERMISSION"}, rows)
+}
+
+// splitList splits the comma separated list, the empty items are dropped.
+func splitList(list string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
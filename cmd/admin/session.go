@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// sessionList lists the active sessions of a user.
+func sessionList(fs *flag.FlagSet, args []string) (action, error) {
+	email := fs.String("email", "", "email of the user")
+	if err := parse(fs, args, "email"); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.sessionList(ctx, *email)
+	}, nil
+}
+
+// sessionList prints the active sessions of the user.
+func (a *admin) sessionList(ctx context.Context, email string) error {
+	user, err := a.UserBiz.FindUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := a.Session.List(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(sessions))
+	for _, info := range sessions {
+		rows = append(rows, []string{info.ID, user.Email, info.ExpiresAt.Format(time.RFC3339)})
+	}
+
+	return a.out.print(sessions, []string{"ID", "EMAIL", "EXPIRES AT"}, rows)
+}
+
+// sessionRevoke revokes a session or all the sessions of a user.
+func sessionRevoke(fs *flag.FlagSet, args []string) (action, error) {
+	email := fs.String("email", "", "email of the user")
+	id := fs.String("id", "", "id of the session")
+	all := fs.Bool("all", false, "all the sessions of the user")
+	if err := parse(fs, args, "email"); err != nil {
+		return nil, err
+	}
+
+	if *all == (*id != "") {
+		_, _ = fmt.Fprintln(fs.Output(), "one of -id and -all is required")
+		fs.Usage()
+		return nil, errFlags
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.sessionRevoke(ctx, *email, *id)
+	}, nil
+}
+
+// sessionRevoke revokes the session with the id, or all the sessions of the user,
+// and prints the revoked sessions.
+func (a *admin) sessionRevoke(ctx context.Context, email, id string) error {
+	user, err := a.UserBiz.FindUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := a.revokeSessions(ctx, user.Id, id)
+	if err != nil {
+		return err
+	}
+
+	if id != "" && len(revoked) == 0 {
+		return fmt.Errorf("session %s of %s not found", id, user.Email)
+	}
+
+	rows := make([][]string, 0, len(revoked))
+	for _, sessionID := range revoked {
+		rows = append(rows, []string{sessionID, user.Email})
+	}
+
+	return a.out.print(revoked, []string{"REVOKED", "EMAIL"}, rows)
+}
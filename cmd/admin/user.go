@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/golang-jwt/jwt/v4"
+	userv1 "github.com/xdorro/proto-base-project/proto-gen-go/user/v1"
+
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	userservice "github.com/xdorro/golang-grpc-base-project/internal/module/user/service"
+)
+
+// passwordFlags defines the password flags, the password is read from stdin to keep it
+// out of the process list and the shell history.
+func passwordFlags(fs *flag.FlagSet) func() (string, error) {
+	password := fs.String("password", "", "password")
+	stdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin")
+
+	return func() (string, error) {
+		if *stdin == (*password != "") {
+			_, _ = fmt.Fprintln(fs.Output(), "one of -password and -password-stdin is required")
+			fs.Usage()
+			return "", errFlags
+		}
+
+		if !*stdin {
+			return *password, nil
+		}
+
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			return "", fmt.Errorf("read password from stdin: %w", err)
+		}
+
+		return line, nil
+	}
+}
+
+// userCreate creates a user.
+func userCreate(fs *flag.FlagSet, args []string) (action, error) {
+	name := fs.String("name", "", "name of the user")
+	email := fs.String("email", "", "email of the user")
+	role := fs.String("role", "", "role of the user, user by default")
+	password := passwordFlags(fs)
+	if err := parse(fs, args, "name", "email"); err != nil {
+		return nil, err
+	}
+
+	pwd, err := password()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.userCreate(ctx, *name, *email, pwd, *role)
+	}, nil
+}
+
+// userCreate creates the user and prints it.
+func (a *admin) userCreate(ctx context.Context, name, email, password, role string) error {
+	res, err := a.UserBiz.CreateUser(ctx, connect.NewRequest(&userv1.CreateUserRequest{
+		Name:     name,
+		Email:    email,
+		Password: password,
+		Role:     role,
+	}))
+	if err != nil {
+		return err
+	}
+
+	user := &userv1.User{Id: res.Msg.GetData(), Name: name, Email: email, Role: strings.ToLower(role)}
+	if user.Role == "" {
+		user.Role = "user"
+	}
+
+	return a.out.print(user, []string{"ID", "NAME", "EMAIL", "ROLE"}, [][]string{
+		{user.GetId(), user.GetName(), user.GetEmail(), user.GetRole()},
+	})
+}
+
+// userResetPassword replaces the password of a user and revokes its sessions.
+func userResetPassword(fs *flag.FlagSet, args []string) (action, error) {
+	email := fs.String("email", "", "email of the user")
+	keep := fs.Bool("keep-sessions", false, "keep the sessions of the user")
+	password := passwordFlags(fs)
+	if err := parse(fs, args, "email"); err != nil {
+		return nil, err
+	}
+
+	pwd, err := password()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.userResetPassword(ctx, *email, pwd, *keep)
+	}, nil
+}
+
+// userResetPassword replaces the password of the user, revokes its sessions unless kept
+// and prints the revoked sessions.
+func (a *admin) userResetPassword(ctx context.Context, email, password string, keep bool) error {
+	user, err := a.UserBiz.FindUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	// the sessions are listed before the reset revokes them
+	revoked := make([]string, 0)
+	if !keep {
+		sessions, err := a.Session.List(ctx, user.Id)
+		if err != nil {
+			return err
+		}
+
+		for _, info := range sessions {
+			revoked = append(revoked, info.ID)
+		}
+	}
+
+	if err = a.UserBiz.ResetPassword(ctx, user.Id, password, keep); err != nil {
+		return err
+	}
+
+	result := map[string]any{"id": user.Id, "email": user.Email, "revoked_sessions": revoked}
+	return a.out.print(result, []string{"ID", "EMAIL", "REVOKED SESSIONS"}, [][]string{
+		{user.Id, user.Email, fmt.Sprint(len(revoked))},
+	})
+}
+
+// userAssignRole replaces the role of a user.
+func userAssignRole(fs *flag.FlagSet, args []string) (action, error) {
+	email := fs.String("email", "", "email of the user")
+	role := fs.String("role", "", "role of the user")
+	if err := parse(fs, args, "email", "role"); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.userAssignRole(ctx, *email, *role)
+	}, nil
+}
+
+// userAssignRole replaces the role of the user and prints it.
+func (a *admin) userAssignRole(ctx context.Context, email, role string) error {
+	user, err := a.UserBiz.FindUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.UserBiz.UpdateUser(ctx, connect.NewRequest(&userv1.UpdateUserRequest{
+		Id:   user.Id,
+		Role: &role,
+	}))
+	if err != nil {
+		return err
+	}
+
+	result := &userv1.User{Id: user.Id, Name: user.Name, Email: user.Email, Role: strings.ToLower(role)}
+	return a.out.print(result, []string{"ID", "EMAIL", "ROLE"}, [][]string{
+		{result.GetId(), result.GetEmail(), result.GetRole()},
+	})
+}
+
+// userList lists all the users.
+func userList(fs *flag.FlagSet, args []string) (action, error) {
+	if err := parse(fs, args); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.userList(ctx)
+	}, nil
+}
+
+// userList prints all the users, page by page.
+func (a *admin) userList(ctx context.Context) error {
+	users := make([]*userv1.User, 0)
+	for page := int64(1); ; page++ {
+		res, err := a.UserBiz.FindAllUsers(ctx, connect.NewRequest(&userv1.FindAllUsersRequest{Page: page}))
+		if err != nil {
+			return err
+		}
+
+		users = append(users, res.Msg.GetData()...)
+		if page >= res.Msg.GetTotalPage() {
+			break
+		}
+	}
+
+	rows := make([][]string, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, []string{user.GetId(), user.GetName(), user.GetEmail(), user.GetRole()})
+	}
+
+	return a.out.print(users, []string{"ID", "NAME", "EMAIL", "ROLE"}, rows)
+}
+
+// revokeSessions revokes the session of the user with the id, or all its sessions, tracked or not.
+// It returns the ids of the revoked sessions, from the informative list of the active sessions.
+func (a *admin) revokeSessions(ctx context.Context, subject, id string) ([]string, error) {
+	sessions, err := a.Session.List(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked := make([]string, 0, len(sessions))
+	if id == "" {
+		if err = a.Session.RevokeAll(ctx, subject); err != nil {
+			return nil, err
+		}
+
+		for _, info := range sessions {
+			revoked = append(revoked, info.ID)
+		}
+
+		return revoked, nil
+	}
+
+	for _, info := range sessions {
+		if info.ID != id {
+			continue
+		}
+
+		err = a.Session.Revoke(ctx, &jwt.RegisteredClaims{
+			ID:        info.ID,
+			Subject:   info.Subject,
+			ExpiresAt: jwt.NewNumericDate(info.ExpiresAt),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		revoked = append(revoked, info.ID)
+	}
+
+	return revoked, nil
+}
+
+// userSuspend suspends a user and revokes its sessions.
+func userSuspend(fs *flag.FlagSet, args []string) (action, error) {
+	return userState(fs, args, usermodel.StatusSuspended, "reason")
+}
+
+// userReactivate reactivates a suspended or deactivated user.
+func userReactivate(fs *flag.FlagSet, args []string) (action, error) {
+	return userState(fs, args, usermodel.StatusActive)
+}
+
+// userDeactivate deactivates a user and revokes its sessions.
+func userDeactivate(fs *flag.FlagSet, args []string) (action, error) {
+	return userState(fs, args, usermodel.StatusDeactivated, "reason")
+}
+
+// userState moves a user to the state, the required flags are checked with the email.
+func userState(fs *flag.FlagSet, args []string, to int32, required ...string) (action, error) {
+	email := fs.String("email", "", "email of the user")
+	reason := fs.String("reason", "", "reason of the transition")
+	if err := parse(fs, args, append([]string{"email"}, required...)...); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a *admin) error {
+		return a.userState(ctx, *email, to, *reason)
+	}, nil
+}
+
+// userState moves the user to the state and prints its state.
+func (a *admin) userState(ctx context.Context, email string, to int32, reason string) error {
+	user, err := a.UserBiz.FindUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	data, err := a.UserBiz.ChangeState(ctx, user.Id, to, reason)
+	if err != nil {
+		return err
+	}
+
+	state := userservice.UserStateToAPI(data)
+	return a.out.print(state, []string{"ID", "EMAIL", "STATE", "REASON"}, [][]string{
+		{state.Id, state.Email, state.State, state.Reason},
+	})
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	userbiz "github.com/xdorro/golang-grpc-base-project/internal/module/user/biz"
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// testSession records the revocations of the listed sessions.
+type testSession struct {
+	session.ISession
+
+	sessions   []*session.Info
+	revoked    []string
+	revokedAll []string
+}
+
+func (s *testSession) List(_ context.Context, _ string) ([]*session.Info, error) {
+	return s.sessions, nil
+}
+
+func (s *testSession) Revoke(_ context.Context, claims *jwt.RegisteredClaims) error {
+	s.revoked = append(s.revoked, claims.ID)
+	return nil
+}
+
+func (s *testSession) RevokeAll(_ context.Context, subject string) error {
+	s.revokedAll = append(s.revokedAll, subject)
+	return nil
+}
+
+func TestRevokeSessions(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	sessions := []*session.Info{
+		{ID: "s1", Subject: "u1", ExpiresAt: expiresAt},
+		{ID: "s2", Subject: "u1", ExpiresAt: expiresAt},
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		want           []string
+		wantRevoked    []string
+		wantRevokedAll []string
+	}{
+		{name: "all", want: []string{"s1", "s2"}, wantRevokedAll: []string{"u1"}},
+		{name: "one", id: "s2", want: []string{"s2"}, wantRevoked: []string{"s2"}},
+		{name: "unknown", id: "s3", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &testSession{sessions: sessions}
+			a := &admin{Session: s}
+
+			got, err := a.revokeSessions(context.Background(), "u1", tt.id)
+			if err != nil {
+				t.Fatalf("revokeSessions() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("revokeSessions() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(s.revoked, tt.wantRevoked) || !reflect.DeepEqual(s.revokedAll, tt.wantRevokedAll) {
+				t.Errorf("revokeSessions() revoked %v and all of %v, want %v and %v",
+					s.revoked, s.revokedAll, tt.wantRevoked, tt.wantRevokedAll)
+			}
+		})
+	}
+}
+
+// testUserBiz resets the password of a single user.
+type testUserBiz struct {
+	userbiz.IUserBiz
+
+	user         *usermodel.User
+	keepSessions []bool
+}
+
+func (b *testUserBiz) FindUserByEmail(_ context.Context, _ string) (*usermodel.User, error) {
+	return b.user, nil
+}
+
+func (b *testUserBiz) ResetPassword(_ context.Context, _, _ string, keepSessions bool) error {
+	b.keepSessions = append(b.keepSessions, keepSessions)
+	return nil
+}
+
+func TestUserResetPassword(t *testing.T) {
+	user := &usermodel.User{BaseModel: utils.BaseModel{Id: "u1"}, Email: "a@example.com"}
+
+	tests := []struct {
+		keep bool
+		want string
+	}{
+		{keep: false, want: `"revoked_sessions":["s1"]`},
+		{keep: true, want: `"revoked_sessions":[]`},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		biz := &testUserBiz{user: user}
+		a := &admin{
+			Session: &testSession{sessions: []*session.Info{{ID: "s1", Subject: "u1"}}},
+			UserBiz: biz,
+			out:     &printer{w: &out, format: formatJSON},
+		}
+
+		if err := a.userResetPassword(context.Background(), user.Email, "correct horse", tt.keep); err != nil {
+			t.Fatalf("userResetPassword(keep %v) error = %v", tt.keep, err)
+		}
+
+		// the sessions are revoked by the biz layer, unless kept
+		if want := []bool{tt.keep}; !reflect.DeepEqual(biz.keepSessions, want) {
+			t.Errorf("userResetPassword(keep %v) reset with keepSessions %v, want %v", tt.keep, biz.keepSessions, want)
+		}
+		if got := strings.Join(strings.Fields(out.String()), ""); !strings.Contains(got, tt.want) {
+			t.Errorf("userResetPassword(keep %v) = %s, want %s", tt.keep, got, tt.want)
+		}
+	}
+}
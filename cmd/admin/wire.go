@@ -0,0 +1,52 @@
+//go:build wireinject
+// +build wireinject
+
+// The build tag makes sure the stub is not built in the final build.
+package main
+
+import (
+	"net/http"
+
+	"github.com/google/wire"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/internal/interceptor"
+	auditmodule "github.com/xdorro/golang-grpc-base-project/internal/module/audit"
+	authmodule "github.com/xdorro/golang-grpc-base-project/internal/module/auth"
+	permissionmodule "github.com/xdorro/golang-grpc-base-project/internal/module/permission"
+	privacymodule "github.com/xdorro/golang-grpc-base-project/internal/module/privacy"
+	retentionmodule "github.com/xdorro/golang-grpc-base-project/internal/module/retention"
+	rolemodule "github.com/xdorro/golang-grpc-base-project/internal/module/role"
+	usermodule "github.com/xdorro/golang-grpc-base-project/internal/module/user"
+	"github.com/xdorro/golang-grpc-base-project/internal/service"
+	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/health"
+	"github.com/xdorro/golang-grpc-base-project/pkg/lifecycle"
+	"github.com/xdorro/golang-grpc-base-project/pkg/redis"
+	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+)
+
+func initAdmin(cfg *config.Config) *admin {
+	wire.Build(
+		http.NewServeMux,
+		lifecycle.ProviderLifecycleSet,
+		health.ProviderHealthSet,
+		repo.ProviderRepoSet,
+		redis.ProviderRedisSet,
+		session.ProviderSessionSet,
+		rolemodule.ProviderModuleSet,
+		permissionmodule.ProviderModuleSet,
+		usermodule.ProviderModuleSet,
+		authmodule.ProviderModuleSet,
+		auditmodule.ProviderModuleSet,
+		retentionmodule.ProviderModuleSet,
+		privacymodule.ProviderModuleSet,
+		casbin.ProviderCasbinSet,
+		interceptor.ProviderInterceptorSet,
+		service.ProviderServiceSet,
+		wire.Struct(new(admin), "Lifecycle", "Casbin", "Session", "UserBiz", "RoleBiz", "PermissionBiz", "RetentionBiz", "PrivacyBiz", "Service"),
+	)
+
+	return &admin{}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
@@ -15,6 +16,9 @@ import (
 	"github.com/xdorro/golang-grpc-base-project/pkg/logger"
 )
 
+// cfg is the config of the environment.
+var cfg *config.Config
+
 func init() {
 	// -env is option for command line
 	env := flag.String("env", "local", "environment")
@@ -23,7 +27,7 @@ func init() {
 	flag.Parse()
 
 	logger.NewLogger(*logFile)
-	config.NewConfig(*env)
+	cfg = config.NewConfig(*env)
 }
 
 func main() {
@@ -31,7 +35,7 @@ func main() {
 	signal.Notify(exit, os.Interrupt, syscall.SIGTERM)
 
 	// New server
-	srv := initServer()
+	srv := initServer(cfg)
 
 	// Run server
 	go func(srv server.IServer) {
@@ -41,11 +45,36 @@ func main() {
 		}
 	}(srv)
 
-	<-exit
-	if err := srv.Close(); err != nil {
-		log.Err(err).Msg("Failed to close server")
-		return
+	sig := <-exit
+	log.Info().
+		Str("signal", sig.String()).
+		Msg("Shutting down, send the signal again to force the exit")
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- srv.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			log.Err(err).Msg("Failed to close server")
+			forceExit()
+		}
+	case sig = <-exit:
+		log.Warn().Str("signal", sig.String()).Msg("Forcing exit")
+		forceExit()
+	case <-time.After(cfg.Shutdown.Timeout):
+		log.Error().Dur("timeout", cfg.Shutdown.Timeout).Msg("Graceful shutdown timed out, forcing exit")
+		forceExit()
 	}
 
 	log.Info().Msg("Graceful shutdown complete")
+	_ = logger.Close()
+}
+
+// forceExit exits without waiting for the pending shutdown.
+func forceExit() {
+	_ = logger.Close()
+	os.Exit(1)
 }
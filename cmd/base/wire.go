@@ -7,29 +7,48 @@ package main
 import (
 	"github.com/google/wire"
 
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/internal/gateway"
 	"github.com/xdorro/golang-grpc-base-project/internal/interceptor"
+	auditmodule "github.com/xdorro/golang-grpc-base-project/internal/module/audit"
 	authmodule "github.com/xdorro/golang-grpc-base-project/internal/module/auth"
 	permissionmodule "github.com/xdorro/golang-grpc-base-project/internal/module/permission"
+	privacymodule "github.com/xdorro/golang-grpc-base-project/internal/module/privacy"
+	retentionmodule "github.com/xdorro/golang-grpc-base-project/internal/module/retention"
 	rolemodule "github.com/xdorro/golang-grpc-base-project/internal/module/role"
 	usermodule "github.com/xdorro/golang-grpc-base-project/internal/module/user"
 	"github.com/xdorro/golang-grpc-base-project/internal/server"
 	"github.com/xdorro/golang-grpc-base-project/internal/service"
 	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/certs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/health"
+	"github.com/xdorro/golang-grpc-base-project/pkg/lifecycle"
 	"github.com/xdorro/golang-grpc-base-project/pkg/redis"
 	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
 )
 
-func initServer() server.IServer {
+func initServer(cfg *config.Config) server.IServer {
 	wire.Build(
+		lifecycle.ProviderLifecycleSet,
+		certs.ProviderCertsSet,
+		tracing.ProviderTracingSet,
+		health.ProviderHealthSet,
 		repo.ProviderRepoSet,
 		redis.ProviderRedisSet,
+		session.ProviderSessionSet,
 		rolemodule.ProviderModuleSet,
 		permissionmodule.ProviderModuleSet,
 		usermodule.ProviderModuleSet,
 		authmodule.ProviderModuleSet,
+		auditmodule.ProviderModuleSet,
+		retentionmodule.ProviderModuleSet,
+		privacymodule.ProviderModuleSet,
 		casbin.ProviderCasbinSet,
 		interceptor.ProviderInterceptorSet,
 		service.ProviderServiceSet,
+		gateway.ProviderGatewaySet,
 		server.ProviderServerSet,
 	)
 
@@ -7,11 +7,19 @@
 package main
 
 import (
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/internal/gateway"
 	"github.com/xdorro/golang-grpc-base-project/internal/interceptor"
+	"github.com/xdorro/golang-grpc-base-project/internal/module/audit/biz"
+	"github.com/xdorro/golang-grpc-base-project/internal/module/audit/service"
 	"github.com/xdorro/golang-grpc-base-project/internal/module/auth/biz"
 	"github.com/xdorro/golang-grpc-base-project/internal/module/auth/service"
 	"github.com/xdorro/golang-grpc-base-project/internal/module/permission/biz"
 	"github.com/xdorro/golang-grpc-base-project/internal/module/permission/service"
+	"github.com/xdorro/golang-grpc-base-project/internal/module/privacy/biz"
+	"github.com/xdorro/golang-grpc-base-project/internal/module/privacy/service"
+	"github.com/xdorro/golang-grpc-base-project/internal/module/retention/biz"
+	"github.com/xdorro/golang-grpc-base-project/internal/module/retention/service"
 	"github.com/xdorro/golang-grpc-base-project/internal/module/role/biz"
 	"github.com/xdorro/golang-grpc-base-project/internal/module/role/service"
 	"github.com/xdorro/golang-grpc-base-project/internal/module/user/biz"
@@ -19,37 +27,72 @@ import (
 	"github.com/xdorro/golang-grpc-base-project/internal/server"
 	"github.com/xdorro/golang-grpc-base-project/internal/service"
 	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/certs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/health"
+	"github.com/xdorro/golang-grpc-base-project/pkg/lifecycle"
 	"github.com/xdorro/golang-grpc-base-project/pkg/redis"
 	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
 	"net/http"
 )
 
 // Injectors from wire.go:
 
-func initServer() server.IServer {
+func initServer(cfg *config.Config) server.IServer {
+	iLifecycle := lifecycle.NewLifecycle(cfg)
+	iManager := certs.NewManager(cfg)
+	iTracing := tracing.NewTracing(cfg)
+	iChecker := health.NewChecker(cfg)
 	serveMux := http.NewServeMux()
-	iRepo := repo.NewRepo()
+	iRepo := repo.NewRepo(cfg)
 	option := &casbin.Option{
-		Repo: iRepo,
+		Config: cfg,
+		Repo:   iRepo,
 	}
 	iCasbin := casbin.NewCasbin(option)
-	iRedis := redis.NewRedis()
-	interceptorOption := &interceptor.Option{
-		Casbin: iCasbin,
+	iRedis := redis.NewRedis(cfg)
+	sessionOption := &session.Option{
+		Config: cfg,
 		Redis:  iRedis,
+	}
+	iSession := session.NewSession(sessionOption)
+	auditbizOption := &auditbiz.Option{
+		Config: cfg,
 		Repo:   iRepo,
 	}
+	iAuditBiz := auditbiz.NewBiz(auditbizOption)
+	interceptorOption := &interceptor.Option{
+		Config:    cfg,
+		Lifecycle: iLifecycle,
+		Casbin:    iCasbin,
+		Redis:     iRedis,
+		Repo:      iRepo,
+		Session:   iSession,
+		AuditBiz:  iAuditBiz,
+	}
 	iInterceptor := interceptor.NewInterceptor(interceptorOption)
+	auditserviceOption := &auditservice.Option{
+		AuditBiz: iAuditBiz,
+	}
+	iAuditService := auditservice.NewService(auditserviceOption)
 	userbizOption := &userbiz.Option{
-		Repo: iRepo,
+		Config:  cfg,
+		Repo:    iRepo,
+		Casbin:  iCasbin,
+		Session: iSession,
 	}
 	iUserBiz := userbiz.NewBiz(userbizOption)
 	userserviceOption := &userservice.Option{
 		UserBiz: iUserBiz,
 	}
 	iUserService := userservice.NewService(userserviceOption)
+	iUserBulkService := userservice.NewBulkService(userserviceOption)
+	iUserStateService := userservice.NewStateService(userserviceOption)
 	authbizOption := &authbiz.Option{
-		Repo: iRepo,
+		Config:  cfg,
+		Repo:    iRepo,
+		Session: iSession,
 	}
 	iAuthBiz := authbiz.NewBiz(authbizOption)
 	authserviceOption := &authservice.Option{
@@ -57,7 +100,8 @@ func initServer() server.IServer {
 	}
 	iAuthService := authservice.NewService(authserviceOption)
 	permissionbizOption := &permissionbiz.Option{
-		Repo: iRepo,
+		Repo:   iRepo,
+		Casbin: iCasbin,
 	}
 	iPermissionBiz := permissionbiz.NewBiz(permissionbizOption)
 	permissionserviceOption := &permissionservice.Option{
@@ -66,26 +110,73 @@ func initServer() server.IServer {
 	iPermissionService := permissionservice.NewService(permissionserviceOption)
 	rolebizOption := &rolebiz.Option{
 		Casbin: iCasbin,
+		Repo:   iRepo,
 	}
 	iRoleBiz := rolebiz.NewBiz(rolebizOption)
 	roleserviceOption := &roleservice.Option{
 		RoleBiz: iRoleBiz,
 	}
 	iRoleService := roleservice.NewService(roleserviceOption)
+	retentionbizOption := &retentionbiz.Option{
+		Config:    cfg,
+		Lifecycle: iLifecycle,
+		Repo:      iRepo,
+		Redis:     iRedis,
+		Casbin:    iCasbin,
+		Session:   iSession,
+	}
+	iRetentionBiz := retentionbiz.NewBiz(retentionbizOption)
+	retentionserviceOption := &retentionservice.Option{
+		RetentionBiz: iRetentionBiz,
+	}
+	iRetentionService := retentionservice.NewService(retentionserviceOption)
+	privacybizOption := &privacybiz.Option{
+		Repo:         iRepo,
+		Casbin:       iCasbin,
+		Session:      iSession,
+		AuditBiz:     iAuditBiz,
+		RetentionBiz: iRetentionBiz,
+	}
+	iPrivacyBiz := privacybiz.NewBiz(privacybizOption)
+	privacyserviceOption := &privacyservice.Option{
+		PrivacyBiz: iPrivacyBiz,
+	}
+	iPrivacyService := privacyservice.NewService(privacyserviceOption)
 	serviceOption := &service.Option{
+		Config:            cfg,
+		Lifecycle:         iLifecycle,
 		Mux:               serveMux,
 		Interceptor:       iInterceptor,
 		Repo:              iRepo,
 		Redis:             iRedis,
+		Casbin:            iCasbin,
+		Health:            iChecker,
+		AuditService:      iAuditService,
 		UserService:       iUserService,
+		UserBulkService:   iUserBulkService,
+		UserStateService:  iUserStateService,
 		AuthService:       iAuthService,
 		PermissionService: iPermissionService,
 		RoleService:       iRoleService,
+		RetentionService:  iRetentionService,
+		PrivacyService:    iPrivacyService,
 	}
 	iService := service.NewService(serviceOption)
+	gatewayOption := &gateway.Option{
+		Config: cfg,
+		Mux:    serveMux,
+	}
+	iGateway := gateway.NewGateway(gatewayOption)
 	serverOption := &server.Option{
-		Mux:     serveMux,
-		Service: iService,
+		Config:    cfg,
+		Lifecycle: iLifecycle,
+		Casbin:    iCasbin,
+		Certs:     iManager,
+		Tracing:   iTracing,
+		Health:    iChecker,
+		Mux:       serveMux,
+		Service:   iService,
+		Gateway:   iGateway,
 	}
 	iServer := server.NewServer(serverOption)
 	return iServer
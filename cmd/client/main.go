@@ -0,0 +1,265 @@
+// Command client is an example of the Go client, it calls every RPC against a running instance:
+//
+//	go run ./cmd/client -url http://localhost:8088 -email admin@gmail.com -password 123456
+//
+// It creates a permission, a role and a user with a random suffix, reads, updates and deletes
+// them, imports an invited user and exports the users, lists the audit trail, then revokes its
+// tokens. It exits with 1 if a call failed.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	authv1 "github.com/xdorro/proto-base-project/proto-gen-go/auth/v1"
+	permissionv1 "github.com/xdorro/proto-base-project/proto-gen-go/permission/v1"
+	rolev1 "github.com/xdorro/proto-base-project/proto-gen-go/role/v1"
+	userv1 "github.com/xdorro/proto-base-project/proto-gen-go/user/v1"
+
+	auditv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1"
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/client"
+)
+
+// example runs the calls and counts the failures.
+type example struct {
+	client   client.IClient
+	failures int
+}
+
+func main() {
+	url := flag.String("url", "http://localhost:8088", "server url")
+	email := flag.String("email", "admin@gmail.com", "login email")
+	password := flag.String("password", "123456", "login password")
+	protocol := flag.String("protocol", client.ProtocolConnect, "connect, grpc or grpcweb")
+	timeout := flag.Duration("timeout", time.Minute, "timeout of the whole run")
+	flag.Parse()
+
+	log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}).
+		With().
+		Timestamp().
+		Logger()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	e := &example{
+		client: client.NewClient(&client.Option{
+			BaseURL:  *url,
+			Protocol: *protocol,
+		}),
+	}
+	e.run(ctx, *email, *password)
+
+	if e.failures > 0 {
+		log.Error().Int("failures", e.failures).Msg("Example finished with failures")
+		cancel()
+		os.Exit(1)
+	}
+
+	log.Info().Msg("Example finished")
+}
+
+// run calls every RPC.
+func (e *example) run(ctx context.Context, email, password string) {
+	suffix := uuid.NewString()[:8]
+	c := e.client
+
+	// auth, the tokens are refreshed by the client afterwards
+	login, err := c.Auth().Login(ctx, connect.NewRequest(&authv1.LoginRequest{Email: email, Password: password}))
+	if e.check("Login", nil, err) != nil {
+		return
+	}
+	c.SetTokens(login.Msg.GetAccessToken(), login.Msg.GetRefreshToken(), time.Unix(login.Msg.GetTokenExpire(), 0))
+
+	// revoke last, after the deferred deletes
+	defer func() {
+		e.check("RevokeToken", nil, c.Logout(ctx))
+	}()
+
+	refreshed, err := c.Auth().RefreshToken(ctx, connect.NewRequest(&authv1.TokenRequest{
+		Token: login.Msg.GetRefreshToken(),
+	}))
+	if e.check("RefreshToken", nil, err) != nil {
+		return
+	}
+	c.SetTokens(refreshed.Msg.GetAccessToken(), refreshed.Msg.GetRefreshToken(),
+		time.Unix(refreshed.Msg.GetTokenExpire(), 0))
+
+	// permissions
+	slug := fmt.Sprintf("/example.v1.ExampleService/Ping%s", suffix)
+	created, err := c.Permission().CreatePermission(ctx, connect.NewRequest(&permissionv1.CreatePermissionRequest{
+		Name:        "Ping " + suffix,
+		Slug:        slug,
+		RequireAuth: true,
+	}))
+	if e.check("CreatePermission", created, err) == nil {
+		permissionID := created.Msg.GetData()
+
+		res, err := c.Permission().FindAllPermissions(ctx, connect.NewRequest(&permissionv1.FindAllPermissionsRequest{Page: 1}))
+		e.check("FindAllPermissions", res, err)
+
+		found, err := c.Permission().FindPermissionByID(ctx, connect.NewRequest(&permissionv1.CommonUUIDRequest{Id: permissionID}))
+		e.check("FindPermissionByID", found, err)
+
+		name := "Ping " + suffix + " updated"
+		updated, err := c.Permission().UpdatePermission(ctx, connect.NewRequest(&permissionv1.UpdatePermissionRequest{
+			Id:   permissionID,
+			Name: &name,
+		}))
+		e.check("UpdatePermission", updated, err)
+
+		defer func() {
+			deleted, err := c.Permission().DeletePermission(ctx, connect.NewRequest(&permissionv1.CommonUUIDRequest{Id: permissionID}))
+			e.check("DeletePermission", deleted, err)
+		}()
+	}
+
+	// roles
+	role := "example-" + suffix
+	createdRole, err := c.Role().CreateRole(ctx, connect.NewRequest(&rolev1.CreateRoleRequest{
+		Name:        role,
+		Permissions: []string{slug},
+	}))
+	if e.check("CreateRole", createdRole, err) == nil {
+		res, err := c.Role().FindAllRoles(ctx, connect.NewRequest(&rolev1.FindAllRolesRequest{}))
+		e.check("FindAllRoles", res, err)
+
+		found, err := c.Role().FindRoleByName(ctx, connect.NewRequest(&rolev1.CommonNameRequest{Name: role}))
+		e.check("FindRoleByName", found, err)
+
+		updated, err := c.Role().UpdateRole(ctx, connect.NewRequest(&rolev1.UpdateRoleRequest{
+			Name:        role,
+			Permissions: []string{slug},
+		}))
+		e.check("UpdateRole", updated, err)
+
+		defer func() {
+			deleted, err := c.Role().DeleteRole(ctx, connect.NewRequest(&rolev1.CommonNameRequest{Name: role}))
+			e.check("DeleteRole", deleted, err)
+		}()
+	}
+
+	// users
+	userEmail := fmt.Sprintf("example-%s@example.com", suffix)
+	createdUser, err := c.User().CreateUser(ctx, connect.NewRequest(&userv1.CreateUserRequest{
+		Name:     "Example " + suffix,
+		Email:    userEmail,
+		Password: "Example-" + uuid.NewString(),
+		Role:     role,
+		Status:   1,
+	}))
+	if e.check("CreateUser", createdUser, err) == nil {
+		var userID string
+		users, err := c.User().FindAllUsers(ctx, connect.NewRequest(&userv1.FindAllUsersRequest{Page: 1}))
+		if e.check("FindAllUsers", users, err) == nil {
+			for _, user := range users.Msg.GetData() {
+				if user.GetEmail() == userEmail {
+					userID = user.GetId()
+				}
+			}
+		}
+
+		if userID == "" {
+			log.Warn().Str("email", userEmail).Msg("Created user not on the first page, skipping its calls")
+		} else {
+			found, err := c.User().FindUserByID(ctx, connect.NewRequest(&userv1.CommonUUIDRequest{Id: userID}))
+			e.check("FindUserByID", found, err)
+
+			name := "Example " + suffix + " updated"
+			updated, err := c.User().UpdateUser(ctx, connect.NewRequest(&userv1.UpdateUserRequest{
+				Id:   userID,
+				Name: &name,
+			}))
+			e.check("UpdateUser", updated, err)
+
+			deleted, err := c.User().DeleteUser(ctx, connect.NewRequest(&userv1.CommonUUIDRequest{Id: userID}))
+			e.check("DeleteUser", deleted, err)
+		}
+	}
+
+	// bulk import of an invited user, accepted then exported
+	e.bulk(ctx, suffix, role)
+
+	// audit trail of the calls above
+	audits, err := c.Audit().FindAllAudits(ctx, connect.NewRequest(&auditv1.FindAllAuditsRequest{Page: 1}))
+	e.check("FindAllAudits", audits, err)
+}
+
+// bulk imports a user without password, accepts its invite, exports the users and deletes it.
+func (e *example) bulk(ctx context.Context, suffix, role string) {
+	c := e.client
+	userEmail := fmt.Sprintf("example-bulk-%s@example.com", suffix)
+
+	stream := c.UserBulk().ImportUsers(ctx)
+	err := stream.Send(&userbulkv1.ImportUsersRequest{
+		Options: &userbulkv1.ImportOptions{},
+		Users:   []*userbulkv1.ImportUser{{Name: "Example bulk " + suffix, Email: userEmail, Role: role}},
+	})
+	if e.check("ImportUsers", nil, err) != nil {
+		_, _ = stream.CloseAndReceive()
+		return
+	}
+
+	// the response has the invite token, it is not logged
+	imported, err := stream.CloseAndReceive()
+	if e.check("ImportUsers", nil, err) != nil {
+		return
+	}
+
+	result := imported.Msg.Results[0]
+	if result.Action != userbulkv1.ActionCreate {
+		e.check("ImportUsers", nil, fmt.Errorf("row %d failed: %s", result.Row, result.Error))
+		return
+	}
+
+	defer func() {
+		deleted, err := c.User().DeleteUser(ctx, connect.NewRequest(&userv1.CommonUUIDRequest{Id: result.Id}))
+		e.check("DeleteUser", deleted, err)
+	}()
+
+	accepted, err := c.UserBulk().AcceptInvite(ctx, connect.NewRequest(&userbulkv1.AcceptInviteRequest{
+		Email:    userEmail,
+		Token:    result.InviteToken,
+		Password: "Example-" + uuid.NewString(),
+	}))
+	e.check("AcceptInvite", accepted, err)
+
+	exported, err := c.UserBulk().ExportUsers(ctx, connect.NewRequest(&userbulkv1.ExportUsersRequest{}))
+	if e.check("ExportUsers", nil, err) != nil {
+		return
+	}
+
+	count := 0
+	for exported.Receive() {
+		count += len(exported.Msg().Users)
+	}
+	if e.check("ExportUsers", nil, exported.Err()) == nil {
+		log.Info().Int("users", count).Msg("Exported users")
+	}
+	_ = exported.Close()
+}
+
+// check logs the result of the call, the tokens are never logged. It returns the error.
+func (e *example) check(rpc string, response any, err error) error {
+	if err != nil {
+		e.failures++
+		log.Err(err).Str("rpc", rpc).Str("code", connect.CodeOf(err).String()).Msg("Call failed")
+		return err
+	}
+
+	event := log.Info().Str("rpc", rpc)
+	if res, ok := response.(interface{ Any() any }); ok {
+		event = event.Interface("response", res.Any())
+	}
+	event.Msg("Call succeeded")
+
+	return nil
+}
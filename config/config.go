@@ -4,17 +4,43 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
-// NewConfig initializes the config
-func NewConfig(env string) {
+// NewConfig reads and validates the config of the environment, then watches the config file
+// to reload the safe settings. The env vars override the keys, e.g. DATABASE_URL for database.url,
+// and the ${provider:ref} references are resolved by the secret providers.
+func NewConfig(env string) *Config {
+	cfg, err := Check(env)
+	if err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+	cfg.reload = &reloader{}
+	cfg.applyLogLevel()
+
+	// reload the safe settings when the file changes, and resolve the secret references
+	// periodically for the rotated secrets
+	cfg.watch(viper.ConfigFileUsed())
+
+	log.Info().
+		Str("goarch", runtime.GOARCH).
+		Str("goos", runtime.GOOS).
+		Str("version", runtime.Version()).
+		Msg("Runtime information")
+
+	return cfg
+}
+
+// Check reads and validates the config of the environment, without watching it.
+func Check(env string) (*Config, error) {
 	viper.AutomaticEnv()
 
 	// Replace env key
-	// viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	pwd, _ := os.Getwd()
 	viper.AddConfigPath(".")
@@ -25,17 +51,38 @@ func NewConfig(env string) {
 	viper.SetConfigName(env)
 
 	if err := viper.ReadInConfig(); err != nil {
-		log.Fatal().Err(err).Msg("Error reading config file")
+		return nil, fmt.Errorf("error reading config file: %w", err)
 	}
 
 	// set default config
 	defaultConfig()
 
-	log.Info().
-		Str("goarch", runtime.GOARCH).
-		Str("goos", runtime.GOOS).
-		Str("version", runtime.Version()).
-		Msg("Runtime information")
+	return load(env)
+}
+
+// load unmarshals the config of the environment, resolves its secrets and validates it.
+func load(env string) (*Config, error) {
+	cfg := &Config{Env: env}
+	if err := viper.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	var err error
+	if cfg.references, err = cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// applyLogLevel sets the global log level, the level is validated.
+func (c *Config) applyLogLevel() {
+	level, _ := zerolog.ParseLevel(c.Log.Level)
+	zerolog.SetGlobalLevel(level)
 }
 
 // defaultConfig is the default configuration for the application.
@@ -44,18 +91,122 @@ func defaultConfig() {
 	viper.SetDefault("app.name", "golang-grpc-base-project")
 	viper.SetDefault("app.port", 8088)
 	viper.SetDefault("app.debug", true)
-	viper.SetDefault("APP_SECRET", "your-256-bit-secret")
+
+	// ADMIN
+	viper.SetDefault("admin.enabled", true)
+	viper.SetDefault("admin.address", "127.0.0.1:6060")
+	viper.SetDefault("admin.auth", "none")
+
+	// CORS
+	viper.SetDefault("cors.profiles.local.allowed_origins", []string{"*"})
+	viper.SetDefault("cors.profiles.local.max_age", "10m")
+
+	// TLS
+	viper.SetDefault("tls.enabled", false)
+	viper.SetDefault("tls.min_version", "1.2")
+	viper.SetDefault("tls.client_auth", "none")
+	viper.SetDefault("tls.reload_interval", "1m")
+	viper.SetDefault("tls.dev_dir", "certs")
+	viper.SetDefault("tls.dev_hosts", []string{"localhost", "127.0.0.1"})
 
 	// LOG
-	viper.SetDefault("LOG_PAYLOAD", true)
-	viper.SetDefault("LOG_FILE_URL", "logs/data.log")
+	viper.SetDefault("log.level", "debug")
+	viper.SetDefault("log.payload", true)
+	viper.SetDefault("log.mode", "full")
+	viper.SetDefault("log.redact.fields", []string{"password", "token", "accessToken", "refreshToken"})
+	viper.SetDefault("log.redact.headers", []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"})
+
+	// RATE LIMIT
+	viper.SetDefault("ratelimit.enabled", true)
+	viper.SetDefault("ratelimit.store", "redis")
+	viper.SetDefault("ratelimit.key", "user")
+	viper.SetDefault("ratelimit.limit", 0)
+	viper.SetDefault("ratelimit.window", "1m")
+
+	// AUTH
+	viper.SetDefault("auth.revocation_fail_mode", "closed")
+
+	// PASSWORD
+	viper.SetDefault("password.min_length", 8)
+	viper.SetDefault("password.breached_file", "config/breached_passwords.txt")
+
+	// BULK
+	viper.SetDefault("bulk.max_rows", 10000)
+	viper.SetDefault("bulk.batch_size", 500)
+	viper.SetDefault("bulk.invite_ttl", "168h")
+
+	// AUDIT
+	viper.SetDefault("audit.enabled", true)
+	viper.SetDefault("audit.retention", "2160h")
+	viper.SetDefault("audit.prefixes", []string{"Create", "Update", "Delete", "Revoke", "Import", "Suspend", "Reactivate", "Deactivate",
+		"Restore", "Purge", "RunRetention", "ExportMyData", "RequestErasure", "ApproveErasure", "RejectErasure",
+	})
+
+	// RETENTION
+	viper.SetDefault("retention.enabled", false)
+	viper.SetDefault("retention.interval", "24h")
+	viper.SetDefault("retention.user_age", "720h")
+	viper.SetDefault("retention.permission_age", "720h")
+	viper.SetDefault("retention.mode", "anonymize")
+	viper.SetDefault("retention.batch_size", 500)
+
+	// SEEDER
+	viper.SetDefault("seeder.service", false)
+	viper.SetDefault("seeder.reconcile", false)
+	viper.SetDefault("seeder.dry_run", true)
+	viper.SetDefault("seeder.admin_role", "admin")
+	viper.SetDefault("seeder.protect", false)
+
+	// HEALTH
+	viper.SetDefault("health.interval", "10s")
+	viper.SetDefault("health.timeout", "2s")
+	viper.SetDefault("health.drain_delay", "5s")
+
+	// GATEWAY
+	viper.SetDefault("gateway.enabled", true)
+	viper.SetDefault("gateway.docs", true)
+
+	// SHUTDOWN
+	viper.SetDefault("shutdown.timeout", "45s")
+	viper.SetDefault("shutdown.unready_timeout", "10s")
+	viper.SetDefault("shutdown.drain_timeout", "15s")
+	viper.SetDefault("shutdown.jobs_timeout", "10s")
+	viper.SetDefault("shutdown.flush_timeout", "5s")
+	viper.SetDefault("shutdown.close_timeout", "5s")
+
+	// TRACING
+	viper.SetDefault("tracing.exporter", "none")
+	viper.SetDefault("tracing.endpoint", "localhost:4317")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.file", "logs/traces.json")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
 
 	// DATABASE
 	viper.SetDefault("database.url", "mongodb://localhost:27017")
 	viper.SetDefault("database.name", "base")
+	viper.SetDefault("database.connect_backoff.attempts", 10)
+	viper.SetDefault("database.connect_backoff.initial", "1s")
+	viper.SetDefault("database.connect_backoff.max", "30s")
+	viper.SetDefault("database.server_selection_timeout", "5s")
 
 	// REDIS
 	viper.SetDefault("redis.url", "localhost:6379")
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
+	viper.SetDefault("redis.timeout", "1s")
+	viper.SetDefault("redis.max_retries", 1)
+	viper.SetDefault("redis.min_retry_backoff", "8ms")
+	viper.SetDefault("redis.max_retry_backoff", "512ms")
+	viper.SetDefault("redis.max_connect_backoff", "30s")
+	viper.SetDefault("redis.breaker.threshold", 5)
+	viper.SetDefault("redis.breaker.cooldown", "30s")
+
+	// CASBIN
+	viper.SetDefault("casbin.name", "roles")
+
+	// SECRETS
+	viper.SetDefault("secrets.refresh", "5m")
+	viper.SetDefault("secrets.dir", "/var/run/secrets")
+	viper.SetDefault("secrets.vault.mount", "secret")
+	viper.SetDefault("secrets.vault.timeout", "5s")
 }
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// redacted replaces the secrets of the config dump.
+const redacted = "[REDACTED]"
+
+// Redacted returns the config as a map keyed like the config file, the settings tagged
+// redact:"true" are replaced and the passwords of the settings tagged redact:"url" are removed.
+func (c *Config) Redacted() map[string]any {
+	result, _ := redactValue(reflect.ValueOf(c).Elem(), "").(map[string]any)
+	return result
+}
+
+// redactValue returns the dump of the value, redacted with the redact tag of its field.
+func redactValue(v reflect.Value, tag string) any {
+	switch {
+	case tag == "true":
+		if v.IsZero() {
+			return v.Interface()
+		}
+
+		return redacted
+	case tag == "url" && v.Kind() == reflect.String:
+		return redactURL(v.String())
+	}
+
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+
+		return redactValue(v.Elem(), "")
+	case reflect.Struct:
+		result := make(map[string]any)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if !field.IsExported() || name == "" || name == "-" {
+				continue
+			}
+
+			result[name] = redactValue(v.Field(i), field.Tag.Get("redact"))
+		}
+
+		return result
+	case reflect.Slice:
+		result := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			result = append(result, redactValue(v.Index(i), ""))
+		}
+
+		return result
+	case reflect.Map:
+		result := make(map[string]any)
+		for _, key := range v.MapKeys() {
+			result[key.String()] = redactValue(v.MapIndex(key), "")
+		}
+
+		return result
+	default:
+		return v.Interface()
+	}
+}
+
+// redactURL returns the uri without its password.
+func redactURL(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return redacted
+	}
+
+	return u.Redacted()
+}
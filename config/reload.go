@@ -0,0 +1,144 @@
+package config
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// reloader keeps the functions notified of the config changes.
+type reloader struct {
+	mu  sync.Mutex
+	fns []func(cfg *Config)
+}
+
+// OnReload registers a function called with the new config when the config file changes.
+// Only the settings safe to change at runtime must be applied: the log level is applied by the
+// config, the payload logging and the rate limits by the interceptor.
+func (c *Config) OnReload(fn func(cfg *Config)) {
+	if c.reload == nil {
+		return
+	}
+
+	c.reload.mu.Lock()
+	c.reload.fns = append(c.reload.fns, fn)
+	c.reload.mu.Unlock()
+}
+
+// watch reloads the config when the file changes and resolves the secret references every
+// refresh interval. Viper is not safe for concurrent use, so both run on the same goroutine.
+func (c *Config) watch(file string) {
+	changes := make(chan string)
+	if err := watchFile(file, changes); err != nil {
+		log.Error().Err(err).Str("file", file).Msg("Failed to watch the config file")
+	}
+
+	var ticks <-chan time.Time
+	if c.Secrets.Refresh > 0 && c.references > 0 {
+		ticks = time.NewTicker(c.Secrets.Refresh).C
+	}
+
+	go c.loop(changes, ticks, c.onChange, c.refreshSecrets)
+}
+
+// loop runs the reloads of the changed files and the refreshes of the secrets one at a time.
+func (c *Config) loop(changes <-chan string, ticks <-chan time.Time,
+	reload func(file string), refresh func(credentials string) string,
+) {
+	credentials := c.credentials()
+	for changes != nil || ticks != nil {
+		select {
+		case file, ok := <-changes:
+			if !ok {
+				changes = nil
+				continue
+			}
+
+			reload(file)
+		case _, ok := <-ticks:
+			if !ok {
+				ticks = nil
+				continue
+			}
+
+			credentials = refresh(credentials)
+		}
+	}
+}
+
+// watchFile sends the config file to the changes when it is written, or when the file it links
+// to changes, the directory is watched since the editors and the config maps replace the file.
+func watchFile(file string, changes chan<- string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	file = filepath.Clean(file)
+	if err = watcher.Add(filepath.Dir(file)); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	realFile, _ := filepath.EvalSymlinks(file)
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				current, _ := filepath.EvalSymlinks(file)
+				written := filepath.Clean(event.Name) == file && event.Op&(fsnotify.Write|fsnotify.Create) != 0
+				if written || (current != "" && current != realFile) {
+					realFile = current
+					changes <- file
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				log.Warn().Err(err).Msg("Config watcher error")
+			}
+		}
+	}()
+
+	return nil
+}
+
+// onChange reads and loads the changed config, an invalid config is logged and ignored.
+func (c *Config) onChange(file string) {
+	if err := viper.ReadInConfig(); err != nil {
+		log.Error().Str("file", file).Msg(err.Error())
+		return
+	}
+
+	cfg, err := load(c.Env)
+	if err != nil {
+		log.Error().Str("file", file).Msg(err.Error())
+		return
+	}
+
+	cfg.applyLogLevel()
+	if c.JWT.rotate(&cfg.JWT) {
+		log.Info().Msg("JWT keys rotated")
+	}
+
+	c.reload.mu.Lock()
+	fns := append([]func(cfg *Config){}, c.reload.fns...)
+	c.reload.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(cfg)
+	}
+
+	log.Info().Str("file", file).Msg("Config reloaded")
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoopSerializes(t *testing.T) {
+	c := &Config{}
+	changes := make(chan string)
+	ticks := make(chan time.Time)
+
+	var running atomic.Int32
+	var calls []string
+	enter := func(name string) {
+		if running.Add(1) != 1 {
+			t.Errorf("%s runs concurrently with another reload", name)
+		}
+		time.Sleep(time.Millisecond)
+		calls = append(calls, name)
+		running.Add(-1)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.loop(changes, ticks,
+			func(string) { enter("reload") },
+			func(credentials string) string { enter("refresh"); return credentials },
+		)
+	}()
+
+	go func() {
+		for n := 0; n < 5; n++ {
+			ticks <- time.Now()
+		}
+		close(ticks)
+	}()
+	for n := 0; n < 5; n++ {
+		changes <- "local.toml"
+	}
+	close(changes)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("loop() does not return once the channels are closed")
+	}
+
+	if len(calls) != 10 {
+		t.Errorf("loop() ran %d reloads, want 10", len(calls))
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "local.toml")
+	if err := os.WriteFile(file, []byte("[app]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan string, 16)
+	if err := watchFile(file, changes); err != nil {
+		t.Fatalf("watchFile() error = %v", err)
+	}
+
+	// another file of the directory is ignored
+	if err := os.WriteFile(filepath.Join(filepath.Dir(file), "other.toml"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("[app]\nport = 5001\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-changes:
+		if got != file {
+			t.Errorf("watchFile() sent %q, want %q", got, file)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchFile() did not send the written file")
+	}
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/secret"
+)
+
+const (
+	// EnvLocal is the environment allowed to run with the sample secrets.
+	EnvLocal = "local"
+
+	// sampleKeyFingerprint is the SHA-256 of the sample public key of config/local.toml.
+	sampleKeyFingerprint = "9e6a92d571b044cc83909f1fb4c61be22e63951e6de33fd96c385a95be6b101c"
+	// samplePassword is the MongoDB password of config/local.toml and docker-compose.yml.
+	samplePassword = "123456aA@"
+
+	// resolveTimeout bounds the resolution of all the secret references.
+	resolveTimeout = 30 * time.Second
+)
+
+// newResolver returns the resolver of the secret references with the configured providers.
+func newResolver(cfg *Secrets) *secret.Resolver {
+	r := secret.NewResolver()
+	r.Register(secret.ProviderFile, secret.NewFileProvider(cfg.Dir))
+	r.Register(secret.ProviderEnv, secret.NewEnvProvider())
+	r.Register(secret.ProviderStub, secret.NewStubProvider(cfg.Stub))
+
+	if cfg.Vault.Address != "" {
+		r.Register(secret.ProviderVault, secret.NewVaultProvider(&secret.VaultOption{
+			Address:   cfg.Vault.Address,
+			Token:     cfg.Vault.Token,
+			Namespace: cfg.Vault.Namespace,
+			Mount:     cfg.Vault.Mount,
+			Timeout:   cfg.Vault.Timeout,
+		}))
+	}
+
+	return r
+}
+
+// resolveSecrets replaces the secret references of the string settings, except the secrets settings.
+// It returns the number of settings holding a reference.
+func (c *Config) resolveSecrets() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+
+	r := newResolver(&c.Secrets)
+	count := 0
+
+	var walk func(path string, v reflect.Value) error
+	walk = func(path string, v reflect.Value) error {
+		switch v.Kind() {
+		case reflect.Pointer:
+			if v.IsNil() {
+				return nil
+			}
+
+			return walk(path, v.Elem())
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				field := v.Type().Field(i)
+				if !field.IsExported() || field.Type == reflect.TypeOf(Secrets{}) {
+					continue
+				}
+
+				if err := walk(path+"."+field.Name, v.Field(i)); err != nil {
+					return err
+				}
+			}
+		case reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i), v.Index(i)); err != nil {
+					return err
+				}
+			}
+		case reflect.String:
+			if !secret.HasReference(v.String()) {
+				return nil
+			}
+
+			val, err := r.Resolve(ctx, v.String())
+			if err != nil {
+				return fmt.Errorf("%s: %w", path[1:], err)
+			}
+
+			v.SetString(val)
+			count++
+		}
+
+		return nil
+	}
+
+	if err := walk("", reflect.ValueOf(c)); err != nil {
+		return count, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return count, nil
+}
+
+// refreshSecrets resolves the secret references again, the rotated JWT keys are applied right
+// away, the rotated database and redis credentials need a restart. It returns the digest of the
+// credentials in use.
+func (c *Config) refreshSecrets(credentials string) string {
+	cfg, err := load(c.Env)
+	if err != nil {
+		log.Warn().Msg(err.Error())
+		return credentials
+	}
+
+	if c.JWT.rotate(&cfg.JWT) {
+		log.Info().Msg("JWT keys rotated")
+	}
+
+	if next := cfg.credentials(); next != credentials {
+		log.Warn().Msg("Database or redis credentials rotated, restart to apply them")
+		return next
+	}
+
+	return credentials
+}
+
+// credentials returns a digest of the database and redis credentials.
+func (c *Config) credentials() string {
+	sum := sha256.Sum256([]byte(c.Database.URL + "\x00" + c.Redis.Password))
+	return hex.EncodeToString(sum[:])
+}
+
+// isSampleKey returns true if the key is the sample key of the repository.
+func isSampleKey(key *rsa.PublicKey) bool {
+	if key == nil {
+		return false
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return false
+	}
+
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]) == sampleKeyFingerprint
+}
+
+// isSamplePassword returns true if the MongoDB uri has the sample password of the repository.
+func isSamplePassword(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil || u.User == nil {
+		return false
+	}
+
+	password, _ := u.User.Password()
+	return password == samplePassword
+}
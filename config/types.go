@@ -0,0 +1,398 @@
+package config
+
+import (
+	"crypto/rsa"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// Config is the configuration of the application.
+type Config struct {
+	// Env is the environment of the config file.
+	Env string `mapstructure:"-"`
+
+	App       App       `mapstructure:"app"`
+	Admin     Admin     `mapstructure:"admin"`
+	TLS       TLS       `mapstructure:"tls"`
+	CORS      CORS      `mapstructure:"cors"`
+	Gateway   Gateway   `mapstructure:"gateway"`
+	Log       Log       `mapstructure:"log"`
+	Health    Health    `mapstructure:"health"`
+	Shutdown  Shutdown  `mapstructure:"shutdown"`
+	Tracing   Tracing   `mapstructure:"tracing"`
+	RateLimit RateLimit `mapstructure:"ratelimit"`
+	Auth      Auth      `mapstructure:"auth"`
+	Password  Password  `mapstructure:"password"`
+	Bulk      Bulk      `mapstructure:"bulk"`
+	Audit     Audit     `mapstructure:"audit"`
+	Retention Retention `mapstructure:"retention"`
+	Seeder    Seeder    `mapstructure:"seeder"`
+	Database  Database  `mapstructure:"database"`
+	Redis     Redis     `mapstructure:"redis"`
+	Casbin    Casbin    `mapstructure:"casbin"`
+	JWT       JWT       `mapstructure:"jwt"`
+	Secrets   Secrets   `mapstructure:"secrets"`
+
+	// reload notifies the subscribers of the config changes.
+	reload *reloader
+	// references is the number of settings holding a secret reference.
+	references int
+}
+
+// App is the application configuration.
+type App struct {
+	Name string `mapstructure:"name"`
+	Port int    `mapstructure:"port"`
+	// Debug serves pprof on the admin server.
+	Debug bool `mapstructure:"debug"`
+	// TrustedProxies are the IP addresses or CIDR ranges of the reverse proxies, only their
+	// X-Forwarded-For and X-Real-IP headers are read for the client ip.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+
+	// proxies are the parsed trusted proxies.
+	proxies []*net.IPNet
+}
+
+// Proxies returns the networks of the trusted proxies.
+func (a *App) Proxies() []*net.IPNet {
+	return a.proxies
+}
+
+// Admin is the admin server configuration, serving pprof, metrics, health and debug endpoints.
+type Admin struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Address string `mapstructure:"address"`
+	// Auth is none, basic or token, the health endpoints are never authenticated.
+	Auth     string `mapstructure:"auth"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password" redact:"true"`
+	Token    string `mapstructure:"token" redact:"true"`
+}
+
+// TLS is the TLS configuration of the application server.
+type TLS struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// MinVersion is 1.2 or 1.3.
+	MinVersion string `mapstructure:"min_version"`
+	// ClientAuth is none, request or require, the client certificates are verified with the client CA.
+	ClientAuth   string `mapstructure:"client_auth"`
+	ClientCAFile string `mapstructure:"client_ca_file"`
+	// ReloadInterval is the interval between two checks of the certificate files.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+	// DevCA generates a local CA, the server and a client certificate in DevDir when they are missing.
+	DevCA    bool     `mapstructure:"dev_ca"`
+	DevDir   string   `mapstructure:"dev_dir"`
+	DevHosts []string `mapstructure:"dev_hosts"`
+	// Identities maps the client certificate identities to the casbin subjects, the other identities
+	// are not authenticated.
+	Identities []*TLSIdentity `mapstructure:"identities"`
+}
+
+// TLSIdentity maps a client certificate identity, its first URI or DNS SAN or its CN, to a casbin subject.
+type TLSIdentity struct {
+	Identity string `mapstructure:"identity"`
+	Subject  string `mapstructure:"subject"`
+}
+
+// CORS is the CORS configuration, a profile per environment.
+type CORS struct {
+	// Profile is the profile in use, the profile of the environment when empty.
+	Profile  string                  `mapstructure:"profile"`
+	Profiles map[string]*CORSProfile `mapstructure:"profiles"`
+}
+
+// CORSProfile is the CORS policy of an environment.
+type CORSProfile struct {
+	// AllowedOrigins are the origins, e.g. https://app.example.com, https://*.example.com or *.
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	// AllowCredentials lets the browsers send the cookies and the authorization header.
+	AllowCredentials bool `mapstructure:"allow_credentials"`
+	// AllowedHeaders are added to the headers of the Connect, gRPC-Web and application protocols.
+	AllowedHeaders []string `mapstructure:"allowed_headers"`
+	// MaxAge is how long the browsers cache the preflight responses.
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// ActiveProfile returns the name and the CORS profile in use, nil if it does not exist.
+func (c *CORS) ActiveProfile(env string) (string, *CORSProfile) {
+	name := c.Profile
+	if name == "" {
+		name = env
+	}
+
+	return name, c.Profiles[name]
+}
+
+// Log is the log configuration.
+type Log struct {
+	// Level is the minimum level of the logs, reloaded on change.
+	Level string `mapstructure:"level"`
+	// Payload enables the payload log of the RPCs, reloaded on change.
+	Payload bool `mapstructure:"payload"`
+	// Mode is the default payload log mode: full, metadata or none, reloaded on change.
+	Mode string `mapstructure:"mode"`
+	// Procedures overrides the payload log mode per procedure, reloaded on change.
+	Procedures []*LogProcedure `mapstructure:"procedures"`
+	Redact     Redact          `mapstructure:"redact"`
+}
+
+// LogProcedure is the payload log mode of a procedure.
+type LogProcedure struct {
+	Procedure string `mapstructure:"procedure"`
+	Mode      string `mapstructure:"mode"`
+}
+
+// Redact lists the fields and headers removed from the payload logs.
+type Redact struct {
+	Fields  []string `mapstructure:"fields"`
+	Headers []string `mapstructure:"headers"`
+}
+
+// Health is the health checker configuration.
+type Health struct {
+	Interval   time.Duration `mapstructure:"interval"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	DrainDelay time.Duration `mapstructure:"drain_delay"`
+}
+
+// Gateway is the REST gateway configuration.
+type Gateway struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Docs serves the OpenAPI document and the Swagger UI.
+	Docs bool `mapstructure:"docs"`
+}
+
+// Shutdown is the graceful shutdown configuration, the phases run in order.
+type Shutdown struct {
+	// Timeout forces the exit when the graceful shutdown takes longer.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// UnreadyTimeout bounds reporting not serving, including the health drain delay.
+	UnreadyTimeout time.Duration `mapstructure:"unready_timeout"`
+	// DrainTimeout bounds the in-flight requests.
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+	// JobsTimeout bounds the background jobs, they are canceled afterwards.
+	JobsTimeout time.Duration `mapstructure:"jobs_timeout"`
+	// FlushTimeout bounds flushing the logs and the traces.
+	FlushTimeout time.Duration `mapstructure:"flush_timeout"`
+	// CloseTimeout bounds closing the stores.
+	CloseTimeout time.Duration `mapstructure:"close_timeout"`
+}
+
+// Tracing is the tracing configuration.
+type Tracing struct {
+	// Exporter is none, stdout, file or otlp.
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	File        string  `mapstructure:"file"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// RateLimit is the rate limit configuration, reloaded on change except the store.
+type RateLimit struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Store is redis or memory.
+	Store string `mapstructure:"store"`
+	// Key is the default key: user, api_key or ip.
+	Key string `mapstructure:"key"`
+	// Limit is the default quota, 0 disables it.
+	Limit      int64            `mapstructure:"limit"`
+	Window     time.Duration    `mapstructure:"window"`
+	Procedures []*RateLimitRule `mapstructure:"procedures"`
+}
+
+// RateLimitRule is the rate limit quota of a procedure.
+type RateLimitRule struct {
+	Procedure string        `mapstructure:"procedure"`
+	Limit     int64         `mapstructure:"limit"`
+	Window    time.Duration `mapstructure:"window"`
+	Key       string        `mapstructure:"key"`
+}
+
+// Auth is the authentication configuration.
+type Auth struct {
+	// RevocationFailMode is open or closed.
+	RevocationFailMode string `mapstructure:"revocation_fail_mode"`
+}
+
+// Password is the password policy configuration.
+type Password struct {
+	MinLength    int    `mapstructure:"min_length"`
+	BreachedFile string `mapstructure:"breached_file"`
+}
+
+// Bulk is the bulk user import and export configuration.
+type Bulk struct {
+	// MaxRows is the maximum number of rows of an import.
+	MaxRows int `mapstructure:"max_rows"`
+	// BatchSize is the number of rows written or read at once.
+	BatchSize int `mapstructure:"batch_size"`
+	// InviteTTL is the validity of the invite tokens of the users imported without a password.
+	InviteTTL time.Duration `mapstructure:"invite_ttl"`
+}
+
+// Audit is the audit log configuration.
+type Audit struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Retention time.Duration `mapstructure:"retention"`
+	Prefixes  []string      `mapstructure:"prefixes"`
+}
+
+// Retention is the retention of the soft deleted users and permissions.
+type Retention struct {
+	// Enabled runs the retention job in the background of the server.
+	Enabled bool `mapstructure:"enabled"`
+	// Interval is the time between two runs.
+	Interval time.Duration `mapstructure:"interval"`
+	// UserAge is the age of the deletion after which a user is purged or anonymized.
+	UserAge time.Duration `mapstructure:"user_age"`
+	// PermissionAge is the age of the deletion after which a permission is purged.
+	PermissionAge time.Duration `mapstructure:"permission_age"`
+	// Mode is purge to remove the users, or anonymize to keep them without their personal data.
+	Mode string `mapstructure:"mode"`
+	// BatchSize is the number of records removed at once.
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// Seeder is the permission seeder configuration.
+type Seeder struct {
+	// Service enables the seeder at startup.
+	Service bool `mapstructure:"service"`
+	// Reconcile enables removing orphaned permissions and applying renames.
+	Reconcile bool `mapstructure:"reconcile"`
+	// DryRun only logs the reconciliation report without applying it.
+	DryRun bool `mapstructure:"dry_run"`
+	// Renames maps an old procedure slug to its new slug.
+	Renames []*SeederRename `mapstructure:"renames"`
+	// AdminRole is the role granted the sensitive procedures, e.g. the bulk import, when they are seeded.
+	AdminRole string `mapstructure:"admin_role"`
+	// Protect re-applies the require_auth and the grant of the sensitive procedures seeded before,
+	// overriding the changes of the operators.
+	Protect bool `mapstructure:"protect"`
+}
+
+// SeederRename is a procedure rename rule.
+type SeederRename struct {
+	From string `mapstructure:"from"`
+	To   string `mapstructure:"to"`
+}
+
+// Database is the MongoDB configuration, a zero pool setting keeps the uri or driver default.
+type Database struct {
+	URL                    string        `mapstructure:"url" redact:"url"`
+	Name                   string        `mapstructure:"name"`
+	MinPoolSize            uint64        `mapstructure:"min_pool_size"`
+	MaxPoolSize            uint64        `mapstructure:"max_pool_size"`
+	MaxConnIdleTime        time.Duration `mapstructure:"max_conn_idle_time"`
+	ConnectTimeout         time.Duration `mapstructure:"connect_timeout"`
+	ServerSelectionTimeout time.Duration `mapstructure:"server_selection_timeout"`
+	SocketTimeout          time.Duration `mapstructure:"socket_timeout"`
+	ReadPreference         string        `mapstructure:"read_preference"`
+	WriteConcern           WriteConcern  `mapstructure:"write_concern"`
+	ConnectBackoff         utils.Backoff `mapstructure:"connect_backoff"`
+}
+
+// WriteConcern is the MongoDB write concern.
+type WriteConcern struct {
+	// W is majority, a number of nodes or a tag set.
+	W       string        `mapstructure:"w"`
+	Journal bool          `mapstructure:"journal"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Redis is the redis configuration.
+type Redis struct {
+	// URL is a space separated list of addresses.
+	URL               string        `mapstructure:"url"`
+	Password          string        `mapstructure:"password" redact:"true"`
+	DB                int           `mapstructure:"db"`
+	Timeout           time.Duration `mapstructure:"timeout"`
+	MaxRetries        int           `mapstructure:"max_retries"`
+	MinRetryBackoff   time.Duration `mapstructure:"min_retry_backoff"`
+	MaxRetryBackoff   time.Duration `mapstructure:"max_retry_backoff"`
+	MaxConnectBackoff time.Duration `mapstructure:"max_connect_backoff"`
+	Breaker           Breaker       `mapstructure:"breaker"`
+}
+
+// Breaker is the redis circuit breaker configuration.
+type Breaker struct {
+	Threshold int           `mapstructure:"threshold"`
+	Cooldown  time.Duration `mapstructure:"cooldown"`
+}
+
+// Casbin is the casbin configuration.
+type Casbin struct {
+	Name  string `mapstructure:"name"`
+	Model string `mapstructure:"model"`
+}
+
+// JWT is the token signing configuration, the PEM keys are parsed by the validation
+// and replaced when the secrets are rotated.
+type JWT struct {
+	SignKey   string `mapstructure:"signKey" redact:"true"`
+	VerifyKey string `mapstructure:"verifyKey"`
+
+	keys atomic.Pointer[jwtKeys]
+}
+
+// jwtKeys are the parsed keys of the tokens.
+type jwtKeys struct {
+	pem    string
+	sign   *rsa.PrivateKey
+	verify *rsa.PublicKey
+}
+
+// PrivateKey returns the key signing the tokens.
+func (j *JWT) PrivateKey() *rsa.PrivateKey {
+	if keys := j.keys.Load(); keys != nil {
+		return keys.sign
+	}
+
+	return nil
+}
+
+// PublicKey returns the key verifying the tokens.
+func (j *JWT) PublicKey() *rsa.PublicKey {
+	if keys := j.keys.Load(); keys != nil {
+		return keys.verify
+	}
+
+	return nil
+}
+
+// rotate replaces the keys by the keys of the new config, it returns true if they changed.
+func (j *JWT) rotate(next *JWT) bool {
+	current, keys := j.keys.Load(), next.keys.Load()
+	if keys == nil || (current != nil && current.pem == keys.pem) {
+		return false
+	}
+
+	j.keys.Store(keys)
+
+	return true
+}
+
+// Secrets is the secret providers configuration, the ${provider:ref} references
+// of the other settings are resolved with the file, env, vault and stub providers.
+type Secrets struct {
+	// Refresh is the interval between two resolutions, for rotated secrets, 0 disables it.
+	Refresh time.Duration `mapstructure:"refresh"`
+	// Dir is the directory of the relative file references.
+	Dir   string            `mapstructure:"dir"`
+	Vault Vault             `mapstructure:"vault"`
+	Stub  map[string]string `mapstructure:"stub" redact:"true"`
+}
+
+// Vault is the HashiCorp Vault KV v2 configuration.
+type Vault struct {
+	Address   string        `mapstructure:"address"`
+	Token     string        `mapstructure:"token" redact:"true"`
+	Namespace string        `mapstructure:"namespace"`
+	Mount     string        `mapstructure:"mount"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+}
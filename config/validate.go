@@ -0,0 +1,231 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// validator collects the violations of the config, so they are all reported at once.
+type validator struct {
+	violations []string
+}
+
+// check records the violation when the condition is false.
+func (v *validator) check(ok bool, key, format string, args ...any) {
+	if !ok {
+		v.violations = append(v.violations, key+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+// oneOf records a violation when the value is not one of the allowed values.
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+
+	v.check(false, key, "%q must be one of %s", value, strings.Join(allowed, ", "))
+}
+
+// Validate checks the config and parses the keys, all the violations are reported in the error.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// app
+	v.check(c.App.Name != "", "app.name", "is required")
+	v.check(c.App.Port > 0 && c.App.Port < 65536, "app.port", "%d is not a valid port", c.App.Port)
+	proxies, err := utils.ParseProxies(c.App.TrustedProxies)
+	v.check(err == nil, "app.trusted_proxies", "%v", err)
+	c.App.proxies = proxies
+
+	// admin
+	if c.Admin.Enabled {
+		_, _, err := net.SplitHostPort(c.Admin.Address)
+		v.check(err == nil, "admin.address", "%q must be host:port", c.Admin.Address)
+		v.oneOf("admin.auth", c.Admin.Auth, "none", "basic", "token")
+		v.check(c.Admin.Auth != "basic" || (c.Admin.Username != "" && c.Admin.Password != ""),
+			"admin.username", "the username and password are required by the basic auth")
+		v.check(c.Admin.Auth != "token" || c.Admin.Token != "", "admin.token", "is required by the token auth")
+		v.check(c.Env == EnvLocal || c.Admin.Auth != "none" || isLoopback(c.Admin.Address),
+			"admin.auth", "is required in the %s environment when the admin server is not bound to loopback", c.Env)
+	}
+
+	// tls
+	if c.TLS.Enabled {
+		v.check(c.TLS.DevCA || (c.TLS.CertFile != "" && c.TLS.KeyFile != ""),
+			"tls.cert_file", "the certificate and key files are required without tls.dev_ca")
+		v.oneOf("tls.min_version", c.TLS.MinVersion, "1.2", "1.3")
+		v.oneOf("tls.client_auth", c.TLS.ClientAuth, "none", "request", "require")
+		v.check(c.TLS.ClientAuth == "none" || c.TLS.DevCA || c.TLS.ClientCAFile != "",
+			"tls.client_ca_file", "is required to verify the client certificates")
+		v.check(c.TLS.ReloadInterval > 0, "tls.reload_interval", "must be positive")
+		v.check(!c.TLS.DevCA || c.Env == EnvLocal, "tls.dev_ca", "must not be used in the %s environment", c.Env)
+		for i, id := range c.TLS.Identities {
+			key := fmt.Sprintf("tls.identities[%d]", i)
+			v.check(id.Identity != "", key+".identity", "is required")
+			v.check(id.Subject != "", key+".subject", "is required")
+		}
+	}
+
+	// cors
+	name, profile := c.CORS.ActiveProfile(c.Env)
+	key := "cors.profiles." + name
+	v.check(profile != nil, key, "is required")
+	if profile != nil {
+		for i, origin := range profile.AllowedOrigins {
+			v.check(validOrigin(origin), fmt.Sprintf("%s.allowed_origins[%d]", key, i),
+				"%q must be *, scheme://host[:port] or scheme://*.domain", origin)
+			v.check(origin != "*" || !profile.AllowCredentials, key+".allow_credentials",
+				"must be false when every origin is allowed")
+		}
+		v.check(profile.MaxAge >= 0, key+".max_age", "must not be negative")
+	}
+
+	// log
+	_, err = zerolog.ParseLevel(c.Log.Level)
+	v.check(err == nil, "log.level", "%q is not a valid level", c.Log.Level)
+	v.oneOf("log.mode", c.Log.Mode, "full", "metadata", "none")
+	for i, p := range c.Log.Procedures {
+		key := fmt.Sprintf("log.procedures[%d]", i)
+		v.check(p.Procedure != "", key+".procedure", "is required")
+		v.oneOf(key+".mode", p.Mode, "full", "metadata", "none")
+	}
+
+	// health
+	v.check(c.Health.Interval > 0, "health.interval", "must be positive")
+	v.check(c.Health.Timeout > 0, "health.timeout", "must be positive")
+	v.check(c.Health.DrainDelay >= 0, "health.drain_delay", "must not be negative")
+
+	// shutdown
+	v.check(c.Shutdown.UnreadyTimeout > c.Health.DrainDelay, "shutdown.unready_timeout",
+		"must be greater than health.drain_delay %s", c.Health.DrainDelay)
+	v.check(c.Shutdown.DrainTimeout > 0, "shutdown.drain_timeout", "must be positive")
+	v.check(c.Shutdown.JobsTimeout > 0, "shutdown.jobs_timeout", "must be positive")
+	v.check(c.Shutdown.FlushTimeout > 0, "shutdown.flush_timeout", "must be positive")
+	v.check(c.Shutdown.CloseTimeout > 0, "shutdown.close_timeout", "must be positive")
+	phases := c.Shutdown.UnreadyTimeout + c.Shutdown.DrainTimeout + c.Shutdown.JobsTimeout +
+		c.Shutdown.FlushTimeout + c.Shutdown.CloseTimeout
+	v.check(c.Shutdown.Timeout >= phases, "shutdown.timeout",
+		"must be at least the sum of the phase timeouts %s", phases)
+
+	// tracing
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "stdout", "file", "otlp")
+	v.check(c.Tracing.Exporter != "otlp" || c.Tracing.Endpoint != "", "tracing.endpoint", "is required by the otlp exporter")
+	v.check(c.Tracing.Exporter != "file" || c.Tracing.File != "", "tracing.file", "is required by the file exporter")
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"tracing.sample_ratio", "%v must be between 0 and 1", c.Tracing.SampleRatio)
+
+	// rate limit
+	v.oneOf("ratelimit.store", c.RateLimit.Store, "redis", "memory")
+	v.oneOf("ratelimit.key", c.RateLimit.Key, "user", "api_key", "ip")
+	v.check(c.RateLimit.Limit >= 0, "ratelimit.limit", "must not be negative")
+	v.check(c.RateLimit.Window >= 0, "ratelimit.window", "must not be negative")
+	for i, r := range c.RateLimit.Procedures {
+		key := fmt.Sprintf("ratelimit.procedures[%d]", i)
+		v.check(r.Procedure != "", key+".procedure", "is required")
+		v.check(r.Limit >= 0, key+".limit", "must not be negative")
+		v.check(r.Window >= 0, key+".window", "must not be negative")
+		if r.Key != "" {
+			v.oneOf(key+".key", r.Key, "user", "api_key", "ip")
+		}
+	}
+
+	// auth, password and audit
+	v.oneOf("auth.revocation_fail_mode", c.Auth.RevocationFailMode, "open", "closed")
+	v.check(c.Password.MinLength >= 0, "password.min_length", "must not be negative")
+	v.check(c.Audit.Retention >= 0, "audit.retention", "must not be negative")
+
+	// bulk
+	v.check(c.Bulk.MaxRows > 0, "bulk.max_rows", "must be positive")
+	v.check(c.Bulk.BatchSize > 0, "bulk.batch_size", "must be positive")
+	v.check(c.Bulk.InviteTTL > 0, "bulk.invite_ttl", "must be positive")
+
+	// retention
+	v.oneOf("retention.mode", c.Retention.Mode, "purge", "anonymize")
+	v.check(c.Retention.Interval > 0, "retention.interval", "must be positive")
+	v.check(c.Retention.UserAge > 0, "retention.user_age", "must be positive")
+	v.check(c.Retention.PermissionAge > 0, "retention.permission_age", "must be positive")
+	v.check(c.Retention.BatchSize > 0, "retention.batch_size", "must be positive")
+
+	// seeder
+	v.check(c.Seeder.AdminRole != "", "seeder.admin_role", "is required")
+
+	// database
+	v.check(c.Database.URL != "", "database.url", "is required")
+	v.check(c.Database.Name != "", "database.name", "is required")
+	v.check(c.Database.MaxPoolSize == 0 || c.Database.MinPoolSize <= c.Database.MaxPoolSize,
+		"database.min_pool_size", "must not be greater than database.max_pool_size")
+	if c.Database.ReadPreference != "" {
+		_, err = readpref.ModeFromString(c.Database.ReadPreference)
+		v.check(err == nil, "database.read_preference", "%q is not a valid read preference", c.Database.ReadPreference)
+	}
+	v.check(c.Database.ConnectBackoff.Attempts >= 0, "database.connect_backoff.attempts", "must not be negative")
+
+	// redis
+	v.check(strings.TrimSpace(c.Redis.URL) != "", "redis.url", "is required")
+	v.check(c.Redis.Timeout > 0, "redis.timeout", "must be positive")
+	v.check(c.Redis.MaxConnectBackoff > 0, "redis.max_connect_backoff", "must be positive")
+	v.check(c.Redis.Breaker.Threshold > 0, "redis.breaker.threshold", "must be positive")
+	v.check(c.Redis.Breaker.Cooldown > 0, "redis.breaker.cooldown", "must be positive")
+
+	// casbin
+	v.check(c.Casbin.Name != "", "casbin.name", "is required")
+	_, err = model.NewModelFromString(c.Casbin.Model)
+	v.check(err == nil, "casbin.model", "%v", err)
+
+	// jwt
+	keys := &jwtKeys{pem: c.JWT.SignKey + c.JWT.VerifyKey}
+	keys.sign, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(c.JWT.SignKey))
+	v.check(err == nil, "jwt.signKey", "%v", err)
+	keys.verify, err = jwt.ParseRSAPublicKeyFromPEM([]byte(c.JWT.VerifyKey))
+	v.check(err == nil, "jwt.verifyKey", "%v", err)
+	c.JWT.keys.Store(keys)
+
+	// the sample secrets of the repository are only allowed locally
+	if c.Env != EnvLocal {
+		v.check(!isSampleKey(keys.verify), "jwt", "the sample keys must not be used in the %s environment", c.Env)
+		v.check(!isSamplePassword(c.Database.URL), "database.url",
+			"the sample password must not be used in the %s environment", c.Env)
+	}
+
+	if len(v.violations) > 0 {
+		return fmt.Errorf("invalid config:\n  %s", strings.Join(v.violations, "\n  "))
+	}
+
+	return nil
+}
+
+// validOrigin returns true if the origin is *, an origin or an origin with a wildcard subdomain.
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+	return err == nil && u.Scheme != "" && u.Host != "" && u.Path == "" && u.RawQuery == "" && u.User == nil
+}
+
+// isLoopback returns true if the address is bound to a loopback interface.
+func isLoopback(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
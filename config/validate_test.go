@@ -0,0 +1,152 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// localConfig loads and validates config/local.toml.
+func localConfig(t *testing.T) *Config {
+	t.Helper()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	viper.SetConfigFile("local.toml")
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig() error = %v", err)
+	}
+	defaultConfig()
+
+	cfg, err := load(EnvLocal)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	return cfg
+}
+
+func TestValidateLocal(t *testing.T) {
+	cfg := localConfig(t)
+
+	if cfg.JWT.PrivateKey() == nil || cfg.JWT.PublicKey() == nil {
+		t.Error("Validate() does not parse the jwt keys")
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c *Config)
+		keys   []string
+	}{
+		{
+			name:   "port",
+			mutate: func(c *Config) { c.App.Port = 70000 },
+			keys:   []string{"app.port"},
+		},
+		{
+			name:   "log mode",
+			mutate: func(c *Config) { c.Log.Mode = "verbose" },
+			keys:   []string{"log.mode"},
+		},
+		{
+			name: "pool size",
+			mutate: func(c *Config) {
+				c.Database.MinPoolSize = 10
+				c.Database.MaxPoolSize = 5
+			},
+			keys: []string{"database.min_pool_size"},
+		},
+		{
+			name:   "read preference",
+			mutate: func(c *Config) { c.Database.ReadPreference = "anywhere" },
+			keys:   []string{"database.read_preference"},
+		},
+		{
+			name: "shutdown phases",
+			mutate: func(c *Config) {
+				c.Shutdown.Timeout = c.Shutdown.DrainTimeout
+			},
+			keys: []string{"shutdown.timeout"},
+		},
+		{
+			name: "every violation at once",
+			mutate: func(c *Config) {
+				c.App.Name = ""
+				c.Health.Interval = 0
+				c.Tracing.SampleRatio = 2
+				c.JWT.SignKey = "invalid"
+			},
+			keys: []string{"app.name", "health.interval", "tracing.sample_ratio", "jwt.signKey"},
+		},
+		{
+			name: "sample secrets outside local",
+			mutate: func(c *Config) {
+				c.Env = "production"
+			},
+			keys: []string{"jwt:", "database.url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := localConfig(t)
+			tt.mutate(cfg)
+
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("Validate() error = nil, want violations")
+			}
+
+			for _, key := range tt.keys {
+				if !strings.Contains(err.Error(), key) {
+					t.Errorf("Validate() error = %v, want a violation of %s", err, key)
+				}
+			}
+		})
+	}
+}
+
+func TestValidOrigin(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "*", want: true},
+		{origin: "https://example.com", want: true},
+		{origin: "http://localhost:3000", want: true},
+		{origin: "https://*.example.com", want: true},
+		{origin: "example.com"},
+		{origin: "https://example.com/path"},
+		{origin: "https://user@example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := validOrigin(tt.origin); got != tt.want {
+			t.Errorf("validOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{address: "127.0.0.1:6060", want: true},
+		{address: "[::1]:6060", want: true},
+		{address: "localhost:6060", want: true},
+		{address: "0.0.0.0:6060"},
+		{address: ":6060"},
+		{address: "127.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		if got := isLoopback(tt.address); got != tt.want {
+			t.Errorf("isLoopback(%q) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// maxBodyBytes caps the size of the request bodies.
+const maxBodyBytes = 4 * 1024 * 1024 // 4MiB
+
+var _ IGateway = (*Gateway)(nil)
+
+// IGateway is the interface that must be implemented by a gateway.
+type IGateway interface {
+	http.Handler
+
+	// OpenAPI returns the OpenAPI v3 document of the routes.
+	OpenAPI() map[string]any
+}
+
+// Option gateway option.
+type Option struct {
+	Config *config.Config
+	Mux    *http.ServeMux
+}
+
+// Gateway transcodes the REST routes into Connect JSON requests, served by the mux
+// with the same interceptors as the Connect, gRPC and gRPC-Web requests.
+type Gateway struct {
+	mux     *http.ServeMux
+	routes  []*route
+	openapi map[string]any
+}
+
+// route is a route of the table with its method descriptor.
+type route struct {
+	*Route
+	segments []string
+	method   protoreflect.MethodDescriptor
+}
+
+// NewGateway creates the gateway and registers the REST routes, the OpenAPI document
+// and the Swagger UI on the mux.
+func NewGateway(opt *Option) IGateway {
+	g := &Gateway{
+		mux: opt.Mux,
+	}
+
+	for _, r := range routes {
+		method, err := methodDescriptor(r.Procedure)
+		if err != nil {
+			log.Err(err).Str("procedure", r.Procedure).Msg("Failed to add gateway route")
+			continue
+		}
+
+		g.routes = append(g.routes, &route{
+			Route:    r,
+			segments: strings.Split(strings.Trim(r.Path, "/"), "/"),
+			method:   method,
+		})
+	}
+	g.openapi = g.newOpenAPI(opt.Config.App.Name)
+
+	if !opt.Config.Gateway.Enabled {
+		return g
+	}
+
+	opt.Mux.Handle("/v1/", g)
+	if opt.Config.Gateway.Docs {
+		opt.Mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+			utils.ResponseWithJson(w, http.StatusOK, g.openapi)
+		})
+		opt.Mux.HandleFunc("/", swaggerHandler)
+	}
+
+	log.Info().
+		Int("routes", len(g.routes)).
+		Bool("docs", opt.Config.Gateway.Docs).
+		Msg("Gateway routes loaded")
+
+	return g
+}
+
+// OpenAPI returns the OpenAPI v3 document of the routes.
+func (g *Gateway) OpenAPI() map[string]any {
+	return g.openapi
+}
+
+// ServeHTTP transcodes the REST request into a Connect JSON request of the procedure.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt, params, allowed := g.match(r.Method, r.URL.EscapedPath())
+	if rt == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeError(w, http.StatusMethodNotAllowed, connect.CodeUnimplemented, "method not allowed")
+			return
+		}
+
+		writeError(w, http.StatusNotFound, connect.CodeNotFound, "route not found")
+		return
+	}
+
+	body, err := rt.request(w, r, params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, connect.CodeInvalidArgument, err.Error())
+		return
+	}
+
+	req := r.Clone(r.Context())
+	req.Method = http.MethodPost
+	req.URL.Path = rt.Procedure
+	req.URL.RawPath = ""
+	req.URL.RawQuery = ""
+	req.RequestURI = rt.Procedure
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connect-Protocol-Version", "1")
+	req.Header.Del("Content-Encoding")
+	req.Header.Del("Content-Length")
+
+	g.mux.ServeHTTP(w, req)
+}
+
+// match returns the route of the method and path with its path parameters,
+// or the allowed methods if only the path matches.
+func (g *Gateway) match(method, path string) (*route, map[string]string, []string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var allowed []string
+	for _, rt := range g.routes {
+		params, ok := rt.matchPath(segments)
+		if !ok {
+			continue
+		}
+
+		if rt.Method == method {
+			return rt, params, nil
+		}
+		allowed = append(allowed, rt.Method)
+	}
+
+	return nil, nil, allowed
+}
+
+// matchPath returns the path parameters if the escaped segments match the route.
+func (rt *route) matchPath(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil || value == "" {
+				return nil, false
+			}
+
+			params[segment[1:len(segment)-1]] = value
+			continue
+		}
+
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// request returns the JSON request message of the body, the query and the path parameters,
+// in increasing order of precedence.
+func (rt *route) request(w http.ResponseWriter, r *http.Request, params map[string]string) ([]byte, error) {
+	message := make(map[string]any)
+
+	if rt.Body {
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			return nil, fmt.Errorf("read body: %w", err)
+		}
+
+		if len(bytes.TrimSpace(data)) > 0 {
+			fields := make(map[string]json.RawMessage)
+			if err = json.Unmarshal(data, &fields); err != nil {
+				return nil, errors.New("body must be a JSON object")
+			}
+
+			for name, value := range fields {
+				message[name] = value
+			}
+		}
+	}
+
+	for name, values := range r.URL.Query() {
+		if err := rt.setField(message, name, values); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, value := range params {
+		if err := rt.setField(message, name, []string{value}); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(message)
+}
+
+// setField sets the field of the JSON request message from the parameter values.
+func (rt *route) setField(message map[string]any, name string, values []string) error {
+	fields := rt.method.Input().Fields()
+	fd := fields.ByJSONName(name)
+	if fd == nil {
+		fd = fields.ByName(protoreflect.Name(name))
+	}
+	if fd == nil {
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind || fd.IsMap() {
+		return fmt.Errorf("parameter %q is not a scalar", name)
+	}
+
+	converted := make([]any, 0, len(values))
+	for _, value := range values {
+		v, err := scalarValue(fd, value)
+		if err != nil {
+			return fmt.Errorf("parameter %q: %w", name, err)
+		}
+
+		converted = append(converted, v)
+	}
+
+	switch {
+	case fd.IsList():
+		message[fd.JSONName()] = converted
+	case len(converted) > 0:
+		message[fd.JSONName()] = converted[len(converted)-1]
+	}
+
+	return nil
+}
+
+// scalarValue returns the JSON value of the parameter, the numbers are kept as strings
+// since protojson accepts them quoted.
+func scalarValue(fd protoreflect.FieldDescriptor, value string) (any, error) {
+	if fd.Kind() == protoreflect.BoolKind {
+		return strconv.ParseBool(value)
+	}
+
+	return value, nil
+}
+
+// methodDescriptor returns the method descriptor of the procedure.
+func methodDescriptor(procedure string) (protoreflect.MethodDescriptor, error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(procedure, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid procedure %q", procedure)
+	}
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, err
+	}
+
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", service)
+	}
+
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, fmt.Errorf("method %q not found", procedure)
+	}
+
+	return md, nil
+}
+
+// writeError writes an error in the Connect JSON error format.
+func writeError(w http.ResponseWriter, status int, code connect.Code, message string) {
+	utils.ResponseWithJson(w, status, map[string]string{
+		"code":    code.String(),
+		"message": message,
+	})
+}
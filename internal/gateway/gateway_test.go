@@ -0,0 +1,201 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+// transcoded is the Connect request received by the mux.
+type transcoded struct {
+	method      string
+	path        string
+	contentType string
+	message     map[string]any
+}
+
+// newTestGateway returns a gateway whose mux records the transcoded requests of the user service.
+func newTestGateway(t *testing.T) (*Gateway, *http.ServeMux, *transcoded) {
+	t.Helper()
+
+	got := &transcoded{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user.v1.UserService/", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+
+		got.method = r.Method
+		got.path = r.URL.Path
+		got.contentType = r.Header.Get("Content-Type")
+		got.message = map[string]any{}
+		if err := json.Unmarshal(data, &got.message); err != nil {
+			t.Errorf("transcoded body %q error = %v", data, err)
+		}
+	})
+
+	g := NewGateway(&Option{
+		Config: &config.Config{
+			App:     config.App{Name: "test"},
+			Gateway: config.Gateway{Enabled: true, Docs: true},
+		},
+		Mux: mux,
+	}).(*Gateway)
+
+	if len(g.routes) != len(routes) {
+		t.Fatalf("NewGateway() loaded %d routes, want %d", len(g.routes), len(routes))
+	}
+
+	return g, mux, got
+}
+
+func TestServeHTTP(t *testing.T) {
+	g, _, got := newTestGateway(t)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		path   string
+		want   map[string]any
+	}{
+		{
+			name:   "query parameters",
+			method: http.MethodGet,
+			target: "/v1/users?page=2",
+			path:   "/user.v1.UserService/FindAllUsers",
+			want:   map[string]any{"page": "2"},
+		},
+		{
+			name:   "escaped path parameter",
+			method: http.MethodGet,
+			target: "/v1/users/abc%2F1",
+			path:   "/user.v1.UserService/FindUserByID",
+			want:   map[string]any{"id": "abc/1"},
+		},
+		{
+			name:   "path over query over body",
+			method: http.MethodPatch,
+			target: "/v1/users/abc?id=query&name=query",
+			body:   `{"id":"body","name":"body","email":"a@example.com"}`,
+			path:   "/user.v1.UserService/UpdateUser",
+			want:   map[string]any{"id": "abc", "name": "query", "email": "a@example.com"},
+		},
+		{
+			name:   "empty body",
+			method: http.MethodPost,
+			target: "/v1/users",
+			path:   "/user.v1.UserService/CreateUser",
+			want:   map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			g.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%s %s = %d %s, want %d", tt.method, tt.target, rec.Code, rec.Body, http.StatusOK)
+			}
+
+			if got.method != http.MethodPost || got.path != tt.path || got.contentType != "application/json" {
+				t.Errorf("transcoded = %s %s %s, want POST %s application/json", got.method, got.path,
+					got.contentType, tt.path)
+			}
+
+			if len(got.message) != len(tt.want) {
+				t.Errorf("transcoded message = %v, want %v", got.message, tt.want)
+			}
+			for name, value := range tt.want {
+				if got.message[name] != value {
+					t.Errorf("transcoded message = %v, want %v", got.message, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestServeHTTPErrors(t *testing.T) {
+	g, _, _ := newTestGateway(t)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+		allow  string
+	}{
+		{name: "unknown route", method: http.MethodGet, target: "/v1/unknown", want: http.StatusNotFound},
+		{name: "method not allowed", method: http.MethodPut, target: "/v1/users/abc", want: http.StatusMethodNotAllowed,
+			allow: "GET, PATCH, DELETE"},
+		{name: "unknown parameter", method: http.MethodGet, target: "/v1/users?unknown=1", want: http.StatusBadRequest},
+		{name: "body not an object", method: http.MethodPost, target: "/v1/users", body: `["a"]`,
+			want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			g.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.target, rec.Code, tt.want)
+			}
+
+			if got := rec.Header().Get("Allow"); got != tt.allow {
+				t.Errorf("Allow = %q, want %q", got, tt.allow)
+			}
+
+			body := map[string]string{}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body["code"] == "" {
+				t.Errorf("error body = %v, want the Connect error format", body)
+			}
+		})
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	_, mux, _ := newTestGateway(t)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Security   []any `json:"security"`
+			Parameters []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+			RequestBody any `json:"requestBody"`
+		} `json:"paths"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("openapi.json error = %v", err)
+	}
+
+	login := doc.Paths["/v1/auth/login"]["post"]
+	if login.Security == nil || len(login.Security) != 0 || login.RequestBody == nil {
+		t.Errorf("login operation = %+v, want a public operation with a body", login)
+	}
+
+	update := doc.Paths["/v1/users/{id}"]["patch"]
+	if len(update.Parameters) != 1 || update.Parameters[0].Name != "id" || update.Parameters[0].In != "path" {
+		t.Errorf("update parameters = %+v, want the id path parameter only", update.Parameters)
+	}
+
+	list := doc.Paths["/v1/users"]["get"]
+	if list.Security != nil || len(list.Parameters) == 0 || list.Parameters[0].In != "query" {
+		t.Errorf("list operation = %+v, want authenticated query parameters", list)
+	}
+}
@@ -0,0 +1,242 @@
+package gateway
+
+import (
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// openapiVersion is the version of the OpenAPI specification of the document.
+const openapiVersion = "3.0.3"
+
+// newOpenAPI generates the OpenAPI v3 document of the routes from their method descriptors.
+func (g *Gateway) newOpenAPI(title string) map[string]any {
+	schemas := map[string]any{
+		"Error": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"code":    map[string]any{"type": "string"},
+				"message": map[string]any{"type": "string"},
+				"details": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+			},
+		},
+	}
+	paths := make(map[string]any)
+	tags := make(map[string]bool)
+
+	for _, rt := range g.routes {
+		item, ok := paths[rt.Path].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			paths[rt.Path] = item
+		}
+
+		tag := string(rt.method.Parent().Name())
+		tags[tag] = true
+
+		item[strings.ToLower(rt.Method)] = rt.operation(tag, schemas)
+	}
+
+	names := make([]string, 0, len(tags))
+	for tag := range tags {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+
+	tagList := make([]any, 0, len(names))
+	for _, name := range names {
+		tagList = append(tagList, map[string]any{"name": name})
+	}
+
+	return map[string]any{
+		"openapi": openapiVersion,
+		"info": map[string]any{
+			"title":   title,
+			"version": "v1",
+		},
+		"tags":  tagList,
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+			},
+		},
+		"security": []any{
+			map[string]any{"bearerAuth": []any{}},
+		},
+	}
+}
+
+// operation returns the OpenAPI operation of the route, adding the message schemas.
+func (rt *route) operation(tag string, schemas map[string]any) map[string]any {
+	input := rt.method.Input()
+	parameters := make([]any, 0)
+
+	pathParams := make(map[string]bool)
+	for _, segment := range rt.segments {
+		if !strings.HasPrefix(segment, "{") {
+			continue
+		}
+
+		name := segment[1 : len(segment)-1]
+		pathParams[name] = true
+
+		schema := map[string]any{"type": "string"}
+		if fd := input.Fields().ByJSONName(name); fd != nil {
+			schema = fieldSchema(fd, schemas)
+		}
+
+		parameters = append(parameters, map[string]any{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   schema,
+		})
+	}
+
+	// the scalar fields of the routes without a body are query parameters
+	if !rt.Body {
+		fields := input.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if pathParams[fd.JSONName()] || fd.Kind() == protoreflect.MessageKind || fd.IsMap() {
+				continue
+			}
+
+			parameters = append(parameters, map[string]any{
+				"name":   fd.JSONName(),
+				"in":     "query",
+				"schema": fieldSchema(fd, schemas),
+			})
+		}
+	}
+
+	op := map[string]any{
+		"tags":        []any{tag},
+		"operationId": string(rt.method.Name()),
+		"summary":     rt.Procedure,
+		"parameters":  parameters,
+		"responses": map[string]any{
+			"200": map[string]any{
+				"description": "OK",
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": messageRef(rt.method.Output(), schemas)},
+				},
+			},
+			"default": map[string]any{
+				"description": "Error",
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Error"}},
+				},
+			},
+		},
+	}
+
+	if rt.Body {
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": messageRef(input, schemas)},
+			},
+		}
+	}
+
+	if rt.Public {
+		op["security"] = []any{}
+	}
+
+	return op
+}
+
+// messageRef returns the reference to the message schema, adding it and its dependencies.
+func messageRef(md protoreflect.MessageDescriptor, schemas map[string]any) map[string]any {
+	name := string(md.FullName())
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if _, ok := schemas[name]; ok {
+		return ref
+	}
+
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return map[string]any{"type": "string", "format": "date-time"}
+	case "google.protobuf.Duration":
+		return map[string]any{"type": "string"}
+	case "google.protobuf.Empty":
+		return map[string]any{"type": "object"}
+	}
+
+	// reserve the name first, the messages can be recursive
+	properties := make(map[string]any)
+	schemas[name] = map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		properties[fd.JSONName()] = fieldSchema(fd, schemas)
+	}
+
+	return ref
+}
+
+// fieldSchema returns the schema of the field in the protojson mapping.
+func fieldSchema(fd protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	if fd.IsMap() {
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": singularSchema(fd.MapValue(), schemas),
+		}
+	}
+
+	schema := singularSchema(fd, schemas)
+	if fd.IsList() {
+		return map[string]any{
+			"type":  "array",
+			"items": schema,
+		}
+	}
+
+	return schema
+}
+
+// singularSchema returns the schema of a single value of the field.
+func singularSchema(fd protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "int64", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson encodes the 64-bit integers as strings
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]any, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageRef(fd.Message(), schemas)
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
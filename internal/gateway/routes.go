@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/xdorro/proto-base-project/proto-gen-go/auth/v1/authv1connect"
+	"github.com/xdorro/proto-base-project/proto-gen-go/permission/v1/permissionv1connect"
+	"github.com/xdorro/proto-base-project/proto-gen-go/role/v1/rolev1connect"
+	"github.com/xdorro/proto-base-project/proto-gen-go/user/v1/userv1connect"
+)
+
+// Route maps a REST route onto a procedure.
+type Route struct {
+	Method string
+	// Path is the route path, the {field} segments are copied into the request field.
+	Path      string
+	Procedure string
+	// Body decodes the request body into the request message.
+	Body bool
+	// Public documents the route without the bearer authentication.
+	Public bool
+}
+
+// procedure returns the procedure of the method of the service.
+func procedure(service, method string) string {
+	return "/" + service + "/" + method
+}
+
+// routes is the route table of the REST gateway.
+var routes = []*Route{
+	// users
+	{Method: http.MethodGet, Path: "/v1/users", Procedure: procedure(userv1connect.UserServiceName, "FindAllUsers")},
+	{Method: http.MethodGet, Path: "/v1/users/{id}", Procedure: procedure(userv1connect.UserServiceName, "FindUserByID")},
+	{Method: http.MethodPost, Path: "/v1/users", Procedure: procedure(userv1connect.UserServiceName, "CreateUser"), Body: true},
+	{Method: http.MethodPatch, Path: "/v1/users/{id}", Procedure: procedure(userv1connect.UserServiceName, "UpdateUser"), Body: true},
+	{Method: http.MethodDelete, Path: "/v1/users/{id}", Procedure: procedure(userv1connect.UserServiceName, "DeleteUser")},
+
+	// roles
+	{Method: http.MethodGet, Path: "/v1/roles", Procedure: procedure(rolev1connect.RoleServiceName, "FindAllRoles")},
+	{Method: http.MethodGet, Path: "/v1/roles/{name}", Procedure: procedure(rolev1connect.RoleServiceName, "FindRoleByName")},
+	{Method: http.MethodPost, Path: "/v1/roles", Procedure: procedure(rolev1connect.RoleServiceName, "CreateRole"), Body: true},
+	{Method: http.MethodPatch, Path: "/v1/roles/{name}", Procedure: procedure(rolev1connect.RoleServiceName, "UpdateRole"), Body: true},
+	{Method: http.MethodDelete, Path: "/v1/roles/{name}", Procedure: procedure(rolev1connect.RoleServiceName, "DeleteRole")},
+
+	// permissions
+	{Method: http.MethodGet, Path: "/v1/permissions", Procedure: procedure(permissionv1connect.PermissionServiceName, "FindAllPermissions")},
+	{Method: http.MethodGet, Path: "/v1/permissions/{id}", Procedure: procedure(permissionv1connect.PermissionServiceName, "FindPermissionByID")},
+	{Method: http.MethodPost, Path: "/v1/permissions", Procedure: procedure(permissionv1connect.PermissionServiceName, "CreatePermission"), Body: true},
+	{Method: http.MethodPatch, Path: "/v1/permissions/{id}", Procedure: procedure(permissionv1connect.PermissionServiceName, "UpdatePermission"), Body: true},
+	{Method: http.MethodDelete, Path: "/v1/permissions/{id}", Procedure: procedure(permissionv1connect.PermissionServiceName, "DeletePermission")},
+
+	// auth
+	{Method: http.MethodPost, Path: "/v1/auth/login", Procedure: procedure(authv1connect.AuthServiceName, "Login"), Body: true, Public: true},
+	{Method: http.MethodPost, Path: "/v1/auth/refresh", Procedure: procedure(authv1connect.AuthServiceName, "RefreshToken"), Body: true, Public: true},
+	{Method: http.MethodPost, Path: "/v1/auth/revoke", Procedure: procedure(authv1connect.AuthServiceName, "RevokeToken"), Body: true},
+}
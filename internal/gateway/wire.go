@@ -0,0 +1,11 @@
+package gateway
+
+import (
+	"github.com/google/wire"
+)
+
+// ProviderGatewaySet is Gateway providers.
+var ProviderGatewaySet = wire.NewSet(
+	NewGateway,
+	wire.Struct(new(Option), "*"),
+)
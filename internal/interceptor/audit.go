@@ -0,0 +1,131 @@
+package interceptor
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/xdorro/proto-base-project/proto-gen-go/role/v1/rolev1connect"
+	"go.opentelemetry.io/otel/trace"
+
+	auditmodel "github.com/xdorro/golang-grpc-base-project/internal/module/audit/model"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// AuditInterceptor is a unary interceptor that records an audit entry for every mutating procedure.
+// It runs after the authorization, so the denied calls are neither snapshotted nor recorded, and the
+// actor is the subject of the verified access token.
+func (i *Interceptor) AuditInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (
+			connect.AnyResponse, error,
+		) {
+			procedure := request.Spec().Procedure
+			if !i.auditEnabled || !i.isMutating(procedure) {
+				return next(ctx, request)
+			}
+
+			before := i.auditBiz.Snapshot(ctx, procedure, auditTargets(procedure, request.Any(), nil))
+			response, err := next(ctx, request)
+
+			data := i.auditEntry(ctx, procedure, request.Header(), request.Peer().Addr)
+			if err != nil {
+				data.Targets = auditTargets(procedure, request.Any(), nil)
+				data.Outcome = auditmodel.OutcomeFailure
+				data.Code = connect.CodeOf(err).String()
+			} else {
+				data.Targets = auditTargets(procedure, request.Any(), response.Any())
+			}
+
+			// the request context is canceled once the response is sent, keep the trace and logger only
+			spanContext := trace.SpanContextFromContext(ctx)
+			logger := log.Ctx(ctx)
+			i.lifecycle.Go("audit", func(jobCtx context.Context) {
+				ctx := logger.WithContext(trace.ContextWithSpanContext(jobCtx, spanContext))
+				if data.Outcome == auditmodel.OutcomeSuccess {
+					after := i.auditBiz.Snapshot(ctx, procedure, data.Targets)
+					data.Changes = auditmodel.Diff(before, after)
+				}
+
+				i.auditBiz.Record(ctx, data)
+			})
+
+			return response, err
+		}
+	}
+}
+
+// auditEntry returns the audit entry of the procedure, with the caller of the verified claims.
+func (i *Interceptor) auditEntry(ctx context.Context, procedure string, header http.Header, addr string) *auditmodel.Audit {
+	data := &auditmodel.Audit{
+		Procedure: procedure,
+		ClientIP:  i.clientIP(header, addr),
+		Outcome:   auditmodel.OutcomeSuccess,
+	}
+
+	if claims := session.Claims(ctx); claims != nil {
+		data.Actor = claims.Subject
+		if len(claims.Audience) > 0 {
+			data.Role = claims.Audience[0]
+		}
+	} else {
+		data.Actor = i.certSubject(ctx)
+	}
+
+	return data
+}
+
+// isMutating returns true if the procedure method starts with one of the audit prefixes.
+func (i *Interceptor) isMutating(procedure string) bool {
+	method := procedure[strings.LastIndex(procedure, "/")+1:]
+	for _, prefix := range i.auditPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// auditClaims returns the claims of the access token, if any.
+func (i *Interceptor) auditClaims(header http.Header) *jwt.RegisteredClaims {
+	token, err := utils.AuthFromHeader(header, utils.TokenType)
+	if err != nil {
+		return nil
+	}
+
+	claims, err := utils.DecryptToken(i.jwt.PublicKey(), token)
+	if err != nil {
+		return nil
+	}
+
+	return claims
+}
+
+// auditTargets returns the ids of the records touched by the procedure.
+func auditTargets(procedure string, request, response any) []string {
+	if msg, ok := request.(interface{ GetId() string }); ok && msg.GetId() != "" {
+		return []string{msg.GetId()}
+	}
+
+	// roles are identified by name
+	if strings.HasPrefix(procedure, "/"+rolev1connect.RoleServiceName+"/") {
+		if msg, ok := request.(interface{ GetName() string }); ok && msg.GetName() != "" {
+			return []string{strings.ToLower(msg.GetName())}
+		}
+	}
+
+	// the created records return their id, the other responses return a status
+	method := procedure[strings.LastIndex(procedure, "/")+1:]
+	if strings.HasPrefix(method, "Create") {
+		if msg, ok := response.(interface{ GetData() string }); ok && msg.GetData() != "" {
+			return []string{msg.GetData()}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package interceptor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	userv1 "github.com/xdorro/proto-base-project/proto-gen-go/user/v1"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+)
+
+func TestAuditTargets(t *testing.T) {
+	id := "63a1f0c2e4b0a1b2c3d4e5f6"
+
+	tests := []struct {
+		name      string
+		procedure string
+		request   any
+		response  any
+		want      []string
+	}{
+		{
+			name:      "request id",
+			procedure: "/user.v1.UserService/UpdateUser",
+			request:   &userv1.UpdateUserRequest{Id: id},
+			response:  &userv1.CommonResponse{Data: "success"},
+			want:      []string{id},
+		},
+		{
+			name:      "created id",
+			procedure: "/user.v1.UserService/CreateUser",
+			request:   &userv1.CreateUserRequest{Email: "a@example.com"},
+			response:  &userv1.CommonResponse{Data: id},
+			want:      []string{id},
+		},
+		{
+			name:      "status of another procedure",
+			procedure: "/user.v1.UserService/UpdateUser",
+			request:   &userv1.UpdateUserRequest{},
+			response:  &userv1.CommonResponse{Data: "done"},
+		},
+		{
+			name:      "failed create",
+			procedure: "/user.v1.UserService/CreateUser",
+			request:   &userv1.CreateUserRequest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := auditTargets(tt.procedure, tt.request, tt.response); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("auditTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditEntry(t *testing.T) {
+	i := &Interceptor{}
+	header := map[string][]string{"X-Tenant-Id": {"spoofed"}}
+
+	ctx := session.WithClaims(context.Background(), &jwt.RegisteredClaims{
+		Subject:  "63a1f0c2e4b0a1b2c3d4e5f6",
+		Audience: jwt.ClaimStrings{"admin"},
+	})
+
+	data := i.auditEntry(ctx, "/user.v1.UserService/UpdateUser", header, "203.0.113.7:51234")
+	if data.Actor != "63a1f0c2e4b0a1b2c3d4e5f6" || data.Role != "admin" {
+		t.Errorf("auditEntry() actor = %q, role = %q, want the verified claims", data.Actor, data.Role)
+	}
+
+	if data.Tenant != "" {
+		t.Errorf("auditEntry() tenant = %q, want none from the header", data.Tenant)
+	}
+
+	if data.ClientIP != "203.0.113.7" {
+		t.Errorf("auditEntry() client ip = %q, want the peer address", data.ClientIP)
+	}
+
+	if data = i.auditEntry(context.Background(), "/user.v1.UserService/UpdateUser", header, ""); data.Actor != "" {
+		t.Errorf("auditEntry() actor = %q, want none without verified claims", data.Actor)
+	}
+}
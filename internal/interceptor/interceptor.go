@@ -3,131 +3,299 @@ package interceptor
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/bufbuild/connect-go"
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/xdorro/golang-grpc-base-project/config"
+	auditbiz "github.com/xdorro/golang-grpc-base-project/internal/module/audit/biz"
 	permissionmodel "github.com/xdorro/golang-grpc-base-project/internal/module/permission/model"
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
 	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/certs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/lifecycle"
+	"github.com/xdorro/golang-grpc-base-project/pkg/ratelimit"
+	"github.com/xdorro/golang-grpc-base-project/pkg/redact"
 	"github.com/xdorro/golang-grpc-base-project/pkg/redis"
 	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
 	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
 	"github.com/xdorro/golang-grpc-base-project/pkg/utils/constants"
 )
 
+const (
+	// logModeFull logs the procedure, headers, request and response.
+	logModeFull = "full"
+	// logModeMetadata logs the procedure and headers only.
+	logModeMetadata = "metadata"
+	// logModeNone disables the payload log.
+	logModeNone = "none"
+)
+
 var _ IInterceptor = (*Interceptor)(nil)
 
 // IInterceptor is the interface that must be implemented by an interceptor.
 type IInterceptor interface {
 	UnaryInterceptor() connect.UnaryInterceptorFunc
+	AuditInterceptor() connect.UnaryInterceptorFunc
+	ValidateInterceptor() connect.UnaryInterceptorFunc
+	RateLimitInterceptor() connect.UnaryInterceptorFunc
+	RequestIDInterceptor() connect.UnaryInterceptorFunc
+	MetricsInterceptor() connect.UnaryInterceptorFunc
+	TracingInterceptor() connect.UnaryInterceptorFunc
+	StreamInterceptor() connect.Interceptor
 }
 
 // Option is an interceptor option struct.
 type Option struct {
-	Casbin casbin.ICasbin
-	Redis  redis.IRedis
-	Repo   repo.IRepo
+	Config    *config.Config
+	Lifecycle lifecycle.ILifecycle
+	Casbin    casbin.ICasbin
+	Redis     redis.IRedis
+	Repo      repo.IRepo
+	Session   session.ISession
+	AuditBiz  auditbiz.IAuditBiz
 }
 
 // Interceptor is an interceptor struct.
 type Interceptor struct {
-	logPayload bool
+	settings      atomic.Pointer[settings]
+	redactor      *redact.Redactor
+	auditEnabled  bool
+	auditPrefixes []string
+	jwt           *config.JWT
+	identities    map[string]string
+	proxies       []*net.IPNet
+	limiter       ratelimit.ILimiter
 
 	// options
+	lifecycle            lifecycle.ILifecycle
 	casbin               casbin.ICasbin
 	redis                redis.IRedis
+	session              session.ISession
+	auditBiz             auditbiz.IAuditBiz
 	permissionCollection *mongo.Collection
+	userCollection       *mongo.Collection
+}
+
+// settings are the payload log and rate limit settings, replaced when the config is reloaded.
+type settings struct {
+	logPayload    bool
+	logMode       string
+	logProcedures map[string]string
+
+	// rate limit
+	rateLimitEnabled bool
+	rateLimitDefault *config.RateLimitRule
+	rateLimitRules   map[string]*config.RateLimitRule
 }
 
 // NewInterceptor returns a new interceptor.
 func NewInterceptor(opt *Option) IInterceptor {
+	cfg := opt.Config
 	i := &Interceptor{
-		logPayload:           viper.GetBool("log.payload"),
+		redactor:             redact.New(cfg.Log.Redact.Fields, cfg.Log.Redact.Headers),
+		auditEnabled:         cfg.Audit.Enabled,
+		auditPrefixes:        cfg.Audit.Prefixes,
+		jwt:                  &cfg.JWT,
+		identities:           make(map[string]string),
+		proxies:              cfg.App.Proxies(),
+		lifecycle:            opt.Lifecycle,
 		casbin:               opt.Casbin,
 		redis:                opt.Redis,
+		session:              opt.Session,
+		auditBiz:             opt.AuditBiz,
 		permissionCollection: opt.Repo.CollectionModel(&permissionmodel.Permission{}),
+		userCollection:       opt.Repo.CollectionModel(&usermodel.User{}),
 	}
 
+	// client certificate subjects
+	for _, id := range cfg.TLS.Identities {
+		i.identities[id.Identity] = id.Subject
+	}
+
+	// rate limiter
+	i.newRateLimiter(cfg.RateLimit.Store)
+
+	i.settings.Store(newSettings(cfg))
+	cfg.OnReload(func(cfg *config.Config) {
+		i.settings.Store(newSettings(cfg))
+	})
+
 	return i
 }
 
+// clientIP returns the client ip of the request, from the headers of the trusted proxies.
+func (i *Interceptor) clientIP(header http.Header, addr string) string {
+	return utils.ClientIP(header, addr, i.proxies)
+}
+
+// newSettings returns the payload log and rate limit settings of the config.
+func newSettings(cfg *config.Config) *settings {
+	st := &settings{
+		logPayload:       cfg.Log.Payload,
+		logMode:          cfg.Log.Mode,
+		logProcedures:    make(map[string]string),
+		rateLimitEnabled: cfg.RateLimit.Enabled,
+		rateLimitDefault: &config.RateLimitRule{
+			Limit:  cfg.RateLimit.Limit,
+			Window: cfg.RateLimit.Window,
+			Key:    cfg.RateLimit.Key,
+		},
+		rateLimitRules: make(map[string]*config.RateLimitRule),
+	}
+
+	// per procedure payload log modes
+	for _, p := range cfg.Log.Procedures {
+		st.logProcedures[p.Procedure] = p.Mode
+	}
+
+	// per procedure rate limits
+	for _, rule := range cfg.RateLimit.Procedures {
+		st.rateLimitRules[rule.Procedure] = rule
+	}
+
+	return st
+}
+
 // UnaryInterceptor is a unary interceptor.
 func (i *Interceptor) UnaryInterceptor() connect.UnaryInterceptorFunc {
 	return func(next connect.UnaryFunc) connect.UnaryFunc {
 		return func(ctx context.Context, request connect.AnyRequest) (
 			connect.AnyResponse, error,
 		) {
-			response, err := next(ctx, request)
-
-			// get full method
-			permissions := i.getListPermissions()
-			if len(permissions) == 0 {
-				return i.logPayloadHandler(request, response, err)
+			// the caller is authorized before the procedure is handled
+			ctx, err := i.authorizeProcedure(ctx, request.Header(), request.Spec().Procedure)
+			if err != nil {
+				return i.logPayloadHandler(ctx, request, nil, err)
 			}
 
-			// check permission
-			procedure := request.Spec().Procedure
-			per, ok := permissions[procedure]
-			if !ok || per == nil {
-				return i.logPayloadHandler(request, response, err)
-			}
+			response, err := next(ctx, request)
+			return i.logPayloadHandler(ctx, request, response, err)
+		}
+	}
+}
 
-			// check require auth
-			if per.RequireAuth {
-				var token string
-				token, err = utils.AuthFromHeader(request.Header(), utils.TokenType)
-				if err != nil {
-					log.Err(err).Msg("Error get token from header")
-					return i.logPayloadHandler(request, nil, connect.NewError(connect.CodeUnauthenticated, err))
-				}
-
-				var claims *jwt.RegisteredClaims
-				claims, err = utils.DecryptToken(token)
-				if err != nil {
-					return i.logPayloadHandler(request, nil, connect.NewError(connect.CodeInvalidArgument, err))
-				}
-
-				// check role
-				var role string
-				if len(claims.Audience) > 0 {
-					role = claims.Audience[0]
-				}
-
-				allowed, _ := i.casbin.Enforcer().Enforce(role, procedure)
-				if !allowed {
-					err = fmt.Errorf("Permission denied")
-					return i.logPayloadHandler(request, nil, connect.NewError(connect.CodePermissionDenied, err))
-				}
-			}
+// authorizeProcedure authorizes the caller of the procedures requiring authentication, it returns
+// the context carrying the claims of the verified access token.
+func (i *Interceptor) authorizeProcedure(ctx context.Context, header http.Header, procedure string) (
+	context.Context, error,
+) {
+	per, ok := i.getListPermissions(ctx)[procedure]
+	if !ok || per == nil || !per.RequireAuth {
+		return ctx, nil
+	}
 
-			// check require hash
-			if !per.RequireHash {
-				return i.logPayloadHandler(request, response, err)
-			}
+	return i.authorize(ctx, header, procedure)
+}
 
-			// handler hash payload here
-			// // test custom response
-			//  response = connect.NewResponse(&permissionv1.CommonResponse{
-			// 	Data: "ahihi",
-			// })
-			return i.logPayloadHandler(request, response, err)
+// authorize authenticates the caller with the access token, or with the verified client certificate
+// when the request has no token, then enforces the permission of its casbin subject.
+func (i *Interceptor) authorize(ctx context.Context, header http.Header, procedure string) (context.Context, error) {
+	token, err := utils.AuthFromHeader(header, utils.TokenType)
+	if err != nil {
+		if subject := i.certSubject(ctx); subject != "" {
+			return ctx, i.enforce(ctx, subject, procedure)
 		}
+
+		log.Ctx(ctx).Err(err).Msg("Error get token from header")
+		return ctx, errs.Unauthenticated(err.Error())
+	}
+
+	claims, err := utils.DecryptToken(i.jwt.PublicKey(), token)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msg("Error decrypt token")
+		return ctx, errs.Unauthenticated("token is invalid")
 	}
+
+	// check revoked session
+	revoked, err := i.session.IsRevoked(ctx, claims)
+	if err != nil {
+		return ctx, err
+	}
+
+	if revoked {
+		return ctx, errs.Unauthenticated("token is revoked")
+	}
+
+	// the revocation list may fail open, the state of the user is checked too
+	if err = i.checkActive(ctx, claims.Subject); err != nil {
+		return ctx, err
+	}
+
+	// check role
+	var role string
+	if len(claims.Audience) > 0 {
+		role = claims.Audience[0]
+	}
+
+	if err = i.enforce(ctx, role, procedure); err != nil {
+		return ctx, err
+	}
+
+	return session.WithClaims(ctx, claims), nil
+}
+
+// checkActive returns an error if the user of the token is not active, or is deleted.
+func (i *Interceptor) checkActive(ctx context.Context, subject string) error {
+	filter := repo.ActiveIDFilter(subject)
+	opt := options.FindOne().SetProjection(bson.M{"status": 1})
+	user, err := repo.FindOne[usermodel.User](ctx, i.userCollection, filter, opt)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errs.Unauthenticated("user is not active")
+	}
+	if err != nil {
+		return errs.FromRepo(ctx, "user", err)
+	}
+
+	if !user.IsActive() {
+		return errs.Unauthenticated("user is not active")
+	}
+
+	return nil
+}
+
+// enforce returns an error if the subject is not allowed to call the procedure.
+func (i *Interceptor) enforce(ctx context.Context, subject, procedure string) error {
+	allowed, _ := i.casbin.Enforce(ctx, subject, procedure)
+	if !allowed {
+		return errs.PermissionDenied(procedure)
+	}
+
+	return nil
+}
+
+// certSubject returns the casbin subject of the verified client certificate, empty if none.
+// Only the identities mapped in the config are authenticated, any certificate of the client CA
+// could otherwise claim the subject of a role with its CN.
+func (i *Interceptor) certSubject(ctx context.Context) string {
+	identity := certs.Identity(ctx)
+	if identity == "" {
+		return ""
+	}
+
+	return i.identities[identity]
 }
 
 // getAllPermissions returns all permissions.
-func (i *Interceptor) getListPermissions() map[string]*permissionmodel.Permission {
+func (i *Interceptor) getListPermissions(ctx context.Context) map[string]*permissionmodel.Permission {
+	ctx, span := tracing.Start(ctx, "interceptor.getListPermissions")
+	defer span.End()
+
 	// get all permissions
 	permissions := make(map[string]*permissionmodel.Permission)
 
-	if val := redis.Get(i.redis, constants.ListAuthPermissionsKey); val != "" {
+	if val := redis.Get(ctx, i.redis, constants.ListAuthPermissionsKey); val != "" {
 		_ = json.Unmarshal([]byte(val), &permissions)
 		return permissions
 	}
@@ -144,7 +312,7 @@ func (i *Interceptor) getListPermissions() map[string]*permissionmodel.Permissio
 		Find().
 		SetSort(bson.M{"created_at": -1})
 
-	data, err := repo.Find[permissionmodel.Permission](i.permissionCollection, filter, opt)
+	data, err := repo.Find[permissionmodel.Permission](ctx, i.permissionCollection, filter, opt)
 	if err != nil {
 		return permissions
 	}
@@ -153,38 +321,53 @@ func (i *Interceptor) getListPermissions() map[string]*permissionmodel.Permissio
 		permissions[per.Slug] = per
 	}
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Interface("permissions", permissions).
 		Msg("Log get all permissions")
 
-	go func() {
-		_ = redis.SetObject(i.redis, constants.ListAuthPermissionsKey, permissions, 7*24*time.Hour)
-	}()
+	i.lifecycle.Go("cache permissions", func(ctx context.Context) {
+		_ = redis.SetObject(ctx, i.redis, constants.ListAuthPermissionsKey, permissions, 7*24*time.Hour)
+	})
 
 	return permissions
 }
 
 // logPayloadHandler is a log payload handler.
-func (i *Interceptor) logPayloadHandler(request connect.AnyRequest, response connect.AnyResponse, err error) (
-	connect.AnyResponse, error,
-) {
+func (i *Interceptor) logPayloadHandler(
+	ctx context.Context, request connect.AnyRequest, response connect.AnyResponse, err error,
+) (connect.AnyResponse, error) {
+	procedure := request.Spec().Procedure
+
 	// Log the payload
-	if i.logPayload {
-		go func(response connect.AnyResponse, err error) {
-			logger := log.Info()
+	st := i.settings.Load()
+	mode := st.logModeOf(procedure)
+	if st.logPayload && mode != logModeNone {
+		i.lifecycle.Go("log payload", func(context.Context) {
+			logger := log.Ctx(ctx).Info()
 			if err != nil {
-				logger = log.Error().Err(err)
-			} else {
-				logger.Interface("response", response.Any())
+				logger = log.Ctx(ctx).Error().Err(err)
+			} else if mode == logModeFull {
+				logger.Interface("response", i.redactor.Message(response.Any()))
+			}
+
+			if mode == logModeFull {
+				logger.Interface("request", i.redactor.Message(request.Any()))
 			}
 
 			logger.
-				Str("procedure", request.Spec().Procedure).
-				Interface("request", request.Any()).
-				Interface("header", request.Header()).
+				Interface("header", i.redactor.Header(request.Header())).
 				Msg("Log payload interceptor")
-		}(response, err)
+		})
+	}
+
+	return response, errs.Sanitize(ctx, err)
+}
+
+// logModeOf returns the payload log mode of the procedure.
+func (st *settings) logModeOf(procedure string) string {
+	if mode, ok := st.logProcedures[procedure]; ok {
+		return mode
 	}
 
-	return response, err
+	return st.logMode
 }
@@ -0,0 +1,35 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/certs"
+)
+
+func TestCertSubject(t *testing.T) {
+	i := &Interceptor{identities: map[string]string{"spiffe://example.org/billing": "billing"}}
+
+	tests := []struct {
+		name     string
+		identity string
+		want     string
+	}{
+		{name: "mapped identity", identity: "spiffe://example.org/billing", want: "billing"},
+		{name: "unmapped identity", identity: "admin"},
+		{name: "no certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.identity != "" {
+				ctx = certs.WithIdentity(ctx, tt.identity)
+			}
+
+			if got := i.certSubject(ctx); got != tt.want {
+				t.Errorf("certSubject() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
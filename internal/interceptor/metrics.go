@@ -0,0 +1,30 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/metrics"
+)
+
+// MetricsInterceptor is a unary interceptor that records the request count, latency and code of the procedures.
+func (i *Interceptor) MetricsInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (
+			connect.AnyResponse, error,
+		) {
+			start := time.Now()
+			response, err := next(ctx, request)
+
+			code := metrics.StatusOK
+			if err != nil {
+				code = connect.CodeOf(err).String()
+			}
+			metrics.ObserveRPC(request.Spec().Procedure, code, time.Since(start))
+
+			return response, err
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/ratelimit"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+const (
+	// rateLimitKeyUser limits by the access token subject.
+	rateLimitKeyUser = "user"
+	// rateLimitKeyAPIKey limits by the api key header.
+	rateLimitKeyAPIKey = "api_key"
+	// rateLimitKeyIP limits by the client ip.
+	rateLimitKeyIP = "ip"
+)
+
+// newRateLimiter creates the rate limiter of the store, the store is not reloaded.
+func (i *Interceptor) newRateLimiter(store string) {
+	switch store {
+	case ratelimit.StoreMemory:
+		i.limiter = ratelimit.NewMemoryLimiter()
+	default:
+		i.limiter = ratelimit.NewRedisLimiter(i.redis)
+	}
+}
+
+// RateLimitInterceptor is a unary interceptor that rejects the calls over the procedure quota.
+func (i *Interceptor) RateLimitInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (
+			connect.AnyResponse, error,
+		) {
+			procedure := request.Spec().Procedure
+			st := i.settings.Load()
+			rule := i.rateLimitRuleOf(ctx, st, procedure)
+			if !st.rateLimitEnabled || rule.Limit <= 0 || rule.Window <= 0 {
+				return next(ctx, request)
+			}
+
+			keyType, key := i.rateLimitKey(request, rule.Key)
+			allowed, retry, err := i.limiter.Allow(ctx, procedure+":"+keyType+":"+key, rule.Limit, rule.Window)
+			if err != nil {
+				// fail open, the rate limiter must not take the service down
+				log.Ctx(ctx).Err(err).Msg("Error rate limit")
+				return next(ctx, request)
+			}
+
+			if !allowed {
+				ratelimit.ThrottledTotal.WithLabelValues(procedure, keyType).Inc()
+				return nil, errs.ResourceExhausted(retry)
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// rateLimitRuleOf returns the quota of the procedure, the permission quota takes precedence over the config.
+func (i *Interceptor) rateLimitRuleOf(ctx context.Context, st *settings, procedure string) *config.RateLimitRule {
+	if per, ok := i.getListPermissions(ctx)[procedure]; ok && per != nil && per.RateLimit > 0 {
+		return &config.RateLimitRule{
+			Procedure: procedure,
+			Limit:     per.RateLimit,
+			Window:    time.Duration(per.RateWindow) * time.Second,
+			Key:       per.RateKey,
+		}
+	}
+
+	if rule, ok := st.rateLimitRules[procedure]; ok {
+		return rule
+	}
+
+	return st.rateLimitDefault
+}
+
+// rateLimitKey returns the key type and key of the caller.
+// The user key falls back to the api key, then to the client ip.
+func (i *Interceptor) rateLimitKey(request connect.AnyRequest, keyType string) (string, string) {
+	switch keyType {
+	case rateLimitKeyIP:
+	case rateLimitKeyAPIKey:
+		if apiKey := request.Header().Get(utils.HeaderAPIKey); apiKey != "" {
+			sum := sha256.Sum256([]byte(apiKey))
+			return rateLimitKeyAPIKey, hex.EncodeToString(sum[:16])
+		}
+	default:
+		if claims := i.auditClaims(request.Header()); claims != nil {
+			return rateLimitKeyUser, claims.Subject
+		}
+
+		return i.rateLimitKey(request, rateLimitKeyAPIKey)
+	}
+
+	return rateLimitKeyIP, i.clientIP(request.Header(), request.Peer().Addr)
+}
@@ -0,0 +1,76 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/certs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/logger"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// maxRequestIDLength is the maximum length of an incoming request id.
+const maxRequestIDLength = 128
+
+// RequestIDInterceptor is a unary interceptor that accepts or generates the request id,
+// stores it in the context with the context logger and echoes it in the response.
+func (i *Interceptor) RequestIDInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (
+			connect.AnyResponse, error,
+		) {
+			requestID := request.Header().Get(utils.HeaderRequestID)
+			if !validRequestID(requestID) {
+				requestID = uuid.NewString()
+			}
+
+			ctx = logger.WithRequestID(ctx, requestID)
+			ctx = logger.With(ctx, func(c zerolog.Context) zerolog.Context {
+				c = c.
+					Str(logger.FieldRequestID, requestID).
+					Str(logger.FieldProcedure, request.Spec().Procedure)
+				if claims := i.auditClaims(request.Header()); claims != nil {
+					c = c.Str(logger.FieldUserID, claims.Subject)
+				}
+				if identity := certs.Identity(ctx); identity != "" {
+					c = c.Str(logger.FieldClientIdentity, identity)
+				}
+
+				return c
+			})
+
+			response, err := next(ctx, request)
+			if err != nil {
+				var connectErr *connect.Error
+				if errors.As(err, &connectErr) {
+					connectErr.Meta().Set(utils.HeaderRequestID, requestID)
+				}
+
+				return response, err
+			}
+
+			response.Header().Set(utils.HeaderRequestID, requestID)
+
+			return response, nil
+		}
+	}
+}
+
+// validRequestID returns true if the incoming request id can be trusted in logs and headers.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range requestID {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
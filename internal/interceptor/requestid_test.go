@@ -0,0 +1,91 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1/userbulkv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/logger"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// requestIDHandler returns the request id of its context as the user id, or fails for a missing email.
+type requestIDHandler struct {
+	userbulkv1connect.UnimplementedUserBulkServiceHandler
+}
+
+func (h *requestIDHandler) AcceptInvite(ctx context.Context, req *connect.Request[userbulkv1.AcceptInviteRequest]) (
+	*connect.Response[userbulkv1.AcceptInviteResponse], error,
+) {
+	if req.Msg.GetEmail() == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("email is required"))
+	}
+
+	return connect.NewResponse(&userbulkv1.AcceptInviteResponse{Id: logger.RequestID(ctx)}), nil
+}
+
+func TestRequestIDInterceptor(t *testing.T) {
+	i := &Interceptor{}
+	path, handler := userbulkv1connect.NewUserBulkServiceHandler(&requestIDHandler{},
+		connect.WithInterceptors(i.RequestIDInterceptor()))
+
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := userbulkv1connect.NewUserBulkServiceClient(server.Client(), server.URL)
+
+	tests := []struct {
+		name      string
+		requestID string
+		generated bool
+	}{
+		{name: "incoming id", requestID: "req-123"},
+		{name: "no id", generated: true},
+		{name: "id with spaces", requestID: "req 123", generated: true},
+		{name: "id too long", requestID: strings.Repeat("a", maxRequestIDLength+1), generated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := connect.NewRequest(&userbulkv1.AcceptInviteRequest{Email: "user@example.com"})
+			if tt.requestID != "" {
+				req.Header().Set(utils.HeaderRequestID, tt.requestID)
+			}
+
+			res, err := client.AcceptInvite(context.Background(), req)
+			if err != nil {
+				t.Fatalf("AcceptInvite() error = %v", err)
+			}
+
+			got := res.Header().Get(utils.HeaderRequestID)
+			if got != res.Msg.GetId() {
+				t.Errorf("response id = %q, context id = %q, want the same", got, res.Msg.GetId())
+			}
+
+			if tt.generated && (got == tt.requestID || !validRequestID(got)) {
+				t.Errorf("response id = %q, want a generated id", got)
+			} else if !tt.generated && got != tt.requestID {
+				t.Errorf("response id = %q, want %q", got, tt.requestID)
+			}
+		})
+	}
+
+	// the errors carry the request id too
+	req := connect.NewRequest(&userbulkv1.AcceptInviteRequest{})
+	req.Header().Set(utils.HeaderRequestID, "req-456")
+
+	_, err := client.AcceptInvite(context.Background(), req)
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Meta().Get(utils.HeaderRequestID) != "req-456" {
+		t.Errorf("AcceptInvite() error = %v, want the request id in its metadata", err)
+	}
+}
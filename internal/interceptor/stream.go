@@ -0,0 +1,122 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+
+	auditmodel "github.com/xdorro/golang-grpc-base-project/internal/module/audit/model"
+	"github.com/xdorro/golang-grpc-base-project/pkg/certs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/logger"
+	"github.com/xdorro/golang-grpc-base-project/pkg/metrics"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+var _ connect.Interceptor = (*streamInterceptor)(nil)
+
+// streamInterceptor applies the request id, metrics, authorization and audit of the unary
+// interceptors to the streaming handlers, the unary interceptors only wrap unary procedures.
+type streamInterceptor struct {
+	i *Interceptor
+}
+
+// StreamInterceptor is an interceptor for the streaming procedures.
+func (i *Interceptor) StreamInterceptor() connect.Interceptor {
+	return &streamInterceptor{i: i}
+}
+
+// WrapUnary passes the unary procedures through.
+func (s *streamInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return next
+}
+
+// WrapStreamingClient passes the streaming clients through.
+func (s *streamInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler authorizes the caller before the stream is handled.
+func (s *streamInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		i := s.i
+		header := conn.RequestHeader()
+		procedure := conn.Spec().Procedure
+
+		requestID := header.Get(utils.HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		claims := i.auditClaims(header)
+		ctx = logger.WithRequestID(ctx, requestID)
+		ctx = logger.With(ctx, func(c zerolog.Context) zerolog.Context {
+			c = c.
+				Str(logger.FieldRequestID, requestID).
+				Str(logger.FieldProcedure, procedure)
+			if claims != nil {
+				c = c.Str(logger.FieldUserID, claims.Subject)
+			}
+			if identity := certs.Identity(ctx); identity != "" {
+				c = c.Str(logger.FieldClientIdentity, identity)
+			}
+
+			return c
+		})
+
+		// the response header is sent with the first message
+		conn.ResponseHeader().Set(utils.HeaderRequestID, requestID)
+
+		start := time.Now()
+		ctx, authorized, err := s.handle(ctx, conn, next)
+
+		code := metrics.StatusOK
+		if err != nil {
+			code = connect.CodeOf(err).String()
+		}
+		metrics.ObserveRPC(procedure, code, time.Since(start))
+
+		// the denied calls are not audited, as the unary procedures
+		if authorized && i.auditEnabled && i.isMutating(procedure) {
+			data := i.auditEntry(ctx, procedure, header, conn.Peer().Addr)
+			if err != nil {
+				data.Outcome = auditmodel.OutcomeFailure
+				data.Code = connect.CodeOf(err).String()
+			}
+
+			// the request context is canceled once the stream is closed, keep the trace and logger only
+			spanContext := trace.SpanContextFromContext(ctx)
+			ctxLogger := log.Ctx(ctx)
+			i.lifecycle.Go("audit", func(jobCtx context.Context) {
+				i.auditBiz.Record(ctxLogger.WithContext(trace.ContextWithSpanContext(jobCtx, spanContext)), data)
+			})
+		}
+
+		err = errs.Sanitize(ctx, err)
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			connectErr.Meta().Set(utils.HeaderRequestID, requestID)
+		}
+
+		return err
+	}
+}
+
+// handle authorizes the procedures requiring authentication, then handles the stream.
+// It returns the context carrying the verified claims and whether the caller was authorized.
+func (s *streamInterceptor) handle(ctx context.Context, conn connect.StreamingHandlerConn,
+	next connect.StreamingHandlerFunc,
+) (context.Context, bool, error) {
+	authCtx, err := s.i.authorizeProcedure(ctx, conn.RequestHeader(), conn.Spec().Procedure)
+	if err != nil {
+		return ctx, false, err
+	}
+
+	return authCtx, true, next(authCtx, conn)
+}
@@ -0,0 +1,59 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/logger"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
+)
+
+// TracingInterceptor is a unary interceptor that starts the server span of the procedure,
+// continuing the W3C trace context of the incoming headers.
+func (i *Interceptor) TracingInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (
+			connect.AnyResponse, error,
+		) {
+			procedure := request.Spec().Procedure
+			service, method, _ := strings.Cut(strings.TrimPrefix(procedure, "/"), "/")
+
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(request.Header()))
+			ctx, span := tracing.Start(ctx, procedure,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.RPCSystemKey.String("connect_rpc"),
+					semconv.RPCServiceKey.String(service),
+					semconv.RPCMethodKey.String(method),
+					semconv.NetPeerIPKey.String(i.clientIP(request.Header(), request.Peer().Addr)),
+				),
+			)
+			defer span.End()
+
+			if requestID := logger.RequestID(ctx); requestID != "" {
+				span.SetAttributes(attribute.String("rpc.request_id", requestID))
+			}
+
+			// log lines of the request carry the trace and span ids
+			ctx = tracing.WithLogger(ctx)
+
+			response, err := next(ctx, request)
+			if err != nil {
+				code := connect.CodeOf(err).String()
+				span.RecordError(err)
+				span.SetStatus(codes.Error, code)
+				span.SetAttributes(attribute.String("rpc.connect_rpc.error_code", code))
+			}
+
+			return response, err
+		}
+	}
+}
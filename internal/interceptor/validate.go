@@ -0,0 +1,141 @@
+package interceptor
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/bufbuild/connect-go"
+
+	privacyv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/validate"
+)
+
+var (
+	objectIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+	rolePattern     = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	slugPattern     = regexp.MustCompile(`^/[A-Za-z0-9_.]+/([A-Za-z0-9_]+|\*)$`)
+)
+
+// validateRules are the declarative constraints of the request messages.
+var validateRules = validate.Rules{
+	// auth
+	"auth.v1.LoginRequest": {
+		{Field: "email", Required: true, Email: true, MaxLen: 255},
+		{Field: "password", Required: true, MaxBytes: 72},
+	},
+	"auth.v1.TokenRequest": {
+		{Field: "token", Required: true, MaxLen: 4096},
+	},
+
+	// user
+	"user.v1.FindAllUsersRequest": {
+		{Field: "page", Gte: validate.Int64(0)},
+	},
+	"user.v1.CommonUUIDRequest": {
+		{Field: "id", Required: true, Pattern: objectIDPattern},
+	},
+	"user.v1.CreateUserRequest": {
+		{Field: "name", MaxLen: 255},
+		{Field: "email", Required: true, Email: true, MaxLen: 255},
+		{Field: "password", Required: true, MaxBytes: 72},
+		{Field: "role", MaxLen: 64, Pattern: rolePattern},
+	},
+	"user.v1.UpdateUserRequest": {
+		{Field: "id", Required: true, Pattern: objectIDPattern},
+		{Field: "name", MinLen: 1, MaxLen: 255},
+		{Field: "email", Email: true, MaxLen: 255},
+		{Field: "role", MaxLen: 64, Pattern: rolePattern},
+	},
+
+	// permission
+	"permission.v1.FindAllPermissionsRequest": {
+		{Field: "page", Gte: validate.Int64(0)},
+	},
+	"permission.v1.CommonUUIDRequest": {
+		{Field: "id", Required: true, Pattern: objectIDPattern},
+	},
+	"permission.v1.CreatePermissionRequest": {
+		{Field: "name", Required: true, MaxLen: 255},
+		{Field: "slug", Required: true, MaxLen: 255, Pattern: slugPattern},
+	},
+	"permission.v1.UpdatePermissionRequest": {
+		{Field: "id", Required: true, Pattern: objectIDPattern},
+		{Field: "name", MinLen: 1, MaxLen: 255},
+		{Field: "slug", MaxLen: 255, Pattern: slugPattern},
+	},
+
+	// role
+	"role.v1.CommonNameRequest": {
+		{Field: "name", Required: true, MaxLen: 64, Pattern: rolePattern},
+	},
+	"role.v1.CreateRoleRequest": {
+		{Field: "name", Required: true, MaxLen: 64, Pattern: rolePattern},
+		{Field: "permissions", MaxItems: 1000, MaxLen: 255, Pattern: slugPattern},
+	},
+	"role.v1.UpdateRoleRequest": {
+		{Field: "name", Required: true, MaxLen: 64, Pattern: rolePattern},
+		{Field: "permissions", MaxItems: 1000, MaxLen: 255, Pattern: slugPattern},
+	},
+
+	// audit
+	"audit.v1.FindAllAuditsRequest": {
+		{Field: "page", Gte: validate.Int64(0)},
+		{Field: "actor", MaxLen: 255},
+		{Field: "target", MaxLen: 255},
+		{Field: "procedure", MaxLen: 255},
+		{Field: "from", Gte: validate.Int64(0)},
+		{Field: "to", Gte: validate.Int64(0)},
+	},
+
+	// userbulk
+	"userbulk.v1.AcceptInviteRequest": {
+		{Field: "email", Required: true, Email: true, MaxLen: 255},
+		{Field: "token", Required: true, MaxLen: 255},
+		{Field: "password", Required: true, MaxBytes: 72},
+	},
+
+	// userstate
+	"userstate.v1.UserStateRequest": {
+		{Field: "id", Required: true, Pattern: objectIDPattern},
+		{Field: "reason", MaxLen: 512},
+	},
+	"userstate.v1.FindUserStateRequest": {
+		{Field: "id", Required: true, Pattern: objectIDPattern},
+	},
+
+	// retention
+	"retention.v1.RecordRequest": {
+		{Field: "id", Required: true, Pattern: objectIDPattern},
+	},
+
+	// privacy
+	"privacy.v1.RequestErasureRequest": {
+		{Field: "reason", MaxLen: 512},
+	},
+	"privacy.v1.FindAllErasuresRequest": {
+		{Field: "page", Gte: validate.Int64(0)},
+		{Field: "status", In: []string{
+			privacyv1.ErasurePending, privacyv1.ErasureRejected, privacyv1.ErasureCompleted,
+		}},
+	},
+	"privacy.v1.ReviewErasureRequest": {
+		{Field: "id", Required: true, Pattern: objectIDPattern},
+		{Field: "note", MaxLen: 512},
+	},
+}
+
+// ValidateInterceptor is a unary interceptor that validates the request messages.
+func (i *Interceptor) ValidateInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (
+			connect.AnyResponse, error,
+		) {
+			if violations := validateRules.Validate(request.Any()); len(violations) > 0 {
+				return nil, errs.Validation(violations...)
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
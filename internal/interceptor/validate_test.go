@@ -0,0 +1,40 @@
+package interceptor
+
+import (
+	"testing"
+
+	authv1 "github.com/xdorro/proto-base-project/proto-gen-go/auth/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	// the messages of the services the interceptor does not import
+	_ "github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1"
+	_ "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+)
+
+func TestValidateRules(t *testing.T) {
+	for name, rules := range validateRules {
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(name)
+		if err != nil {
+			t.Errorf("message %s is not registered", name)
+			continue
+		}
+
+		for _, rule := range rules {
+			if mt.Descriptor().Fields().ByName(protoreflect.Name(rule.Field)) == nil {
+				t.Errorf("message %s has no field %s", name, rule.Field)
+			}
+		}
+	}
+}
+
+func TestValidateLogin(t *testing.T) {
+	if got := validateRules.Validate(&authv1.LoginRequest{Email: "user@example.com", Password: "secret"}); len(got) != 0 {
+		t.Errorf("Validate() = %v, want no violation", got)
+	}
+
+	got := validateRules.Validate(&authv1.LoginRequest{Email: "user"})
+	if len(got) != 2 || got[0].Field != "email" || got[1].Field != "password" {
+		t.Errorf("Validate() = %v, want the email and password violations", got)
+	}
+}
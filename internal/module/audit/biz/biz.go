@@ -0,0 +1,308 @@
+package auditbiz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/rs/zerolog/log"
+	"github.com/xdorro/proto-base-project/proto-gen-go/permission/v1/permissionv1connect"
+	"github.com/xdorro/proto-base-project/proto-gen-go/user/v1/userv1connect"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	auditmodel "github.com/xdorro/golang-grpc-base-project/internal/module/audit/model"
+	permissionmodel "github.com/xdorro/golang-grpc-base-project/internal/module/permission/model"
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	auditv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userstate/v1/userstatev1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+var _ IAuditBiz = &Biz{}
+
+// IAuditBiz audit service interface.
+type IAuditBiz interface {
+	Record(ctx context.Context, data *auditmodel.Audit)
+	Snapshot(ctx context.Context, procedure string, targets []string) map[string]any
+	FindAllAudits(ctx context.Context, req *connect.Request[auditv1.FindAllAuditsRequest]) (
+		*connect.Response[auditv1.FindAllAuditsResponse], error,
+	)
+
+	// FindBySubject returns the entries of which the user is the actor or a target, the most recent first.
+	FindBySubject(ctx context.Context, subject string) ([]*auditmodel.Audit, error)
+	// Redact removes the personal data of the user from the entries, they are kept otherwise, and
+	// records the redaction by the actor. It is the only update of the append only entries.
+	// It returns the number of updates, an entry of which the user is the actor and a target counts twice.
+	Redact(ctx context.Context, subject, actor string) (int64, error)
+}
+
+// personalFields are the changed fields holding personal data.
+var personalFields = []string{"name", "email", "status_reason"}
+
+// Biz struct.
+type Biz struct {
+	retention time.Duration
+
+	// option
+	auditCollection *mongo.Collection
+	snapshots       map[string]*mongo.Collection
+}
+
+// Option service option.
+type Option struct {
+	Config *config.Config
+	Repo   repo.IRepo
+}
+
+// NewBiz new service.
+func NewBiz(opt *Option) IAuditBiz {
+	s := &Biz{
+		retention:       opt.Config.Audit.Retention,
+		auditCollection: opt.Repo.CollectionModel(&auditmodel.Audit{}),
+		snapshots: map[string]*mongo.Collection{
+			userv1connect.UserServiceName:             opt.Repo.CollectionModel(&usermodel.User{}),
+			permissionv1connect.PermissionServiceName: opt.Repo.CollectionModel(&permissionmodel.Permission{}),
+			userstatev1connect.UserStateServiceName:   opt.Repo.CollectionModel(&usermodel.User{}),
+		},
+	}
+
+	ctx := context.Background()
+	if _, err := repo.CreateIndexes(ctx, s.auditCollection, (&auditmodel.Audit{}).GetIndexModels()); err != nil {
+		log.Err(err).Msg("Error create audit indexes")
+	}
+	if err := s.expireAfter(ctx); err != nil {
+		log.Err(err).
+			Dur("retention", s.retention).
+			Msg("Error set audit retention")
+	}
+
+	return s
+}
+
+// expireIndex is the name of the index expiring the audit entries.
+const expireIndex = "created_at_1"
+
+// mongo error codes of the index changes.
+const (
+	namespaceNotFound    = 26
+	indexNotFound        = 27
+	indexOptionsConflict = 85
+)
+
+// expireAfter creates the index expiring the audit entries after the retention period. The index of
+// a previous retention is updated in place, since it can not be created again with other options,
+// and dropped when the retention is disabled.
+func (s *Biz) expireAfter(ctx context.Context) error {
+	if s.retention <= 0 {
+		_, err := s.auditCollection.Indexes().DropOne(ctx, expireIndex)
+		if hasErrorCode(err, namespaceNotFound, indexNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	seconds := int32(s.retention.Seconds())
+	_, err := s.auditCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetName(expireIndex).SetExpireAfterSeconds(seconds),
+	})
+	if !hasErrorCode(err, indexOptionsConflict) {
+		return err
+	}
+
+	return s.auditCollection.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: s.auditCollection.Name()},
+		{Key: "index", Value: bson.D{
+			{Key: "name", Value: expireIndex},
+			{Key: "expireAfterSeconds", Value: seconds},
+		}},
+	}).Err()
+}
+
+// hasErrorCode returns true if err is a server error with one of the codes.
+func hasErrorCode(err error, codes ...int) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+
+	for _, code := range codes {
+		if serverErr.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Record appends an audit entry.
+func (s *Biz) Record(ctx context.Context, data *auditmodel.Audit) {
+	data.PreCreate()
+
+	if _, err := repo.InsertOne(ctx, s.auditCollection, data); err != nil {
+		log.Ctx(ctx).Err(err).
+			Str("procedure", data.Procedure).
+			Msg("Error record audit")
+	}
+}
+
+// Snapshot returns the stored document of the first target, used to compute the audit diff.
+func (s *Biz) Snapshot(ctx context.Context, procedure string, targets []string) map[string]any {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	service := strings.Split(strings.TrimPrefix(procedure, "/"), "/")[0]
+	collection, ok := s.snapshots[service]
+	if !ok {
+		return nil
+	}
+
+	filter := repo.IDFilter(targets[0])
+	opt := options.
+		FindOne().
+		SetProjection(bson.M{"_id": 0, "password": 0, "invite_token": 0, "updated_at": 0})
+
+	data, err := repo.FindOne[bson.M](ctx, collection, filter, opt)
+	if err != nil {
+		return nil
+	}
+
+	return *data
+}
+
+// FindAllAudits is the audit.v1.AuditBiz.FindAllAudits method.
+func (s *Biz) FindAllAudits(ctx context.Context, req *connect.Request[auditv1.FindAllAuditsRequest]) (
+	*connect.Response[auditv1.FindAllAuditsResponse], error,
+) {
+	ctx, span := tracing.Start(ctx, "auditbiz.FindAllAudits")
+	defer span.End()
+
+	// count all audits with filter
+	filter := bson.M{}
+	if actor := req.Msg.Actor; actor != "" {
+		filter["actor"] = actor
+	}
+
+	if target := req.Msg.Target; target != "" {
+		filter["targets"] = target
+	}
+
+	if procedure := req.Msg.Procedure; procedure != "" {
+		filter["procedure"] = procedure
+	}
+
+	createdAt := bson.M{}
+	if req.Msg.From > 0 {
+		createdAt["$gte"] = time.Unix(req.Msg.From, 0)
+	}
+
+	if req.Msg.To > 0 {
+		createdAt["$lte"] = time.Unix(req.Msg.To, 0)
+	}
+
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	count, err := repo.CountDocuments(ctx, s.auditCollection, filter)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "audit", err)
+	}
+	limit := int64(10)
+	totalPages := utils.TotalPage(count, limit)
+	page := utils.CurrentPage(req.Msg.Page, totalPages)
+
+	// find all audits with filter and option
+	opt := options.
+		Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetLimit(limit).
+		SetSkip((page - 1) * limit)
+	data, err := repo.Find[auditmodel.Audit](ctx, s.auditCollection, filter, opt)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "audit", err)
+	}
+
+	res := &auditv1.FindAllAuditsResponse{
+		TotalPage:   totalPages,
+		CurrentPage: page,
+		Data:        auditmodel.AuditsToProto(data),
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// FindBySubject returns the entries of the user.
+func (s *Biz) FindBySubject(ctx context.Context, subject string) ([]*auditmodel.Audit, error) {
+	ctx, span := tracing.Start(ctx, "auditbiz.FindBySubject")
+	defer span.End()
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"actor": subject},
+			bson.M{"targets": subject},
+		},
+	}
+	opt := options.
+		Find().
+		SetSort(bson.M{"created_at": -1})
+
+	data, err := repo.Find[auditmodel.Audit](ctx, s.auditCollection, filter, opt)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "audit", err)
+	}
+
+	return data, nil
+}
+
+// Redact removes the personal fields of the changes of the entries targeting the user, and the
+// client ip of the entries of which it is the actor. The actors, targets and outcomes are kept,
+// so the trail stays complete. The entries are otherwise append only, so the redaction is recorded
+// as an entry of its own, with the actor and the number of updates.
+func (s *Biz) Redact(ctx context.Context, subject, actor string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "auditbiz.Redact")
+	defer span.End()
+
+	now := time.Now()
+	unset := bson.M{}
+	for _, field := range personalFields {
+		unset["changes."+field] = ""
+	}
+
+	targets, err := repo.UpdateMany(ctx, s.auditCollection, bson.M{"targets": subject}, bson.M{
+		"$set":   bson.M{"redacted_at": now},
+		"$unset": unset,
+	})
+	if err != nil {
+		return 0, errs.FromRepo(ctx, "audit", err)
+	}
+
+	actors, err := repo.UpdateMany(ctx, s.auditCollection, bson.M{"actor": subject}, bson.M{
+		"$set":   bson.M{"redacted_at": now},
+		"$unset": bson.M{"client_ip": ""},
+	})
+	if err != nil {
+		return 0, errs.FromRepo(ctx, "audit", err)
+	}
+
+	redacted := targets.ModifiedCount + actors.ModifiedCount
+	s.Record(ctx, &auditmodel.Audit{
+		Actor:     actor,
+		Procedure: auditmodel.ProcedureRedact,
+		Targets:   []string{subject},
+		Changes: map[string]*auditmodel.Change{
+			"redacted_audits": {After: redacted},
+		},
+		Outcome: auditmodel.OutcomeSuccess,
+	})
+
+	return redacted, nil
+}
@@ -0,0 +1,31 @@
+package auditbiz
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestHasErrorCode(t *testing.T) {
+	conflict := mongo.CommandError{Code: indexOptionsConflict, Name: "IndexOptionsConflict"}
+
+	tests := []struct {
+		name  string
+		err   error
+		codes []int
+		want  bool
+	}{
+		{"nil", nil, []int{indexOptionsConflict}, false},
+		{"other error", fmt.Errorf("connection refused"), []int{indexOptionsConflict}, false},
+		{"same code", conflict, []int{indexOptionsConflict}, true},
+		{"wrapped", fmt.Errorf("create index: %w", conflict), []int{indexOptionsConflict}, true},
+		{"one of the codes", mongo.CommandError{Code: indexNotFound}, []int{namespaceNotFound, indexNotFound}, true},
+		{"other code", mongo.CommandError{Code: indexNotFound}, []int{indexOptionsConflict}, false},
+	}
+	for _, tt := range tests {
+		if got := hasErrorCode(tt.err, tt.codes...); got != tt.want {
+			t.Errorf("hasErrorCode(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package auditmodel
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	auditv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+const (
+	// OutcomeSuccess is the outcome of a succeeded procedure.
+	OutcomeSuccess = "success"
+	// OutcomeFailure is the outcome of a failed procedure.
+	OutcomeFailure = "failure"
+
+	// ProcedureRedact is the procedure of the entries recording a redaction, the one update of the
+	// otherwise append only entries.
+	ProcedureRedact = "audit.redact"
+)
+
+var _ IAudit = &Audit{}
+
+// IAudit is the interface for an audit
+type IAudit interface {
+	utils.IBaseModel
+}
+
+// Audit is an audit struct.
+type Audit struct {
+	utils.BaseModel `bson:",inline"`
+
+	Actor     string             `json:"actor,omitempty" bson:"actor,omitempty"`
+	Role      string             `json:"role,omitempty" bson:"role,omitempty"`
+	Tenant    string             `json:"tenant,omitempty" bson:"tenant,omitempty"`
+	Procedure string             `json:"procedure,omitempty" bson:"procedure,omitempty"`
+	Targets   []string           `json:"targets,omitempty" bson:"targets,omitempty"`
+	Changes   map[string]*Change `json:"changes,omitempty" bson:"changes,omitempty"`
+	ClientIP  string             `json:"client_ip,omitempty" bson:"client_ip,omitempty"`
+	Outcome   string             `json:"outcome,omitempty" bson:"outcome,omitempty"`
+	Code      string             `json:"code,omitempty" bson:"code,omitempty"`
+
+	// RedactedAt is set once the personal data of an erased user are removed from the entry.
+	RedactedAt time.Time `json:"-" bson:"redacted_at,omitempty"`
+}
+
+// Change is the before and after value of a changed field.
+type Change struct {
+	Before any `json:"before,omitempty" bson:"before,omitempty"`
+	After  any `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// CollectionName returns the name of the collection from struct name
+func (m *Audit) CollectionName() string {
+	return utils.CollectionName(m)
+}
+
+// GetIndexModels returns the index models
+func (m *Audit) GetIndexModels() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "targets", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "procedure", Value: 1}, {Key: "created_at", Value: -1}}},
+	}
+}
+
+// PreCreate is a callback that gets called before creating a models.
+func (m *Audit) PreCreate() {
+	m.BaseModel.PreCreate()
+}
+
+// PreUpdate is a callback that gets called before updating a models.
+func (m *Audit) PreUpdate() {
+	m.BaseModel.PreUpdate()
+}
+
+// Diff returns the changed fields between two snapshots.
+func Diff(before, after map[string]any) map[string]*Change {
+	changes := make(map[string]*Change)
+
+	for key, value := range before {
+		if next, ok := after[key]; !ok || !equal(value, next) {
+			changes[key] = &Change{Before: value, After: after[key]}
+		}
+	}
+
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changes[key] = &Change{After: value}
+		}
+	}
+
+	return changes
+}
+
+// equal compares two snapshot values.
+func equal(a, b any) bool {
+	aBytes, _ := bson.Marshal(bson.M{"v": a})
+	bBytes, _ := bson.Marshal(bson.M{"v": b})
+
+	return string(aBytes) == string(bBytes)
+}
+
+// AuditToProto converts an audit to a proto
+func AuditToProto(m *Audit) *auditv1.Audit {
+	changes := make(map[string]*auditv1.Change, len(m.Changes))
+	for key, change := range m.Changes {
+		changes[key] = &auditv1.Change{
+			Before: toValue(change.Before),
+			After:  toValue(change.After),
+		}
+	}
+
+	return &auditv1.Audit{
+		Id:        m.Id,
+		Actor:     m.Actor,
+		Role:      m.Role,
+		Tenant:    m.Tenant,
+		Procedure: m.Procedure,
+		Targets:   m.Targets,
+		Changes:   changes,
+		ClientIp:  m.ClientIP,
+		Outcome:   m.Outcome,
+		Code:      m.Code,
+		Redacted:  !m.RedactedAt.IsZero(),
+		CreatedAt: m.CreatedAt.Unix(),
+	}
+}
+
+// toValue converts a snapshot value to a proto value through its json representation,
+// so the bson types are written as in the json of the models.
+func toValue(v any) *structpb.Value {
+	if v == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	value := &structpb.Value{}
+	if err = protojson.Unmarshal(data, value); err != nil {
+		return nil
+	}
+
+	return value
+}
+
+// AuditsToProto converts a slice of audits to a slice of proto
+func AuditsToProto(list []*Audit) []*auditv1.Audit {
+	return utils.ToProto[Audit, auditv1.Audit](list, AuditToProto)
+}
@@ -0,0 +1,80 @@
+package auditmodel
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/proto"
+
+	auditv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1"
+)
+
+func TestDiff(t *testing.T) {
+	before := map[string]any{"name": "old", "role": "user", "email": "a@example.com"}
+	after := map[string]any{"name": "new", "role": "user", "status": int32(2)}
+
+	changes := Diff(before, after)
+	if len(changes) != 3 {
+		t.Fatalf("Diff() = %d changes, want 3", len(changes))
+	}
+
+	if change := changes["name"]; change == nil || change.Before != "old" || change.After != "new" {
+		t.Errorf("Diff() name = %+v, want old to new", change)
+	}
+
+	if change := changes["email"]; change == nil || change.Before != "a@example.com" || change.After != nil {
+		t.Errorf("Diff() email = %+v, want a removed value", change)
+	}
+
+	if change := changes["status"]; change == nil || change.Before != nil || change.After != int32(2) {
+		t.Errorf("Diff() status = %+v, want an added value", change)
+	}
+
+	if _, ok := changes["role"]; ok {
+		t.Error("Diff() reports the unchanged role")
+	}
+}
+
+func TestAuditToProto(t *testing.T) {
+	id := primitive.NewObjectID()
+	m := &Audit{
+		Actor:     id.Hex(),
+		Procedure: "/user.v1.UserService/UpdateUser",
+		Targets:   []string{id.Hex()},
+		Changes: map[string]*Change{
+			"name": {Before: "old", After: "new"},
+			"_id":  {After: id},
+		},
+		Outcome:    OutcomeSuccess,
+		RedactedAt: time.Now(),
+	}
+	m.Id = id.Hex()
+	m.CreatedAt = time.Date(2262, 4, 11, 0, 0, 0, 0, time.UTC)
+
+	data, err := proto.Marshal(AuditToProto(m))
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+
+	got := &auditv1.Audit{}
+	if err = proto.Unmarshal(data, got); err != nil {
+		t.Fatalf("proto.Unmarshal() error = %v", err)
+	}
+
+	if got.GetCreatedAt() != m.CreatedAt.Unix() {
+		t.Errorf("created_at = %d, want %d", got.GetCreatedAt(), m.CreatedAt.Unix())
+	}
+
+	if !got.GetRedacted() {
+		t.Error("redacted = false, want true")
+	}
+
+	if before := got.GetChanges()["name"].GetBefore().GetStringValue(); before != "old" {
+		t.Errorf("changes.name.before = %q, want old", before)
+	}
+
+	if after := got.GetChanges()["_id"].GetAfter().GetStringValue(); after != id.Hex() {
+		t.Errorf("changes._id.after = %q, want %q", after, id.Hex())
+	}
+}
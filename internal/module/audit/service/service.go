@@ -0,0 +1,47 @@
+package auditservice
+
+import (
+	"context"
+
+	"github.com/bufbuild/connect-go"
+
+	auditbiz "github.com/xdorro/golang-grpc-base-project/internal/module/audit/biz"
+	auditv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1/auditv1connect"
+)
+
+var _ IAuditService = &Service{}
+
+// IAuditService audit service interface.
+type IAuditService interface {
+	auditv1connect.AuditServiceHandler
+}
+
+// Service struct.
+type Service struct {
+	// option
+	auditBiz auditbiz.IAuditBiz
+
+	auditv1connect.UnimplementedAuditServiceHandler
+}
+
+// Option service option.
+type Option struct {
+	AuditBiz auditbiz.IAuditBiz
+}
+
+// NewService new service.
+func NewService(opt *Option) IAuditService {
+	s := &Service{
+		auditBiz: opt.AuditBiz,
+	}
+
+	return s
+}
+
+// FindAllAudits is the audit.v1.AuditService.FindAllAudits method.
+func (s *Service) FindAllAudits(ctx context.Context, req *connect.Request[auditv1.FindAllAuditsRequest]) (
+	*connect.Response[auditv1.FindAllAuditsResponse], error,
+) {
+	return s.auditBiz.FindAllAudits(ctx, req)
+}
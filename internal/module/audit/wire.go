@@ -0,0 +1,14 @@
+package auditmodule
+
+import (
+	"github.com/google/wire"
+
+	auditbiz "github.com/xdorro/golang-grpc-base-project/internal/module/audit/biz"
+	auditservice "github.com/xdorro/golang-grpc-base-project/internal/module/audit/service"
+)
+
+// ProviderModuleSet is Module providers.
+var ProviderModuleSet = wire.NewSet(
+	auditbiz.ProviderBizSet,
+	auditservice.ProviderServiceSet,
+)
@@ -1,12 +1,12 @@
 package authbiz
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"time"
 
 	"github.com/bufbuild/connect-go"
 	"github.com/golang-jwt/jwt/v4"
-	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	authv1 "github.com/xdorro/proto-base-project/proto-gen-go/auth/v1"
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,8 +14,12 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/xdorro/golang-grpc-base-project/config"
 	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
 	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
 	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
 )
 
@@ -23,52 +27,70 @@ var _ IAuthBiz = &Biz{}
 
 // IAuthBiz auth service interface.
 type IAuthBiz interface {
-	Login(req *connect.Request[authv1.LoginRequest]) (*connect.Response[authv1.TokenResponse], error)
-	RevokeToken(req *connect.Request[authv1.TokenRequest]) (*connect.Response[authv1.CommonResponse], error)
-	RefreshToken(req *connect.Request[authv1.TokenRequest]) (*connect.Response[authv1.TokenResponse], error)
+	Login(ctx context.Context, req *connect.Request[authv1.LoginRequest]) (*connect.Response[authv1.TokenResponse], error)
+	RevokeToken(ctx context.Context, req *connect.Request[authv1.TokenRequest]) (*connect.Response[authv1.CommonResponse], error)
+	RefreshToken(ctx context.Context, req *connect.Request[authv1.TokenRequest]) (*connect.Response[authv1.TokenResponse], error)
 }
 
 // Biz struct.
 type Biz struct {
+	// jwt keys, replaced when rotated
+	jwt *config.JWT
+
 	// option
+	session        session.ISession
 	userCollection *mongo.Collection
 }
 
 // Option service option.
 type Option struct {
-	Repo repo.IRepo
+	Config  *config.Config
+	Repo    repo.IRepo
+	Session session.ISession
 }
 
 // NewBiz new service.
 func NewBiz(opt *Option) IAuthBiz {
 	s := &Biz{
+		jwt:            &opt.Config.JWT,
+		session:        opt.Session,
 		userCollection: opt.Repo.CollectionModel(&usermodel.User{}),
 	}
 
 	return s
 }
 
-func (s *Biz) Login(req *connect.Request[authv1.LoginRequest]) (
+func (s *Biz) Login(ctx context.Context, req *connect.Request[authv1.LoginRequest]) (
 	*connect.Response[authv1.TokenResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "authbiz.Login")
+	defer span.End()
+
 	filter := bson.M{
 		"email": req.Msg.GetEmail(),
 		"deleted_at": bson.M{
 			"$exists": false,
 		},
 	}
-	data, err := repo.FindOne[usermodel.User](s.userCollection, filter)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	data, err := repo.FindOne[usermodel.User](ctx, s.userCollection, filter)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errs.Unauthenticated("email or password is incorrect")
+	} else if err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
 	}
 
 	// verify password
 	if !data.ComparePassword(req.Msg.GetPassword()) {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("password is incorrect"))
+		return nil, errs.Unauthenticated("email or password is incorrect")
+	}
+
+	// the state is checked after the password, so it is not disclosed to anyone else
+	if err = checkState(data); err != nil {
+		return nil, err
 	}
 
 	// generate a new auth token
-	res, err := s.generateAuthToken(data)
+	res, err := s.generateAuthToken(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -77,15 +99,18 @@ func (s *Biz) Login(req *connect.Request[authv1.LoginRequest]) (
 }
 
 // RevokeToken is the auth.v1.AuthBiz.RevokeToken method.
-func (s *Biz) RevokeToken(req *connect.Request[authv1.TokenRequest]) (
+func (s *Biz) RevokeToken(ctx context.Context, req *connect.Request[authv1.TokenRequest]) (
 	*connect.Response[authv1.CommonResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "authbiz.RevokeToken")
+	defer span.End()
+
 	token := req.Msg.GetToken()
 
 	// verify & remove old token
-	_, err := s.removeAuthToken(token)
+	_, err := s.removeAuthToken(ctx, token)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, err
 	}
 
 	res := &authv1.CommonResponse{
@@ -96,19 +121,22 @@ func (s *Biz) RevokeToken(req *connect.Request[authv1.TokenRequest]) (
 }
 
 // RefreshToken is the auth.v1.AuthBiz.RefreshToken method.
-func (s *Biz) RefreshToken(req *connect.Request[authv1.TokenRequest]) (
+func (s *Biz) RefreshToken(ctx context.Context, req *connect.Request[authv1.TokenRequest]) (
 	*connect.Response[authv1.TokenResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "authbiz.RefreshToken")
+	defer span.End()
+
 	// verify & remove old token
-	claims, err := s.removeAuthToken(req.Msg.GetToken())
+	claims, err := s.removeAuthToken(ctx, req.Msg.GetToken())
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, err
 	}
 
 	id, err := primitive.ObjectIDFromHex(claims.Subject)
 	if err != nil {
-		log.Err(err).Msg("Failed find user by id")
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		log.Ctx(ctx).Err(err).Msg("Failed find user by id")
+		return nil, errs.Unauthenticated("token is invalid")
 	}
 
 	filter := bson.M{
@@ -117,13 +145,17 @@ func (s *Biz) RefreshToken(req *connect.Request[authv1.TokenRequest]) (
 			"$exists": false,
 		},
 	}
-	data, err := repo.FindOne[usermodel.User](s.userCollection, filter)
+	data, err := repo.FindOne[usermodel.User](ctx, s.userCollection, filter)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "user", err)
+	}
+
+	if err = checkState(data); err != nil {
+		return nil, err
 	}
 
 	// generate a new auth token
-	res, err := s.generateAuthToken(data)
+	res, err := s.generateAuthToken(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -131,13 +163,22 @@ func (s *Biz) RefreshToken(req *connect.Request[authv1.TokenRequest]) (
 	return connect.NewResponse(res), nil
 }
 
+// checkState returns an error if the user is not active.
+func checkState(data *usermodel.User) error {
+	if data.IsActive() {
+		return nil
+	}
+
+	return errs.FailedPrecondition(errs.ReasonUserInactive, "user is "+usermodel.StatusName(data.State()))
+}
+
 // generateAuthToken generates a new auth token for the user.
-func (s *Biz) generateAuthToken(data *usermodel.User) (
+func (s *Biz) generateAuthToken(ctx context.Context, data *usermodel.User) (
 	*authv1.TokenResponse, error,
 ) {
 	uid := data.Id
-	sessionID := uuid.NewString()
 	now := time.Now()
+	sessionID := session.NewID(now)
 	refreshExpire := now.Add(utils.RefreshExpire)
 	accessExpire := now.Add(utils.AccessExpire)
 
@@ -151,31 +192,26 @@ func (s *Biz) generateAuthToken(data *usermodel.User) (
 	// Create a new refreshToken
 	eg.Go(func() error {
 		var err error
-		result.RefreshToken, err = utils.EncryptToken(&jwt.RegisteredClaims{
+		result.RefreshToken, err = utils.EncryptToken(s.jwt.PrivateKey(), &jwt.RegisteredClaims{
 			Subject:   uid,
 			ExpiresAt: jwt.NewNumericDate(refreshExpire),
+			IssuedAt:  jwt.NewNumericDate(now),
 			ID:        sessionID,
 		})
 		if err != nil {
 			return err
 		}
 
-		// key := fmt.Sprintf(utils.AuthSessionKey, uid, sessionID)
-		// err = redis.Set(s.redis, key, result.RefreshToken, utils.RefreshExpire)
-		// if err != nil {
-		// 	log.Err(err).Msg("Failed to set auth session")
-		// 	return err
-		// }
-
 		return nil
 	})
 
 	// Create a new accessToken
 	eg.Go(func() error {
 		var err error
-		result.AccessToken, err = utils.EncryptToken(&jwt.RegisteredClaims{
+		result.AccessToken, err = utils.EncryptToken(s.jwt.PrivateKey(), &jwt.RegisteredClaims{
 			Subject:   uid,
 			ExpiresAt: jwt.NewNumericDate(accessExpire),
+			IssuedAt:  jwt.NewNumericDate(now),
 			ID:        sessionID,
 			Audience:  []string{data.Role},
 		})
@@ -187,34 +223,43 @@ func (s *Biz) generateAuthToken(data *usermodel.User) (
 	})
 
 	if err := eg.Wait(); err != nil {
+		return nil, errs.Internal(ctx, err)
+	}
 
-		return nil, err
+	// the list of the active sessions is informative, the login goes on without it
+	if err := s.session.Track(ctx, uid, sessionID, refreshExpire); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to track session")
 	}
 
 	return result, nil
 }
 
-// removeAuthToken removes the auth token from the redis.
-func (s *Biz) removeAuthToken(token string) (*jwt.RegisteredClaims, error) {
+// removeAuthToken verifies the auth token and revokes its session.
+func (s *Biz) removeAuthToken(ctx context.Context, token string) (*jwt.RegisteredClaims, error) {
 	// verify refresh token
-	claims, err := utils.DecryptToken(token)
+	claims, err := utils.DecryptToken(s.jwt.PublicKey(), token)
 	if err != nil {
-		return nil, err
+		log.Ctx(ctx).Err(err).Msg("Error decrypt token")
+		return nil, errs.Unauthenticated("token is invalid")
 	}
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Interface("claims", claims).
 		Msg("Token decrypted")
 
-	// // check if the refresh token is existed
-	// key := fmt.Sprintf(utils.AuthSessionKey, claims.Subject, claims.ID)
-	// if check := redis.Exists(s.redis, key); !check {
-	// 	return nil, fmt.Errorf("token is not found")
-	// }
-	//
-	// if err = redis.Del(s.redis, key); err != nil {
-	// 	return nil, err
-	// }
+	// a token can be used only once
+	revoked, err := s.session.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return nil, errs.Unauthenticated("token is revoked")
+	}
+
+	if err = s.session.Revoke(ctx, claims); err != nil {
+		return nil, err
+	}
 
 	return claims, nil
 }
@@ -40,22 +40,22 @@ func NewService(opt *Option) IAuthService {
 }
 
 // Login is the auth.v1.AuthService.Login method.
-func (s *Service) Login(_ context.Context, req *connect.Request[authv1.LoginRequest]) (
+func (s *Service) Login(ctx context.Context, req *connect.Request[authv1.LoginRequest]) (
 	*connect.Response[authv1.TokenResponse], error,
 ) {
-	return s.authBiz.Login(req)
+	return s.authBiz.Login(ctx, req)
 }
 
 // RevokeToken is the auth.v1.AuthService.RevokeToken method.
-func (s *Service) RevokeToken(_ context.Context, req *connect.Request[authv1.TokenRequest]) (
+func (s *Service) RevokeToken(ctx context.Context, req *connect.Request[authv1.TokenRequest]) (
 	*connect.Response[authv1.CommonResponse], error,
 ) {
-	return s.authBiz.RevokeToken(req)
+	return s.authBiz.RevokeToken(ctx, req)
 }
 
 // RefreshToken is the auth.v1.AuthService.RefreshToken method.
-func (s *Service) RefreshToken(_ context.Context, req *connect.Request[authv1.TokenRequest]) (
+func (s *Service) RefreshToken(ctx context.Context, req *connect.Request[authv1.TokenRequest]) (
 	*connect.Response[authv1.TokenResponse], error,
 ) {
-	return s.authBiz.RefreshToken(req)
+	return s.authBiz.RefreshToken(ctx, req)
 }
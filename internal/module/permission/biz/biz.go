@@ -1,8 +1,7 @@
 package permissionbiz
 
 import (
-	"fmt"
-
+	"context"
 	"github.com/bufbuild/connect-go"
 	"github.com/rs/zerolog/log"
 	permissionv1 "github.com/xdorro/proto-base-project/proto-gen-go/permission/v1"
@@ -12,7 +11,11 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	permissionmodel "github.com/xdorro/golang-grpc-base-project/internal/module/permission/model"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1/retentionv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
 	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
 	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
 )
 
@@ -20,19 +23,19 @@ var _ IPermissionBiz = &Biz{}
 
 // IPermissionBiz permission service interface.
 type IPermissionBiz interface {
-	FindAllPermissions(req *connect.Request[permissionv1.FindAllPermissionsRequest]) (
+	FindAllPermissions(ctx context.Context, req *connect.Request[permissionv1.FindAllPermissionsRequest]) (
 		*connect.Response[permissionv1.FindAllPermissionsResponse], error,
 	)
-	FindPermissionByID(req *connect.Request[permissionv1.CommonUUIDRequest]) (
+	FindPermissionByID(ctx context.Context, req *connect.Request[permissionv1.CommonUUIDRequest]) (
 		*connect.Response[permissionv1.Permission], error,
 	)
-	CreatePermission(req *connect.Request[permissionv1.CreatePermissionRequest]) (
+	CreatePermission(ctx context.Context, req *connect.Request[permissionv1.CreatePermissionRequest]) (
 		*connect.Response[permissionv1.CommonResponse], error,
 	)
-	UpdatePermission(req *connect.Request[permissionv1.UpdatePermissionRequest]) (
+	UpdatePermission(ctx context.Context, req *connect.Request[permissionv1.UpdatePermissionRequest]) (
 		*connect.Response[permissionv1.CommonResponse], error,
 	)
-	DeletePermission(req *connect.Request[permissionv1.CommonUUIDRequest]) (
+	DeletePermission(ctx context.Context, req *connect.Request[permissionv1.CommonUUIDRequest]) (
 		*connect.Response[permissionv1.CommonResponse], error,
 	)
 }
@@ -40,17 +43,20 @@ type IPermissionBiz interface {
 // Biz struct.
 type Biz struct {
 	// option
+	casbin               casbin.ICasbin
 	permissionCollection *mongo.Collection
 }
 
 // Option service option.
 type Option struct {
-	Repo repo.IRepo
+	Repo   repo.IRepo
+	Casbin casbin.ICasbin
 }
 
 // NewBiz new service.
 func NewBiz(opt *Option) IPermissionBiz {
 	s := &Biz{
+		casbin:               opt.Casbin,
 		permissionCollection: opt.Repo.CollectionModel(&permissionmodel.Permission{}),
 	}
 
@@ -58,16 +64,30 @@ func NewBiz(opt *Option) IPermissionBiz {
 }
 
 // FindAllPermissions is the permission.v1.PermissionBiz.FindAllPermissions method.
-func (s *Biz) FindAllPermissions(req *connect.Request[permissionv1.FindAllPermissionsRequest]) (
+func (s *Biz) FindAllPermissions(ctx context.Context, req *connect.Request[permissionv1.FindAllPermissionsRequest]) (
 	*connect.Response[permissionv1.FindAllPermissionsResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "permissionbiz.FindAllPermissions")
+	defer span.End()
+
+	// the deleted permissions are listed to the roles restoring them
+	includeDeleted, err := casbin.IncludeDeleted(ctx, s.casbin, req.Header(),
+		"/"+retentionv1connect.RetentionServiceName+"/RestorePermission")
+	if err != nil {
+		return nil, err
+	}
+
 	// count all permissions with filter
-	filter := bson.M{
-		"deleted_at": bson.M{
+	filter := bson.M{}
+	if !includeDeleted {
+		filter["deleted_at"] = bson.M{
 			"$exists": false,
-		},
+		}
+	}
+	count, err := repo.CountDocuments(ctx, s.permissionCollection, filter)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "permission", err)
 	}
-	count, _ := repo.CountDocuments(s.permissionCollection, filter)
 	limit := int64(10)
 	totalPages := utils.TotalPage(count, limit)
 	page := utils.CurrentPage(req.Msg.GetPage(), totalPages)
@@ -78,9 +98,9 @@ func (s *Biz) FindAllPermissions(req *connect.Request[permissionv1.FindAllPermis
 		SetSort(bson.M{"created_at": -1}).
 		SetLimit(limit).
 		SetSkip((page - 1) * limit)
-	data, err := repo.Find[permissionmodel.Permission](s.permissionCollection, filter, opt)
+	data, err := repo.Find[permissionmodel.Permission](ctx, s.permissionCollection, filter, opt)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "permission", err)
 	}
 
 	res := &permissionv1.FindAllPermissionsResponse{
@@ -93,14 +113,17 @@ func (s *Biz) FindAllPermissions(req *connect.Request[permissionv1.FindAllPermis
 }
 
 // FindPermissionByID is the permission.v1.PermissionBiz.FindPermissionByID method.
-func (s *Biz) FindPermissionByID(req *connect.Request[permissionv1.CommonUUIDRequest]) (
+func (s *Biz) FindPermissionByID(ctx context.Context, req *connect.Request[permissionv1.CommonUUIDRequest]) (
 	*connect.Response[permissionv1.Permission], error,
 ) {
+	ctx, span := tracing.Start(ctx, "permissionbiz.FindPermissionByID")
+	defer span.End()
+
 	id := req.Msg.GetId()
 	_, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		log.Err(err).Msg("Failed find permission by id")
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		log.Ctx(ctx).Err(err).Msg("Failed find permission by id")
+		return nil, errs.InvalidArgument("id", "must be a valid object id")
 	}
 
 	opt := options.
@@ -113,9 +136,9 @@ func (s *Biz) FindPermissionByID(req *connect.Request[permissionv1.CommonUUIDReq
 		},
 	}
 
-	data, err := repo.FindOne[permissionmodel.Permission](s.permissionCollection, filter, opt)
+	data, err := repo.FindOne[permissionmodel.Permission](ctx, s.permissionCollection, filter, opt)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "permission", err)
 	}
 
 	res := permissionmodel.PermissionToProto(data)
@@ -123,9 +146,12 @@ func (s *Biz) FindPermissionByID(req *connect.Request[permissionv1.CommonUUIDReq
 }
 
 // CreatePermission is the permission.v1.PermissionBiz.CreatePermission method.
-func (s *Biz) CreatePermission(req *connect.Request[permissionv1.CreatePermissionRequest]) (
+func (s *Biz) CreatePermission(ctx context.Context, req *connect.Request[permissionv1.CreatePermissionRequest]) (
 	*connect.Response[permissionv1.CommonResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "permissionbiz.CreatePermission")
+	defer span.End()
+
 	// count all permissions with filter
 	countFilter := bson.M{
 		"slug": req.Msg.GetSlug(),
@@ -133,9 +159,12 @@ func (s *Biz) CreatePermission(req *connect.Request[permissionv1.CreatePermissio
 			"$exists": false,
 		},
 	}
-	count, _ := repo.CountDocuments(s.permissionCollection, countFilter)
+	count, err := repo.CountDocuments(ctx, s.permissionCollection, countFilter)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "permission", err)
+	}
 	if count > 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("slug already exists"))
+		return nil, errs.AlreadyExists("permission", "slug")
 	}
 
 	data := &permissionmodel.Permission{
@@ -146,10 +175,10 @@ func (s *Biz) CreatePermission(req *connect.Request[permissionv1.CreatePermissio
 	}
 	data.PreCreate()
 
-	oid, err := repo.InsertOne(s.permissionCollection, data)
+	oid, err := repo.InsertOne(ctx, s.permissionCollection, data)
 	if err != nil {
-		log.Err(err).Msg("Error create permission")
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		log.Ctx(ctx).Err(err).Msg("Error create permission")
+		return nil, errs.FromRepo(ctx, "permission", err)
 	}
 
 	resID := oid.InsertedID.(string)
@@ -157,7 +186,7 @@ func (s *Biz) CreatePermission(req *connect.Request[permissionv1.CreatePermissio
 		Data: resID,
 	}
 
-	// if err = redis.Del(s.redis, utils.ListAuthPermissionsKey); err != nil {
+	// if err = redis.Del(ctx, s.redis, utils.ListAuthPermissionsKey); err != nil {
 	// 	return nil, err
 	// }
 
@@ -165,14 +194,17 @@ func (s *Biz) CreatePermission(req *connect.Request[permissionv1.CreatePermissio
 }
 
 // UpdatePermission is the permission.v1.PermissionBiz.UpdatePermission method.
-func (s *Biz) UpdatePermission(req *connect.Request[permissionv1.UpdatePermissionRequest]) (
+func (s *Biz) UpdatePermission(ctx context.Context, req *connect.Request[permissionv1.UpdatePermissionRequest]) (
 	*connect.Response[permissionv1.CommonResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "permissionbiz.UpdatePermission")
+	defer span.End()
+
 	id := req.Msg.GetId()
 	_, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		log.Err(err).Msg("Failed find permission by id")
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		log.Ctx(ctx).Err(err).Msg("Failed find permission by id")
+		return nil, errs.InvalidArgument("id", "must be a valid object id")
 	}
 
 	filter := bson.M{
@@ -181,9 +213,9 @@ func (s *Biz) UpdatePermission(req *connect.Request[permissionv1.UpdatePermissio
 			"$exists": false,
 		},
 	}
-	data, err := repo.FindOne[permissionmodel.Permission](s.permissionCollection, filter)
+	data, err := repo.FindOne[permissionmodel.Permission](ctx, s.permissionCollection, filter)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "permission", err)
 	}
 
 	// count all permissions with filter
@@ -194,9 +226,12 @@ func (s *Biz) UpdatePermission(req *connect.Request[permissionv1.UpdatePermissio
 			"$exists": false,
 		},
 	}
-	count, _ := repo.CountDocuments(s.permissionCollection, countFilter)
+	count, err := repo.CountDocuments(ctx, s.permissionCollection, countFilter)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "permission", err)
+	}
 	if count > 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("slug already exists"))
+		return nil, errs.AlreadyExists("permission", "slug")
 	}
 
 	data.Name = utils.StringCompareOrPassValue(data.Name, req.Msg.GetName())
@@ -213,15 +248,15 @@ func (s *Biz) UpdatePermission(req *connect.Request[permissionv1.UpdatePermissio
 	data.PreUpdate()
 
 	opt := bson.M{"$set": data}
-	if _, err = repo.UpdateOne(s.permissionCollection, filter, opt); err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	if _, err = repo.UpdateOne(ctx, s.permissionCollection, filter, opt); err != nil {
+		return nil, errs.FromRepo(ctx, "permission", err)
 	}
 
 	res := &permissionv1.CommonResponse{
 		Data: req.Msg.GetId(),
 	}
 
-	// if err = redis.Del(s.redis, utils.ListAuthPermissionsKey); err != nil {
+	// if err = redis.Del(ctx, s.redis, utils.ListAuthPermissionsKey); err != nil {
 	// 	return nil, err
 	// }
 
@@ -229,14 +264,17 @@ func (s *Biz) UpdatePermission(req *connect.Request[permissionv1.UpdatePermissio
 }
 
 // DeletePermission is the permission.v1.PermissionBiz.DeletePermission method.
-func (s *Biz) DeletePermission(req *connect.Request[permissionv1.CommonUUIDRequest]) (
+func (s *Biz) DeletePermission(ctx context.Context, req *connect.Request[permissionv1.CommonUUIDRequest]) (
 	*connect.Response[permissionv1.CommonResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "permissionbiz.DeletePermission")
+	defer span.End()
+
 	id := req.Msg.GetId()
 	_, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		log.Err(err).Msg("Failed find permission by id")
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		log.Ctx(ctx).Err(err).Msg("Failed find permission by id")
+		return nil, errs.InvalidArgument("id", "must be a valid object id")
 	}
 
 	filter := bson.M{
@@ -246,20 +284,23 @@ func (s *Biz) DeletePermission(req *connect.Request[permissionv1.CommonUUIDReque
 		},
 	}
 	// count all permissions with filter
-	count, _ := repo.CountDocuments(s.permissionCollection, filter)
+	count, err := repo.CountDocuments(ctx, s.permissionCollection, filter)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "permission", err)
+	}
 	if count <= 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("permission does not exists"))
+		return nil, errs.NotFound("permission")
 	}
 
-	if _, err = repo.SoftDeleteOne(s.permissionCollection, filter); err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	if _, err = repo.SoftDeleteOne(ctx, s.permissionCollection, filter); err != nil {
+		return nil, errs.FromRepo(ctx, "permission", err)
 	}
 
 	res := &permissionv1.CommonResponse{
 		Data: req.Msg.GetId(),
 	}
 
-	// if err = redis.Del(s.redis, utils.ListAuthPermissionsKey); err != nil {
+	// if err = redis.Del(ctx, s.redis, utils.ListAuthPermissionsKey); err != nil {
 	// 	return nil, err
 	// }
 
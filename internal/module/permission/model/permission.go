@@ -22,6 +22,11 @@ type Permission struct {
 	Slug        string `json:"slug,omitempty" bson:"slug,omitempty"`
 	RequireAuth bool   `json:"require_auth,omitempty" bson:"require_auth,omitempty"`
 	RequireHash bool   `json:"require_hash,omitempty" bson:"require_hash,omitempty"`
+
+	// RateLimit is the number of calls allowed per RateWindow seconds, keyed by RateKey.
+	RateLimit  int64  `json:"rate_limit,omitempty" bson:"rate_limit,omitempty"`
+	RateWindow int64  `json:"rate_window,omitempty" bson:"rate_window,omitempty"`
+	RateKey    string `json:"rate_key,omitempty" bson:"rate_key,omitempty"`
 }
 
 // CollectionName returns the name of the collection from struct name
@@ -40,36 +40,36 @@ func NewService(opt *Option) IPermissionService {
 }
 
 // FindAllPermissions find all permissions
-func (s *Service) FindAllPermissions(_ context.Context, req *connect.Request[permissionv1.FindAllPermissionsRequest]) (
+func (s *Service) FindAllPermissions(ctx context.Context, req *connect.Request[permissionv1.FindAllPermissionsRequest]) (
 	*connect.Response[permissionv1.FindAllPermissionsResponse], error,
 ) {
-	return s.permissionBiz.FindAllPermissions(req)
+	return s.permissionBiz.FindAllPermissions(ctx, req)
 }
 
 // FindPermissionByID find permission by id
-func (s *Service) FindPermissionByID(_ context.Context, req *connect.Request[permissionv1.CommonUUIDRequest]) (
+func (s *Service) FindPermissionByID(ctx context.Context, req *connect.Request[permissionv1.CommonUUIDRequest]) (
 	*connect.Response[permissionv1.Permission], error,
 ) {
-	return s.permissionBiz.FindPermissionByID(req)
+	return s.permissionBiz.FindPermissionByID(ctx, req)
 }
 
 // CreatePermission create permission
-func (s *Service) CreatePermission(_ context.Context, req *connect.Request[permissionv1.CreatePermissionRequest]) (
+func (s *Service) CreatePermission(ctx context.Context, req *connect.Request[permissionv1.CreatePermissionRequest]) (
 	*connect.Response[permissionv1.CommonResponse], error,
 ) {
-	return s.permissionBiz.CreatePermission(req)
+	return s.permissionBiz.CreatePermission(ctx, req)
 }
 
 // UpdatePermission update permission by id
-func (s *Service) UpdatePermission(_ context.Context, req *connect.Request[permissionv1.UpdatePermissionRequest]) (
+func (s *Service) UpdatePermission(ctx context.Context, req *connect.Request[permissionv1.UpdatePermissionRequest]) (
 	*connect.Response[permissionv1.CommonResponse], error,
 ) {
-	return s.permissionBiz.UpdatePermission(req)
+	return s.permissionBiz.UpdatePermission(ctx, req)
 }
 
 // DeletePermission delete permission by id
-func (s *Service) DeletePermission(_ context.Context, req *connect.Request[permissionv1.CommonUUIDRequest]) (
+func (s *Service) DeletePermission(ctx context.Context, req *connect.Request[permissionv1.CommonUUIDRequest]) (
 	*connect.Response[permissionv1.CommonResponse], error,
 ) {
-	return s.permissionBiz.DeletePermission(req)
+	return s.permissionBiz.DeletePermission(ctx, req)
 }
@@ -0,0 +1,375 @@
+package privacybiz
+
+import (
+	"context"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	auditbiz "github.com/xdorro/golang-grpc-base-project/internal/module/audit/biz"
+	auditmodel "github.com/xdorro/golang-grpc-base-project/internal/module/audit/model"
+	privacymodel "github.com/xdorro/golang-grpc-base-project/internal/module/privacy/model"
+	retentionbiz "github.com/xdorro/golang-grpc-base-project/internal/module/retention/biz"
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	auditv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1"
+	privacyv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// maxReasonLength is the maximum length of the reason and the note of an erasure.
+const maxReasonLength = 512
+
+// notStored are the kinds of data of a user the server does not store, listed in the archives so
+// their absence is explicit. The api keys are only read from the request headers.
+var notStored = []string{"api_keys"}
+
+var _ IPrivacyBiz = &Biz{}
+
+// IPrivacyBiz privacy service interface.
+type IPrivacyBiz interface {
+	// ExportData returns everything stored for the user.
+	ExportData(ctx context.Context, subject string) (*privacyv1.DataArchive, error)
+	// RequestErasure records a pending erasure of the user, awaiting the approval of an admin.
+	RequestErasure(ctx context.Context, subject, reason string) (*privacyv1.Erasure, error)
+	// FindAllErasures lists the erasures, the most recent first.
+	FindAllErasures(ctx context.Context, page int64, status string) (*privacyv1.FindAllErasuresResponse, error)
+	// ApproveErasure erases the user of the pending erasure and completes it.
+	ApproveErasure(ctx context.Context, id, reviewer string) (*privacyv1.Erasure, error)
+	// RejectErasure rejects the pending erasure with the note.
+	RejectErasure(ctx context.Context, id, reviewer, note string) (*privacyv1.Erasure, error)
+}
+
+// Biz struct.
+type Biz struct {
+	// option
+	casbin            casbin.ICasbin
+	session           session.ISession
+	auditBiz          auditbiz.IAuditBiz
+	retentionBiz      retentionbiz.IRetentionBiz
+	userCollection    *mongo.Collection
+	erasureCollection *mongo.Collection
+}
+
+// Option service option.
+type Option struct {
+	Repo         repo.IRepo
+	Casbin       casbin.ICasbin
+	Session      session.ISession
+	AuditBiz     auditbiz.IAuditBiz
+	RetentionBiz retentionbiz.IRetentionBiz
+}
+
+// NewBiz new service.
+func NewBiz(opt *Option) IPrivacyBiz {
+	s := &Biz{
+		casbin:            opt.Casbin,
+		session:           opt.Session,
+		auditBiz:          opt.AuditBiz,
+		retentionBiz:      opt.RetentionBiz,
+		userCollection:    opt.Repo.CollectionModel(&usermodel.User{}),
+		erasureCollection: opt.Repo.CollectionModel(&privacymodel.Erasure{}),
+	}
+
+	indexes := (&privacymodel.Erasure{}).GetIndexModels()
+	if _, err := repo.CreateIndexes(context.Background(), s.erasureCollection, indexes); err != nil {
+		log.Err(err).Msg("Error create erasure indexes")
+	}
+
+	return s
+}
+
+// ExportData compiles the user document, its roles, sessions, audit entries and erasures.
+func (s *Biz) ExportData(ctx context.Context, subject string) (*privacyv1.DataArchive, error) {
+	ctx, span := tracing.Start(ctx, "privacybiz.ExportData")
+	defer span.End()
+
+	opt := options.
+		FindOne().
+		SetProjection(bson.M{"password": 0, "invite_token": 0})
+
+	data, err := repo.FindOne[usermodel.User](ctx, s.userCollection, repo.ActiveIDFilter(subject), opt)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
+	}
+
+	sessions, err := s.session.List(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	audits, err := s.auditBiz.FindBySubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	erasures, err := repo.Find[privacymodel.Erasure](ctx, s.erasureCollection, bson.M{"subject": subject},
+		options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "erasure", err)
+	}
+
+	res := &privacyv1.DataArchive{
+		ExportedAt: time.Now().Unix(),
+		User: &privacyv1.User{
+			Id:           data.Id,
+			Name:         data.Name,
+			Email:        data.Email,
+			Role:         data.Role,
+			State:        usermodel.StatusName(data.State()),
+			StatusReason: data.StatusReason,
+			CreatedAt:    data.CreatedAt.Unix(),
+			UpdatedAt:    data.UpdatedAt.Unix(),
+		},
+		Roles:     s.roles(subject, data.Role),
+		Sessions:  make([]*privacyv1.Session, 0, len(sessions)),
+		Audits:    make([]*auditv1.Audit, 0, len(audits)),
+		Erasures:  privacymodel.ErasuresToProto(erasures),
+		NotStored: notStored,
+	}
+
+	for _, info := range sessions {
+		res.Sessions = append(res.Sessions, &privacyv1.Session{Id: info.ID, ExpiresAt: info.ExpiresAt.Unix()})
+	}
+
+	for _, audit := range audits {
+		// the client ip of another actor is not the data of the user
+		if audit.Actor != subject {
+			audit.ClientIP = ""
+		}
+
+		res.Audits = append(res.Audits, auditmodel.AuditToProto(audit))
+	}
+
+	return res, nil
+}
+
+// roles returns the role of the user and the roles assigned to it by the policies, with their permissions.
+func (s *Biz) roles(subject, role string) []*privacyv1.Role {
+	enforcer := s.casbin.Enforcer()
+
+	names := map[string]struct{}{}
+	if role != "" {
+		names[role] = struct{}{}
+	}
+	if assigned, err := enforcer.GetRolesForUser(subject); err == nil {
+		for _, name := range assigned {
+			names[name] = struct{}{}
+		}
+	}
+
+	res := make([]*privacyv1.Role, 0, len(names))
+	for name := range names {
+		permissions := make([]string, 0)
+		for _, policy := range enforcer.GetFilteredPolicy(0, name) {
+			permissions = append(permissions, policy[1])
+		}
+
+		res = append(res, &privacyv1.Role{Name: name, Permissions: permissions})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return res
+}
+
+// RequestErasure records the erasure of the user, a user has at most one pending erasure.
+func (s *Biz) RequestErasure(ctx context.Context, subject, reason string) (*privacyv1.Erasure, error) {
+	ctx, span := tracing.Start(ctx, "privacybiz.RequestErasure")
+	defer span.End()
+
+	if utf8.RuneCountInString(reason) > maxReasonLength {
+		return nil, errs.InvalidArgument("reason", "must be at most 512 characters")
+	}
+
+	count, err := repo.CountDocuments(ctx, s.erasureCollection, bson.M{
+		"subject": subject,
+		"status":  privacyv1.ErasurePending,
+	})
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "erasure", err)
+	}
+	if count > 0 {
+		return nil, errs.FailedPrecondition(errs.ReasonFailedPrecondition, "an erasure is already pending")
+	}
+
+	data := &privacymodel.Erasure{
+		Subject: subject,
+		Status:  privacyv1.ErasurePending,
+		Reason:  reason,
+	}
+	data.PreCreate()
+
+	if _, err = repo.InsertOne(ctx, s.erasureCollection, data); err != nil {
+		return nil, errs.FromRepo(ctx, "erasure", err)
+	}
+
+	return privacymodel.ErasureToProto(data), nil
+}
+
+// FindAllErasures lists the erasures with the status, all of them if empty.
+func (s *Biz) FindAllErasures(ctx context.Context, page int64, status string) (
+	*privacyv1.FindAllErasuresResponse, error,
+) {
+	ctx, span := tracing.Start(ctx, "privacybiz.FindAllErasures")
+	defer span.End()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	count, err := repo.CountDocuments(ctx, s.erasureCollection, filter)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "erasure", err)
+	}
+	limit := int64(10)
+	totalPages := utils.TotalPage(count, limit)
+	page = utils.CurrentPage(page, totalPages)
+
+	opt := options.
+		Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetLimit(limit).
+		SetSkip((page - 1) * limit)
+
+	data, err := repo.Find[privacymodel.Erasure](ctx, s.erasureCollection, filter, opt)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "erasure", err)
+	}
+
+	res := &privacyv1.FindAllErasuresResponse{
+		TotalPage:   totalPages,
+		CurrentPage: page,
+		Data:        privacymodel.ErasuresToProto(data),
+	}
+
+	return res, nil
+}
+
+// ApproveErasure anonymizes the user, revoking its sessions, and redacts its audit entries.
+// A failed erasure stays pending, so it can be approved again.
+func (s *Biz) ApproveErasure(ctx context.Context, id, reviewer string) (*privacyv1.Erasure, error) {
+	ctx, span := tracing.Start(ctx, "privacybiz.ApproveErasure")
+	defer span.End()
+
+	data, err := s.findPending(ctx, id, reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// a purged user has nothing left to anonymize
+	err = s.retentionBiz.AnonymizeUser(ctx, data.Subject)
+	if err != nil && connect.CodeOf(err) != connect.CodeNotFound {
+		return nil, err
+	}
+
+	redacted, err := s.auditBiz.Redact(ctx, data.Subject, reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	data.Status = privacyv1.ErasureCompleted
+	data.ReviewedBy = reviewer
+	data.ReviewedAt = now
+	data.CompletedAt = now
+	data.RedactedAudits = redacted
+
+	if err = s.review(ctx, data); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("erasure", data.Id).
+		Str("subject", data.Subject).
+		Int64("redacted_audits", redacted).
+		Msg("User erased")
+
+	return privacymodel.ErasureToProto(data), nil
+}
+
+// RejectErasure rejects the pending erasure, the user is kept.
+func (s *Biz) RejectErasure(ctx context.Context, id, reviewer, note string) (*privacyv1.Erasure, error) {
+	ctx, span := tracing.Start(ctx, "privacybiz.RejectErasure")
+	defer span.End()
+
+	if note == "" {
+		return nil, errs.InvalidArgument("note", "is required")
+	}
+	if utf8.RuneCountInString(note) > maxReasonLength {
+		return nil, errs.InvalidArgument("note", "must be at most 512 characters")
+	}
+
+	data, err := s.findPending(ctx, id, reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Status = privacyv1.ErasureRejected
+	data.ReviewedBy = reviewer
+	data.ReviewedAt = time.Now()
+	data.ReviewNote = note
+
+	if err = s.review(ctx, data); err != nil {
+		return nil, err
+	}
+
+	return privacymodel.ErasureToProto(data), nil
+}
+
+// findPending returns the pending erasure, its subject can not review it.
+func (s *Biz) findPending(ctx context.Context, id, reviewer string) (*privacymodel.Erasure, error) {
+	if !primitive.IsValidObjectID(id) {
+		return nil, errs.InvalidArgument("id", "must be a valid object id")
+	}
+
+	data, err := repo.FindOne[privacymodel.Erasure](ctx, s.erasureCollection, bson.M{"_id": id})
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "erasure", err)
+	}
+
+	if data.Status != privacyv1.ErasurePending {
+		return nil, errs.FailedPrecondition(errs.ReasonFailedPrecondition, "erasure is "+data.Status)
+	}
+
+	if reviewer == "" || reviewer == data.Subject {
+		return nil, errs.FailedPrecondition(errs.ReasonFailedPrecondition, "erasure must be reviewed by another user")
+	}
+
+	return data, nil
+}
+
+// review stores the review of the erasure, unless it was reviewed meanwhile.
+func (s *Biz) review(ctx context.Context, data *privacymodel.Erasure) error {
+	data.PreUpdate()
+
+	filter := bson.M{
+		"_id":    data.Id,
+		"status": privacyv1.ErasurePending,
+	}
+
+	res, err := repo.UpdateOne(ctx, s.erasureCollection, filter, bson.M{"$set": data})
+	if err != nil {
+		return errs.FromRepo(ctx, "erasure", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return errs.FailedPrecondition(errs.ReasonFailedPrecondition, "erasure was reviewed meanwhile")
+	}
+
+	return nil
+}
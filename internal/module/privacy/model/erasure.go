@@ -0,0 +1,88 @@
+package privacymodel
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	privacyv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+var _ IErasure = &Erasure{}
+
+// IErasure is the interface for an erasure
+type IErasure interface {
+	utils.IBaseModel
+}
+
+// Erasure is the record of the erasure request of a user, it holds no personal data but the
+// reason given by the user.
+type Erasure struct {
+	utils.BaseModel `bson:",inline"`
+
+	// Subject is the id of the user.
+	Subject    string `json:"subject,omitempty" bson:"subject,omitempty"`
+	Status     string `json:"status,omitempty" bson:"status,omitempty"`
+	Reason     string `json:"reason,omitempty" bson:"reason,omitempty"`
+	ReviewedBy string `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewNote string `json:"review_note,omitempty" bson:"review_note,omitempty"`
+
+	RedactedAudits int64     `json:"redacted_audits,omitempty" bson:"redacted_audits,omitempty"`
+	ReviewedAt     time.Time `json:"-" bson:"reviewed_at,omitempty"`
+	CompletedAt    time.Time `json:"-" bson:"completed_at,omitempty"`
+}
+
+// CollectionName returns the name of the collection from struct name
+func (m *Erasure) CollectionName() string {
+	return utils.CollectionName(m)
+}
+
+// GetIndexModels returns the index models
+func (m *Erasure) GetIndexModels() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "subject", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	}
+}
+
+// PreCreate is a callback that gets called before creating a models.
+func (m *Erasure) PreCreate() {
+	m.BaseModel.PreCreate()
+}
+
+// PreUpdate is a callback that gets called before updating a models.
+func (m *Erasure) PreUpdate() {
+	m.BaseModel.PreUpdate()
+}
+
+// ErasureToProto converts an erasure to a proto
+func ErasureToProto(m *Erasure) *privacyv1.Erasure {
+	return &privacyv1.Erasure{
+		Id:             m.Id,
+		Subject:        m.Subject,
+		Status:         m.Status,
+		Reason:         m.Reason,
+		ReviewedBy:     m.ReviewedBy,
+		ReviewNote:     m.ReviewNote,
+		RedactedAudits: m.RedactedAudits,
+		RequestedAt:    unix(m.CreatedAt),
+		ReviewedAt:     unix(m.ReviewedAt),
+		CompletedAt:    unix(m.CompletedAt),
+	}
+}
+
+// ErasuresToProto converts a slice of erasures to a slice of proto
+func ErasuresToProto(list []*Erasure) []*privacyv1.Erasure {
+	return utils.ToProto[Erasure, privacyv1.Erasure](list, ErasureToProto)
+}
+
+// unix returns the unix timestamp in seconds of the time, 0 if it is zero.
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
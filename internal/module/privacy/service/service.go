@@ -0,0 +1,137 @@
+package privacyservice
+
+import (
+	"context"
+
+	"github.com/bufbuild/connect-go"
+
+	privacybiz "github.com/xdorro/golang-grpc-base-project/internal/module/privacy/biz"
+	privacyv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1/privacyv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+)
+
+var _ IPrivacyService = &Service{}
+
+// IPrivacyService privacy service interface.
+type IPrivacyService interface {
+	privacyv1connect.PrivacyServiceHandler
+}
+
+// Service struct.
+type Service struct {
+	// option
+	privacyBiz privacybiz.IPrivacyBiz
+
+	privacyv1connect.UnimplementedPrivacyServiceHandler
+}
+
+// Option service option.
+type Option struct {
+	PrivacyBiz privacybiz.IPrivacyBiz
+}
+
+// NewService new service.
+func NewService(opt *Option) IPrivacyService {
+	s := &Service{
+		privacyBiz: opt.PrivacyBiz,
+	}
+
+	return s
+}
+
+// ExportMyData is the privacy.v1.PrivacyService.ExportMyData method.
+func (s *Service) ExportMyData(ctx context.Context, req *connect.Request[privacyv1.ExportMyDataRequest]) (
+	*connect.Response[privacyv1.DataArchive], error,
+) {
+	subject, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.privacyBiz.ExportData(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// RequestErasure is the privacy.v1.PrivacyService.RequestErasure method.
+func (s *Service) RequestErasure(ctx context.Context, req *connect.Request[privacyv1.RequestErasureRequest]) (
+	*connect.Response[privacyv1.Erasure], error,
+) {
+	subject, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.privacyBiz.RequestErasure(ctx, subject, req.Msg.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// FindAllErasures is the privacy.v1.PrivacyService.FindAllErasures method.
+func (s *Service) FindAllErasures(ctx context.Context, req *connect.Request[privacyv1.FindAllErasuresRequest]) (
+	*connect.Response[privacyv1.FindAllErasuresResponse], error,
+) {
+	if _, err := caller(ctx); err != nil {
+		return nil, err
+	}
+
+	res, err := s.privacyBiz.FindAllErasures(ctx, req.Msg.Page, req.Msg.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// ApproveErasure is the privacy.v1.PrivacyService.ApproveErasure method.
+func (s *Service) ApproveErasure(ctx context.Context, req *connect.Request[privacyv1.ReviewErasureRequest]) (
+	*connect.Response[privacyv1.Erasure], error,
+) {
+	reviewer, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.privacyBiz.ApproveErasure(ctx, req.Msg.GetId(), reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// RejectErasure is the privacy.v1.PrivacyService.RejectErasure method.
+func (s *Service) RejectErasure(ctx context.Context, req *connect.Request[privacyv1.ReviewErasureRequest]) (
+	*connect.Response[privacyv1.Erasure], error,
+) {
+	reviewer, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.privacyBiz.RejectErasure(ctx, req.Msg.GetId(), reviewer, req.Msg.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// caller returns the subject of the access token verified by the interceptor. The procedures act on
+// the caller or record it as the reviewer, so they fail if an operator makes them public.
+func caller(ctx context.Context) (string, error) {
+	claims := session.Claims(ctx)
+	if claims == nil || claims.Subject == "" {
+		return "", errs.Unauthenticated("authentication is required")
+	}
+
+	return claims.Subject, nil
+}
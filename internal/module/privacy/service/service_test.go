@@ -0,0 +1,28 @@
+package privacyservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+)
+
+func TestCaller(t *testing.T) {
+	ctx := session.WithClaims(context.Background(), &jwt.RegisteredClaims{
+		Subject:  "63a1f0c2e4b0a1b2c3d4e5f6",
+		Audience: jwt.ClaimStrings{"user"},
+	})
+
+	subject, err := caller(ctx)
+	if err != nil || subject != "63a1f0c2e4b0a1b2c3d4e5f6" {
+		t.Errorf("caller() = %q, %v, want the subject of the verified claims", subject, err)
+	}
+
+	// a procedure made public by an operator has no verified claims
+	if _, err = caller(context.Background()); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("caller() error = %v, want unauthenticated", err)
+	}
+}
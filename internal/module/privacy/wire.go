@@ -0,0 +1,14 @@
+package privacymodule
+
+import (
+	"github.com/google/wire"
+
+	privacybiz "github.com/xdorro/golang-grpc-base-project/internal/module/privacy/biz"
+	privacyservice "github.com/xdorro/golang-grpc-base-project/internal/module/privacy/service"
+)
+
+// ProviderModuleSet is Module providers.
+var ProviderModuleSet = wire.NewSet(
+	privacybiz.ProviderBizSet,
+	privacyservice.ProviderServiceSet,
+)
@@ -0,0 +1,308 @@
+package retentionbiz
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	permissionmodel "github.com/xdorro/golang-grpc-base-project/internal/module/permission/model"
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	retentionv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/lifecycle"
+	"github.com/xdorro/golang-grpc-base-project/pkg/metrics"
+	"github.com/xdorro/golang-grpc-base-project/pkg/redis"
+	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils/constants"
+)
+
+const (
+	// ModePurge removes the expired users.
+	ModePurge = "purge"
+	// ModeAnonymize keeps the expired users without their personal data.
+	ModeAnonymize = "anonymize"
+)
+
+var _ IRetentionBiz = &Biz{}
+
+// IRetentionBiz retention service interface.
+type IRetentionBiz interface {
+	// RestoreUser restores a soft deleted user, unless it is anonymized.
+	RestoreUser(ctx context.Context, id string) error
+	// RestorePermission restores a soft deleted permission.
+	RestorePermission(ctx context.Context, id string) error
+	// PurgeUser removes a soft deleted user with its sessions.
+	PurgeUser(ctx context.Context, id string) error
+	// AnonymizeUser removes the personal data and the sessions of a user, deleted or not.
+	AnonymizeUser(ctx context.Context, id string) error
+	// PurgePermission removes a soft deleted permission with its policies.
+	PurgePermission(ctx context.Context, id string) error
+	// Run purges or anonymizes the records deleted for longer than their retention age.
+	Run(ctx context.Context, dryRun bool) (*retentionv1.RunRetentionResponse, error)
+}
+
+// Biz struct.
+type Biz struct {
+	retention *config.Retention
+
+	// option
+	casbin               casbin.ICasbin
+	redis                redis.IRedis
+	session              session.ISession
+	userCollection       *mongo.Collection
+	permissionCollection *mongo.Collection
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Option service option.
+type Option struct {
+	Config    *config.Config
+	Lifecycle lifecycle.ILifecycle
+	Repo      repo.IRepo
+	Redis     redis.IRedis
+	Casbin    casbin.ICasbin
+	Session   session.ISession
+}
+
+// NewBiz new service.
+func NewBiz(opt *Option) IRetentionBiz {
+	s := &Biz{
+		retention:            &opt.Config.Retention,
+		casbin:               opt.Casbin,
+		redis:                opt.Redis,
+		session:              opt.Session,
+		userCollection:       opt.Repo.CollectionModel(&usermodel.User{}),
+		permissionCollection: opt.Repo.CollectionModel(&permissionmodel.Permission{}),
+	}
+
+	if s.retention.Enabled {
+		// the job is stopped with the other background jobs
+		opt.Lifecycle.Append(lifecycle.Hook{
+			Name:    "retention",
+			Phase:   lifecycle.PhaseJobs,
+			OnStart: s.start,
+			OnStop:  s.stop,
+		})
+	}
+
+	return s
+}
+
+// RestoreUser restores the soft deleted user, its email must not be used by another user.
+func (s *Biz) RestoreUser(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "retentionbiz.RestoreUser")
+	defer span.End()
+
+	data, err := findDeleted[usermodel.User](ctx, s.userCollection, "user", id)
+	if err != nil {
+		return err
+	}
+
+	if !data.AnonymizedAt.IsZero() {
+		return errs.FailedPrecondition(errs.ReasonFailedPrecondition, "user is anonymized")
+	}
+
+	count, err := repo.CountDocuments(ctx, s.userCollection, bson.M{
+		"_id":   bson.M{"$nin": repo.IDs(id)},
+		"email": data.Email,
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	})
+	if err != nil {
+		return errs.FromRepo(ctx, "user", err)
+	}
+	if count > 0 {
+		return errs.AlreadyExists("user", "email")
+	}
+
+	return restore(ctx, s.userCollection, "user", id)
+}
+
+// RestorePermission restores the soft deleted permission, its slug must not be used by another permission.
+func (s *Biz) RestorePermission(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "retentionbiz.RestorePermission")
+	defer span.End()
+
+	data, err := findDeleted[permissionmodel.Permission](ctx, s.permissionCollection, "permission", id)
+	if err != nil {
+		return err
+	}
+
+	count, err := repo.CountDocuments(ctx, s.permissionCollection, bson.M{
+		"_id":  bson.M{"$nin": repo.IDs(id)},
+		"slug": data.Slug,
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	})
+	if err != nil {
+		return errs.FromRepo(ctx, "permission", err)
+	}
+	if count > 0 {
+		return errs.AlreadyExists("permission", "slug")
+	}
+
+	if err = restore(ctx, s.permissionCollection, "permission", id); err != nil {
+		return err
+	}
+
+	// the interceptor reloads the permissions
+	_ = redis.Del(ctx, s.redis, constants.ListAuthPermissionsKey)
+
+	return nil
+}
+
+// PurgeUser removes the soft deleted user once its sessions are revoked.
+func (s *Biz) PurgeUser(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "retentionbiz.PurgeUser")
+	defer span.End()
+
+	data, err := findDeleted[usermodel.User](ctx, s.userCollection, "user", id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.cleanupUser(ctx, data.Id); err != nil {
+		return err
+	}
+
+	if _, err = repo.DeleteOne(ctx, s.userCollection, repo.IDFilter(id)); err != nil {
+		return errs.FromRepo(ctx, "user", err)
+	}
+	metrics.RetentionRecordsTotal.WithLabelValues("user", ModePurge).Inc()
+
+	return nil
+}
+
+// AnonymizeUser deletes the user and removes its personal data once its sessions are revoked.
+// An anonymized user is left as is.
+func (s *Biz) AnonymizeUser(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "retentionbiz.AnonymizeUser")
+	defer span.End()
+
+	if !primitive.IsValidObjectID(id) {
+		return errs.InvalidArgument("id", "must be a valid object id")
+	}
+
+	filter := repo.IDFilter(id)
+	opt := options.
+		FindOne().
+		SetProjection(bson.M{"anonymized_at": 1})
+
+	data, err := repo.FindOne[usermodel.User](ctx, s.userCollection, filter, opt)
+	if err != nil {
+		return errs.FromRepo(ctx, "user", err)
+	}
+
+	if !data.AnonymizedAt.IsZero() {
+		return nil
+	}
+
+	if err = s.cleanupUser(ctx, id); err != nil {
+		return err
+	}
+
+	if _, err = repo.UpdateOne(ctx, s.userCollection, filter, usermodel.AnonymizeUpdate(time.Now())); err != nil {
+		return errs.FromRepo(ctx, "user", err)
+	}
+	metrics.RetentionRecordsTotal.WithLabelValues("user", ModeAnonymize).Inc()
+
+	return nil
+}
+
+// PurgePermission removes the soft deleted permission once its policies are removed.
+func (s *Biz) PurgePermission(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "retentionbiz.PurgePermission")
+	defer span.End()
+
+	data, err := findDeleted[permissionmodel.Permission](ctx, s.permissionCollection, "permission", id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.cleanupPermission(ctx, data.Slug); err != nil {
+		return err
+	}
+
+	if _, err = repo.DeleteOne(ctx, s.permissionCollection, repo.IDFilter(id)); err != nil {
+		return errs.FromRepo(ctx, "permission", err)
+	}
+	metrics.RetentionRecordsTotal.WithLabelValues("permission", ModePurge).Inc()
+
+	return nil
+}
+
+// cleanupUser revokes the sessions of the user. The policies have nothing to remove, their subjects
+// are the roles and the users are never assigned to a role by a policy.
+func (s *Biz) cleanupUser(ctx context.Context, id string) error {
+	return s.session.RevokeAll(ctx, id)
+}
+
+// cleanupPermission removes the policies granting the permission to the roles.
+func (s *Biz) cleanupPermission(ctx context.Context, slug string) error {
+	if slug == "" {
+		return nil
+	}
+
+	if _, err := s.casbin.Enforcer().RemoveFilteredPolicy(1, slug); err != nil {
+		return errs.FromRepo(ctx, "policy", err)
+	}
+
+	_ = redis.Del(ctx, s.redis, constants.ListAuthPermissionsKey)
+
+	return nil
+}
+
+// findDeleted returns the soft deleted record with the id.
+func findDeleted[T any](ctx context.Context, collection *mongo.Collection, resource, id string) (*T, error) {
+	if !primitive.IsValidObjectID(id) {
+		return nil, errs.InvalidArgument("id", "must be a valid object id")
+	}
+
+	filter := repo.IDFilter(id)
+	filter["deleted_at"] = bson.M{
+		"$exists": true,
+	}
+	opt := options.
+		FindOne().
+		SetProjection(bson.M{"password": 0, "invite_token": 0})
+
+	data, err := repo.FindOne[T](ctx, collection, filter, opt)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, resource, err)
+	}
+
+	return data, nil
+}
+
+// restore removes the deletion date of the record.
+func restore(ctx context.Context, collection *mongo.Collection, resource, id string) error {
+	filter := repo.IDFilter(id)
+	obj := bson.M{
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
+		"$unset": bson.M{
+			"deleted_at": "",
+		},
+	}
+
+	if _, err := repo.UpdateOne(ctx, collection, filter, obj); err != nil {
+		return errs.FromRepo(ctx, resource, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package retentionbiz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	retentionv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/metrics"
+	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
+)
+
+// Run purges or anonymizes the users and purges the permissions deleted for longer than their
+// retention age. The records are processed in batches, so an interrupted run is resumed by the next.
+func (s *Biz) Run(ctx context.Context, dryRun bool) (*retentionv1.RunRetentionResponse, error) {
+	ctx, span := tracing.Start(ctx, "retentionbiz.Run")
+	defer span.End()
+
+	now := time.Now()
+	res := &retentionv1.RunRetentionResponse{
+		Mode:   s.retention.Mode,
+		DryRun: dryRun,
+	}
+
+	// the anonymized users are kept for good
+	userFilter := bson.M{
+		"deleted_at": bson.M{
+			"$lt": now.Add(-s.retention.UserAge),
+		},
+		"anonymized_at": bson.M{
+			"$exists": false,
+		},
+	}
+	users, err := s.expire(ctx, s.userCollection, userFilter, dryRun, s.expireUsers)
+	if s.retention.Mode == ModePurge {
+		res.PurgedUsers = users
+	} else {
+		res.AnonymizedUsers = users
+	}
+	if err != nil {
+		return res, errs.FromRepo(ctx, "user", err)
+	}
+
+	permissionFilter := bson.M{
+		"deleted_at": bson.M{
+			"$lt": now.Add(-s.retention.PermissionAge),
+		},
+	}
+	res.PurgedPermissions, err = s.expire(ctx, s.permissionCollection, permissionFilter, dryRun, s.purgePermissions)
+	if err != nil {
+		return res, errs.FromRepo(ctx, "permission", err)
+	}
+
+	return res, nil
+}
+
+// expire calls fn with the records of the filter batch by batch, it returns the number of records.
+// The records must no longer match the filter once fn returns.
+func (s *Biz) expire(ctx context.Context, collection *mongo.Collection, filter bson.M, dryRun bool,
+	fn func(ctx context.Context, data []bson.M) error,
+) (int64, error) {
+	if dryRun {
+		return repo.CountDocuments(ctx, collection, filter)
+	}
+
+	opt := options.
+		Find().
+		SetProjection(bson.M{"_id": 1, "slug": 1}).
+		SetLimit(int64(s.retention.BatchSize))
+
+	find := func(ctx context.Context) ([]bson.M, error) {
+		data, err := repo.Find[bson.M](ctx, collection, filter, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		batch := make([]bson.M, 0, len(data))
+		for _, doc := range data {
+			batch = append(batch, *doc)
+		}
+
+		return batch, nil
+	}
+
+	return expireBatches(ctx, s.retention.BatchSize, find, fn)
+}
+
+// expireBatches calls fn with the batches returned by find until a batch is smaller than batchSize,
+// it returns the number of records passed to fn.
+func expireBatches(ctx context.Context, batchSize int, find func(ctx context.Context) ([]bson.M, error),
+	fn func(ctx context.Context, data []bson.M) error,
+) (int64, error) {
+	total := int64(0)
+	for {
+		batch, err := find(ctx)
+		if err != nil || len(batch) == 0 {
+			return total, err
+		}
+
+		if err = fn(ctx, batch); err != nil {
+			return total, err
+		}
+		total += int64(len(batch))
+
+		if len(batch) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// expireUsers revokes the sessions of the users, then purges or anonymizes them.
+func (s *Biz) expireUsers(ctx context.Context, data []bson.M) error {
+	keys := make([]any, 0, len(data))
+	for _, doc := range data {
+		if err := s.cleanupUser(ctx, hexID(doc["_id"])); err != nil {
+			return err
+		}
+
+		keys = append(keys, doc["_id"])
+	}
+
+	filter := bson.M{
+		"_id": bson.M{
+			"$in": keys,
+		},
+	}
+
+	if s.retention.Mode == ModePurge {
+		if _, err := repo.DeleteMany(ctx, s.userCollection, filter); err != nil {
+			return err
+		}
+
+		metrics.RetentionRecordsTotal.WithLabelValues("user", ModePurge).Add(float64(len(keys)))
+		return nil
+	}
+
+	if _, err := repo.UpdateMany(ctx, s.userCollection, filter, usermodel.AnonymizeUpdate(time.Now())); err != nil {
+		return err
+	}
+
+	metrics.RetentionRecordsTotal.WithLabelValues("user", ModeAnonymize).Add(float64(len(keys)))
+	return nil
+}
+
+// purgePermissions removes the policies of the permissions, then purges them.
+func (s *Biz) purgePermissions(ctx context.Context, data []bson.M) error {
+	keys := make([]any, 0, len(data))
+	for _, doc := range data {
+		slug, _ := doc["slug"].(string)
+		if err := s.cleanupPermission(ctx, slug); err != nil {
+			return err
+		}
+
+		keys = append(keys, doc["_id"])
+	}
+
+	filter := bson.M{
+		"_id": bson.M{
+			"$in": keys,
+		},
+	}
+	if _, err := repo.DeleteMany(ctx, s.permissionCollection, filter); err != nil {
+		return err
+	}
+
+	metrics.RetentionRecordsTotal.WithLabelValues("permission", ModePurge).Add(float64(len(keys)))
+	return nil
+}
+
+// start runs the retention every interval until stopped.
+func (s *Biz) start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	s.mu.Lock()
+	s.cancel, s.done = cancel, done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(s.retention.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.runJob(ctx)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// stop cancels the running retention and waits for it.
+func (s *Biz) stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runJob runs the retention and logs its result, the replicas may run it concurrently.
+func (s *Biz) runJob(ctx context.Context) {
+	start := time.Now()
+	res, err := s.Run(ctx, false)
+
+	logger := log.Info()
+	if err != nil {
+		logger = log.Error().Err(err)
+	}
+
+	logger.
+		Str("mode", res.Mode).
+		Int64("purged_users", res.PurgedUsers).
+		Int64("anonymized_users", res.AnonymizedUsers).
+		Int64("purged_permissions", res.PurgedPermissions).
+		Dur("took", time.Since(start)).
+		Msg("Retention done")
+}
+
+// hexID returns the hex of an id stored as an object id, or the id stored as a string.
+func hexID(id any) string {
+	switch v := id.(type) {
+	case primitive.ObjectID:
+		return v.Hex()
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
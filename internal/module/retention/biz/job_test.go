@@ -0,0 +1,105 @@
+package retentionbiz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// batches returns a find func returning the batches of the sizes in order, then empty batches.
+func batches(sizes ...int) (func(ctx context.Context) ([]bson.M, error), *int) {
+	calls := 0
+	return func(context.Context) ([]bson.M, error) {
+		calls++
+		if len(sizes) == 0 {
+			return nil, nil
+		}
+
+		batch := make([]bson.M, sizes[0])
+		sizes = sizes[1:]
+		return batch, nil
+	}, &calls
+}
+
+func TestExpireBatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		total int64
+		calls int
+	}{
+		{name: "no records", total: 0, calls: 1},
+		{name: "partial batch", sizes: []int{2}, total: 2, calls: 1},
+		{name: "full batches", sizes: []int{3, 3, 1}, total: 7, calls: 3},
+		{name: "exact batches", sizes: []int{3, 3}, total: 6, calls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			find, calls := batches(tt.sizes...)
+			expired := int64(0)
+			total, err := expireBatches(context.Background(), 3, find, func(_ context.Context, data []bson.M) error {
+				expired += int64(len(data))
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("expireBatches() error = %v", err)
+			}
+
+			if total != tt.total || expired != tt.total {
+				t.Errorf("expireBatches() = %d, expired %d, want %d", total, expired, tt.total)
+			}
+
+			if *calls != tt.calls {
+				t.Errorf("expireBatches() found %d batches, want %d", *calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestExpireBatchesError(t *testing.T) {
+	errExpire := errors.New("expire failed")
+
+	find, _ := batches(3, 3, 3)
+	batch := 0
+	total, err := expireBatches(context.Background(), 3, find, func(context.Context, []bson.M) error {
+		batch++
+		if batch == 2 {
+			return errExpire
+		}
+
+		return nil
+	})
+
+	if !errors.Is(err, errExpire) {
+		t.Errorf("expireBatches() error = %v, want %v", err, errExpire)
+	}
+
+	// the interrupted run counts the batches expired before the error only
+	if total != 3 {
+		t.Errorf("expireBatches() = %d, want 3", total)
+	}
+
+	errFind := errors.New("find failed")
+	total, err = expireBatches(context.Background(), 3, func(context.Context) ([]bson.M, error) {
+		return nil, errFind
+	}, nil)
+	if !errors.Is(err, errFind) || total != 0 {
+		t.Errorf("expireBatches() = %d, %v, want 0, %v", total, err, errFind)
+	}
+}
+
+func TestHexID(t *testing.T) {
+	oid := primitive.NewObjectID()
+
+	if got := hexID(oid); got != oid.Hex() {
+		t.Errorf("hexID() = %q, want %q", got, oid.Hex())
+	}
+
+	if got := hexID("admin"); got != "admin" {
+		t.Errorf("hexID() = %q, want admin", got)
+	}
+}
@@ -0,0 +1,89 @@
+package retentionservice
+
+import (
+	"context"
+
+	"github.com/bufbuild/connect-go"
+
+	retentionbiz "github.com/xdorro/golang-grpc-base-project/internal/module/retention/biz"
+	retentionv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1/retentionv1connect"
+)
+
+var _ IRetentionService = &Service{}
+
+// IRetentionService retention service interface.
+type IRetentionService interface {
+	retentionv1connect.RetentionServiceHandler
+}
+
+// Service struct.
+type Service struct {
+	// option
+	retentionBiz retentionbiz.IRetentionBiz
+
+	retentionv1connect.UnimplementedRetentionServiceHandler
+}
+
+// Option service option.
+type Option struct {
+	RetentionBiz retentionbiz.IRetentionBiz
+}
+
+// NewService new service.
+func NewService(opt *Option) IRetentionService {
+	s := &Service{
+		retentionBiz: opt.RetentionBiz,
+	}
+
+	return s
+}
+
+// RestoreUser is the retention.v1.RetentionService.RestoreUser method.
+func (s *Service) RestoreUser(ctx context.Context, req *connect.Request[retentionv1.RecordRequest]) (
+	*connect.Response[retentionv1.RecordResponse], error,
+) {
+	return record(req.Msg, s.retentionBiz.RestoreUser(ctx, req.Msg.GetId()))
+}
+
+// RestorePermission is the retention.v1.RetentionService.RestorePermission method.
+func (s *Service) RestorePermission(ctx context.Context, req *connect.Request[retentionv1.RecordRequest]) (
+	*connect.Response[retentionv1.RecordResponse], error,
+) {
+	return record(req.Msg, s.retentionBiz.RestorePermission(ctx, req.Msg.GetId()))
+}
+
+// PurgeUser is the retention.v1.RetentionService.PurgeUser method.
+func (s *Service) PurgeUser(ctx context.Context, req *connect.Request[retentionv1.RecordRequest]) (
+	*connect.Response[retentionv1.RecordResponse], error,
+) {
+	return record(req.Msg, s.retentionBiz.PurgeUser(ctx, req.Msg.GetId()))
+}
+
+// PurgePermission is the retention.v1.RetentionService.PurgePermission method.
+func (s *Service) PurgePermission(ctx context.Context, req *connect.Request[retentionv1.RecordRequest]) (
+	*connect.Response[retentionv1.RecordResponse], error,
+) {
+	return record(req.Msg, s.retentionBiz.PurgePermission(ctx, req.Msg.GetId()))
+}
+
+// RunRetention is the retention.v1.RetentionService.RunRetention method.
+func (s *Service) RunRetention(ctx context.Context, req *connect.Request[retentionv1.RunRetentionRequest]) (
+	*connect.Response[retentionv1.RunRetentionResponse], error,
+) {
+	res, err := s.retentionBiz.Run(ctx, req.Msg.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// record returns the response of the restore and purge methods.
+func record(msg *retentionv1.RecordRequest, err error) (*connect.Response[retentionv1.RecordResponse], error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&retentionv1.RecordResponse{Id: msg.GetId()}), nil
+}
@@ -0,0 +1,14 @@
+package retentionmodule
+
+import (
+	"github.com/google/wire"
+
+	retentionbiz "github.com/xdorro/golang-grpc-base-project/internal/module/retention/biz"
+	retentionservice "github.com/xdorro/golang-grpc-base-project/internal/module/retention/service"
+)
+
+// ProviderModuleSet is Module providers.
+var ProviderModuleSet = wire.NewSet(
+	retentionbiz.ProviderBizSet,
+	retentionservice.ProviderServiceSet,
+)
@@ -1,32 +1,40 @@
 package rolebiz
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/bufbuild/connect-go"
 	rolev1 "github.com/xdorro/proto-base-project/proto-gen-go/role/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
+	permissionmodel "github.com/xdorro/golang-grpc-base-project/internal/module/permission/model"
 	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
 )
 
 var _ IRoleBiz = &Biz{}
 
 // IRoleBiz role service interface.
 type IRoleBiz interface {
-	FindAllRoles(req *connect.Request[rolev1.FindAllRolesRequest]) (
+	FindAllRoles(ctx context.Context, req *connect.Request[rolev1.FindAllRolesRequest]) (
 		*connect.Response[rolev1.FindAllRolesResponse], error,
 	)
-	FindRoleByName(req *connect.Request[rolev1.CommonNameRequest]) (
+	FindRoleByName(ctx context.Context, req *connect.Request[rolev1.CommonNameRequest]) (
 		*connect.Response[rolev1.Role], error,
 	)
-	CreateRole(req *connect.Request[rolev1.CreateRoleRequest]) (
+	CreateRole(ctx context.Context, req *connect.Request[rolev1.CreateRoleRequest]) (
 		*connect.Response[rolev1.CommonResponse], error,
 	)
-	UpdateRole(req *connect.Request[rolev1.UpdateRoleRequest]) (
+	UpdateRole(ctx context.Context, req *connect.Request[rolev1.UpdateRoleRequest]) (
 		*connect.Response[rolev1.CommonResponse], error,
 	)
-	DeleteRole(req *connect.Request[rolev1.CommonNameRequest]) (
+	DeleteRole(ctx context.Context, req *connect.Request[rolev1.CommonNameRequest]) (
 		*connect.Response[rolev1.CommonResponse], error,
 	)
 }
@@ -34,27 +42,33 @@ type IRoleBiz interface {
 // Biz struct.
 type Biz struct {
 	// option
-	casbin casbin.ICasbin
+	casbin               casbin.ICasbin
+	permissionCollection *mongo.Collection
 }
 
 // Option service option.
 type Option struct {
 	Casbin casbin.ICasbin
+	Repo   repo.IRepo
 }
 
 // NewBiz new service.
 func NewBiz(opt *Option) IRoleBiz {
 	b := &Biz{
-		casbin: opt.Casbin,
+		casbin:               opt.Casbin,
+		permissionCollection: opt.Repo.CollectionModel(&permissionmodel.Permission{}),
 	}
 
 	return b
 }
 
 // FindAllRoles find all roles
-func (b *Biz) FindAllRoles(*connect.Request[rolev1.FindAllRolesRequest]) (
+func (b *Biz) FindAllRoles(ctx context.Context, _ *connect.Request[rolev1.FindAllRolesRequest]) (
 	*connect.Response[rolev1.FindAllRolesResponse], error,
 ) {
+	_, span := tracing.Start(ctx, "rolebiz.FindAllRoles")
+	defer span.End()
+
 	data := make([]*rolev1.Role, 0)
 
 	roles := b.casbin.Enforcer().GetAllSubjects()
@@ -72,14 +86,17 @@ func (b *Biz) FindAllRoles(*connect.Request[rolev1.FindAllRolesRequest]) (
 }
 
 // FindRoleByName find role by name
-func (b *Biz) FindRoleByName(req *connect.Request[rolev1.CommonNameRequest]) (
+func (b *Biz) FindRoleByName(ctx context.Context, req *connect.Request[rolev1.CommonNameRequest]) (
 	*connect.Response[rolev1.Role], error,
 ) {
+	_, span := tracing.Start(ctx, "rolebiz.FindRoleByName")
+	defer span.End()
+
 	name := strings.ToLower(req.Msg.GetName())
 
 	policies := b.casbin.Enforcer().GetFilteredPolicy(0, name)
 	if len(policies) == 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("role does not exists"))
+		return nil, errs.NotFound("role")
 	}
 
 	permissions := make([]string, 0)
@@ -96,14 +113,21 @@ func (b *Biz) FindRoleByName(req *connect.Request[rolev1.CommonNameRequest]) (
 }
 
 // CreateRole create role
-func (b *Biz) CreateRole(req *connect.Request[rolev1.CreateRoleRequest]) (
+func (b *Biz) CreateRole(ctx context.Context, req *connect.Request[rolev1.CreateRoleRequest]) (
 	*connect.Response[rolev1.CommonResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "rolebiz.CreateRole")
+	defer span.End()
+
 	name := strings.ToLower(req.Msg.GetName())
 
 	policies := b.casbin.Enforcer().GetFilteredPolicy(0, name)
 	if len(policies) > 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("role already exists"))
+		return nil, errs.AlreadyExists("role", "name")
+	}
+
+	if err := b.validatePermissions(ctx, req.Msg.GetPermissions()); err != nil {
+		return nil, err
 	}
 
 	for _, per := range req.Msg.GetPermissions() {
@@ -113,7 +137,7 @@ func (b *Biz) CreateRole(req *connect.Request[rolev1.CreateRoleRequest]) (
 	// add policies to casbin
 	_, err := b.casbin.Enforcer().AddPolicies(policies)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "role", err)
 	}
 
 	res := &rolev1.CommonResponse{
@@ -124,19 +148,26 @@ func (b *Biz) CreateRole(req *connect.Request[rolev1.CreateRoleRequest]) (
 }
 
 // UpdateRole update role
-func (b *Biz) UpdateRole(req *connect.Request[rolev1.UpdateRoleRequest]) (
+func (b *Biz) UpdateRole(ctx context.Context, req *connect.Request[rolev1.UpdateRoleRequest]) (
 	*connect.Response[rolev1.CommonResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "rolebiz.UpdateRole")
+	defer span.End()
+
 	name := strings.ToLower(req.Msg.GetName())
 
 	oldPolicies := b.casbin.Enforcer().GetFilteredPolicy(0, name)
 	if len(oldPolicies) == 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("role does not exists"))
+		return nil, errs.NotFound("role")
+	}
+
+	if err := b.validatePermissions(ctx, req.Msg.GetPermissions()); err != nil {
+		return nil, err
 	}
 
 	_, err := b.casbin.Enforcer().RemovePolicies(oldPolicies)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "role", err)
 	}
 
 	policies := make([][]string, 0)
@@ -147,7 +178,7 @@ func (b *Biz) UpdateRole(req *connect.Request[rolev1.UpdateRoleRequest]) (
 	// update policies to casbin
 	_, err = b.casbin.Enforcer().AddPolicies(policies)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "role", err)
 	}
 
 	res := &rolev1.CommonResponse{
@@ -158,19 +189,22 @@ func (b *Biz) UpdateRole(req *connect.Request[rolev1.UpdateRoleRequest]) (
 }
 
 // DeleteRole delete role
-func (b *Biz) DeleteRole(req *connect.Request[rolev1.CommonNameRequest]) (
+func (b *Biz) DeleteRole(ctx context.Context, req *connect.Request[rolev1.CommonNameRequest]) (
 	*connect.Response[rolev1.CommonResponse], error,
 ) {
+	_, span := tracing.Start(ctx, "rolebiz.DeleteRole")
+	defer span.End()
+
 	name := strings.ToLower(req.Msg.GetName())
 
 	policies := b.casbin.Enforcer().GetFilteredPolicy(0, name)
 	if len(policies) == 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("role does not exists"))
+		return nil, errs.NotFound("role")
 	}
 
 	_, err := b.casbin.Enforcer().RemovePolicies(policies)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "role", err)
 	}
 
 	res := &rolev1.CommonResponse{
@@ -179,3 +213,48 @@ func (b *Biz) DeleteRole(req *connect.Request[rolev1.CommonNameRequest]) (
 
 	return connect.NewResponse(res), nil
 }
+
+// validatePermissions checks that every permission slug is an existing permission.
+func (b *Biz) validatePermissions(ctx context.Context, slugs []string) error {
+	if len(slugs) == 0 {
+		return nil
+	}
+
+	filter := bson.M{
+		"slug": bson.M{
+			"$in": slugs,
+		},
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}
+	opt := options.
+		Find().
+		SetProjection(bson.M{"slug": 1})
+
+	data, err := repo.Find[permissionmodel.Permission](ctx, b.permissionCollection, filter, opt)
+	if err != nil {
+		return errs.FromRepo(ctx, "permission", err)
+	}
+
+	exists := make(map[string]struct{}, len(data))
+	for _, per := range data {
+		exists[per.Slug] = struct{}{}
+	}
+
+	violations := make([]*errs.FieldViolation, 0)
+	for i, slug := range slugs {
+		if _, ok := exists[slug]; !ok {
+			violations = append(violations, &errs.FieldViolation{
+				Field:       fmt.Sprintf("permissions[%d]", i),
+				Description: "permission does not exists",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return errs.Validation(violations...)
+	}
+
+	return nil
+}
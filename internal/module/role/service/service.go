@@ -40,36 +40,36 @@ func NewService(opt *Option) IRoleService {
 }
 
 // FindAllRoles is the role.v1.RoleService.FindAllRoles method.
-func (s *Service) FindAllRoles(_ context.Context, req *connect.Request[rolev1.FindAllRolesRequest]) (
+func (s *Service) FindAllRoles(ctx context.Context, req *connect.Request[rolev1.FindAllRolesRequest]) (
 	*connect.Response[rolev1.FindAllRolesResponse], error,
 ) {
-	return s.roleBiz.FindAllRoles(req)
+	return s.roleBiz.FindAllRoles(ctx, req)
 }
 
 // FindRoleByName is the role.v1.RoleService.FindRoleByName method.
-func (s *Service) FindRoleByName(_ context.Context, req *connect.Request[rolev1.CommonNameRequest]) (
+func (s *Service) FindRoleByName(ctx context.Context, req *connect.Request[rolev1.CommonNameRequest]) (
 	*connect.Response[rolev1.Role], error,
 ) {
-	return s.roleBiz.FindRoleByName(req)
+	return s.roleBiz.FindRoleByName(ctx, req)
 }
 
 // CreateRole is the role.v1.RoleService.CreateRole method.
-func (s *Service) CreateRole(_ context.Context, req *connect.Request[rolev1.CreateRoleRequest]) (
+func (s *Service) CreateRole(ctx context.Context, req *connect.Request[rolev1.CreateRoleRequest]) (
 	*connect.Response[rolev1.CommonResponse], error,
 ) {
-	return s.roleBiz.CreateRole(req)
+	return s.roleBiz.CreateRole(ctx, req)
 }
 
 // UpdateRole is the role.v1.RoleService.UpdateRole method.
-func (s *Service) UpdateRole(_ context.Context, req *connect.Request[rolev1.UpdateRoleRequest]) (
+func (s *Service) UpdateRole(ctx context.Context, req *connect.Request[rolev1.UpdateRoleRequest]) (
 	*connect.Response[rolev1.CommonResponse], error,
 ) {
-	return s.roleBiz.UpdateRole(req)
+	return s.roleBiz.UpdateRole(ctx, req)
 }
 
 // DeleteRole is the role.v1.RoleService.DeleteRole method.
-func (s *Service) DeleteRole(_ context.Context, req *connect.Request[rolev1.CommonNameRequest]) (
+func (s *Service) DeleteRole(ctx context.Context, req *connect.Request[rolev1.CommonNameRequest]) (
 	*connect.Response[rolev1.CommonResponse], error,
 ) {
-	return s.roleBiz.DeleteRole(req)
+	return s.roleBiz.DeleteRole(ctx, req)
 }
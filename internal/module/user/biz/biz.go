@@ -1,8 +1,9 @@
 package userbiz
 
 import (
-	"fmt"
+	"context"
 	"strings"
+	"time"
 
 	"github.com/bufbuild/connect-go"
 	"github.com/rs/zerolog/log"
@@ -12,8 +13,15 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/xdorro/golang-grpc-base-project/config"
 	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1/retentionv1connect"
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
 	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
 	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
 )
 
@@ -21,29 +29,61 @@ var _ IUserBiz = &Biz{}
 
 // IUserBiz user service interface.
 type IUserBiz interface {
-	FindAllUsers(req *connect.Request[userv1.FindAllUsersRequest]) (
+	FindAllUsers(ctx context.Context, req *connect.Request[userv1.FindAllUsersRequest]) (
 		*connect.Response[userv1.FindAllUsersResponse], error,
 	)
-	FindUserByID(req *connect.Request[userv1.CommonUUIDRequest]) (*connect.Response[userv1.User], error)
-	CreateUser(req *connect.Request[userv1.CreateUserRequest]) (*connect.Response[userv1.CommonResponse], error)
-	UpdateUser(req *connect.Request[userv1.UpdateUserRequest]) (*connect.Response[userv1.CommonResponse], error)
-	DeleteUser(req *connect.Request[userv1.CommonUUIDRequest]) (*connect.Response[userv1.CommonResponse], error)
+	FindUserByID(ctx context.Context, req *connect.Request[userv1.CommonUUIDRequest]) (*connect.Response[userv1.User], error)
+	CreateUser(ctx context.Context, req *connect.Request[userv1.CreateUserRequest]) (*connect.Response[userv1.CommonResponse], error)
+	UpdateUser(ctx context.Context, req *connect.Request[userv1.UpdateUserRequest]) (*connect.Response[userv1.CommonResponse], error)
+	DeleteUser(ctx context.Context, req *connect.Request[userv1.CommonUUIDRequest]) (*connect.Response[userv1.CommonResponse], error)
+
+	// FindUserByEmail returns the user with the email, without its password.
+	FindUserByEmail(ctx context.Context, email string) (*usermodel.User, error)
+	// ResetPassword replaces the password of the user, applying the password policy, and revokes
+	// all its sessions unless keepSessions is set.
+	ResetPassword(ctx context.Context, id, password string, keepSessions bool) error
+
+	// ImportUsers imports the users returned by next until io.EOF, with a result by row.
+	ImportUsers(ctx context.Context, opts *userbulkv1.ImportOptions, next func() (*userbulkv1.ImportUser, error)) (
+		*userbulkv1.ImportUsersResponse, error,
+	)
+	// ExportUsers sends the users in batches, without their password.
+	ExportUsers(ctx context.Context, send func([]*userbulkv1.User) error) error
+	// AcceptInvite sets the password of a user imported without password, it returns the user id.
+	AcceptInvite(ctx context.Context, email, token, password string) (string, error)
+
+	// ChangeState moves the user to the lifecycle state, revoking its sessions unless active.
+	ChangeState(ctx context.Context, id string, to int32, reason string) (*usermodel.User, error)
+	// FindState returns the user with its lifecycle state.
+	FindState(ctx context.Context, id string) (*usermodel.User, error)
 }
 
 // Biz struct.
 type Biz struct {
+	password *passwordPolicy
+	bulk     *config.Bulk
+
 	// option
+	casbin         casbin.ICasbin
+	session        session.ISession
 	userCollection *mongo.Collection
 }
 
 // Option service option.
 type Option struct {
-	Repo repo.IRepo
+	Config  *config.Config
+	Repo    repo.IRepo
+	Casbin  casbin.ICasbin
+	Session session.ISession
 }
 
 // NewBiz new service.
 func NewBiz(opt *Option) IUserBiz {
 	s := &Biz{
+		password:       newPasswordPolicy(&opt.Config.Password),
+		bulk:           &opt.Config.Bulk,
+		casbin:         opt.Casbin,
+		session:        opt.Session,
 		userCollection: opt.Repo.CollectionModel(&usermodel.User{}),
 	}
 
@@ -51,16 +91,30 @@ func NewBiz(opt *Option) IUserBiz {
 }
 
 // FindAllUsers is the user.v1.UserBiz.FindAllUsers method.
-func (s *Biz) FindAllUsers(req *connect.Request[userv1.FindAllUsersRequest]) (
+func (s *Biz) FindAllUsers(ctx context.Context, req *connect.Request[userv1.FindAllUsersRequest]) (
 	*connect.Response[userv1.FindAllUsersResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "userbiz.FindAllUsers")
+	defer span.End()
+
+	// the deleted users are listed to the roles restoring them
+	includeDeleted, err := casbin.IncludeDeleted(ctx, s.casbin, req.Header(),
+		"/"+retentionv1connect.RetentionServiceName+"/RestoreUser")
+	if err != nil {
+		return nil, err
+	}
+
 	// count all users with filter
-	filter := bson.M{
-		"deleted_at": bson.M{
+	filter := bson.M{}
+	if !includeDeleted {
+		filter["deleted_at"] = bson.M{
 			"$exists": false,
-		},
+		}
+	}
+	count, err := repo.CountDocuments(ctx, s.userCollection, filter)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
 	}
-	count, _ := repo.CountDocuments(s.userCollection, filter)
 	limit := int64(10)
 	totalPages := utils.TotalPage(count, limit)
 	page := utils.CurrentPage(req.Msg.GetPage(), totalPages)
@@ -73,9 +127,9 @@ func (s *Biz) FindAllUsers(req *connect.Request[userv1.FindAllUsersRequest]) (
 		SetLimit(limit).
 		SetSkip((page - 1) * limit)
 
-	data, err := repo.Find[usermodel.User](s.userCollection, filter, opt)
+	data, err := repo.Find[usermodel.User](ctx, s.userCollection, filter, opt)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "user", err)
 	}
 
 	res := &userv1.FindAllUsersResponse{
@@ -88,13 +142,16 @@ func (s *Biz) FindAllUsers(req *connect.Request[userv1.FindAllUsersRequest]) (
 }
 
 // FindUserByID is the user.v1.UserBiz.FindUserByID method.
-func (s *Biz) FindUserByID(req *connect.Request[userv1.CommonUUIDRequest]) (
+func (s *Biz) FindUserByID(ctx context.Context, req *connect.Request[userv1.CommonUUIDRequest]) (
 	*connect.Response[userv1.User], error,
 ) {
+	ctx, span := tracing.Start(ctx, "userbiz.FindUserByID")
+	defer span.End()
+
 	id, err := primitive.ObjectIDFromHex(req.Msg.GetId())
 	if err != nil {
-		log.Err(err).Msg("Failed find user by id")
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		log.Ctx(ctx).Err(err).Msg("Failed find user by id")
+		return nil, errs.InvalidArgument("id", "must be a valid object id")
 	}
 
 	opt := options.
@@ -109,9 +166,9 @@ func (s *Biz) FindUserByID(req *connect.Request[userv1.CommonUUIDRequest]) (
 		},
 	}
 
-	data, err := repo.FindOne[usermodel.User](s.userCollection, filter, opt)
+	data, err := repo.FindOne[usermodel.User](ctx, s.userCollection, filter, opt)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.FromRepo(ctx, "user", err)
 	}
 
 	res := usermodel.UserToProto(data)
@@ -119,15 +176,31 @@ func (s *Biz) FindUserByID(req *connect.Request[userv1.CommonUUIDRequest]) (
 }
 
 // CreateUser is the user.v1.UserBiz.CreateUser method.
-func (s *Biz) CreateUser(req *connect.Request[userv1.CreateUserRequest]) (
+func (s *Biz) CreateUser(ctx context.Context, req *connect.Request[userv1.CreateUserRequest]) (
 	*connect.Response[userv1.CommonResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "userbiz.CreateUser")
+	defer span.End()
+
 	// count all users with filter
-	count, _ := repo.CountDocuments(s.userCollection, bson.M{
+	count, err := repo.CountDocuments(ctx, s.userCollection, bson.M{
 		"email": req.Msg.GetEmail(),
 	})
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
+	}
 	if count > 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("email already exists"))
+		return nil, errs.AlreadyExists("user", "email")
+	}
+
+	// validate password policy & role
+	violations := s.password.validate(req.Msg.GetPassword())
+	if req.Msg.GetRole() != "" && !s.roleExists(req.Msg.GetRole()) {
+		violations = append(violations, &errs.FieldViolation{Field: "role", Description: "role does not exists"})
+	}
+
+	if len(violations) > 0 {
+		return nil, errs.Validation(violations...)
 	}
 
 	role := req.Msg.GetRole()
@@ -140,19 +213,20 @@ func (s *Biz) CreateUser(req *connect.Request[userv1.CreateUserRequest]) (
 		Email:    req.Msg.GetEmail(),
 		Password: req.Msg.GetPassword(),
 		Role:     strings.ToLower(role),
+		Status:   usermodel.StatusActive,
 	}
 	data.PreCreate()
 
 	// hash password
-	err := data.HashPassword()
+	err = data.HashPassword()
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.Internal(ctx, err)
 	}
 
-	result, err := repo.InsertOne(s.userCollection, data)
+	result, err := repo.InsertOne(ctx, s.userCollection, data)
 	if err != nil {
-		log.Err(err).Msg("Error create user")
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		log.Ctx(ctx).Err(err).Msg("Error create user")
+		return nil, errs.FromRepo(ctx, "user", err)
 	}
 
 	res := &userv1.CommonResponse{
@@ -170,12 +244,15 @@ func (s *Biz) CreateUser(req *connect.Request[userv1.CreateUserRequest]) (
 }
 
 // UpdateUser is the user.v1.UserBiz.UpdateUser method.
-func (s *Biz) UpdateUser(req *connect.Request[userv1.UpdateUserRequest]) (
+func (s *Biz) UpdateUser(ctx context.Context, req *connect.Request[userv1.UpdateUserRequest]) (
 	*connect.Response[userv1.CommonResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "userbiz.UpdateUser")
+	defer span.End()
+
 	id, err := primitive.ObjectIDFromHex(req.Msg.GetId())
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.InvalidArgument("id", "must be a valid object id")
 	}
 
 	filter := bson.M{
@@ -185,28 +262,44 @@ func (s *Biz) UpdateUser(req *connect.Request[userv1.UpdateUserRequest]) (
 		},
 	}
 
-	data, err := repo.FindOne[usermodel.User](s.userCollection, filter)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	if _, err = repo.FindOne[usermodel.User](ctx, s.userCollection, filter); err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
 	}
 
 	// count all users with filter
-	count, _ := repo.CountDocuments(s.userCollection, bson.M{
+	count, err := repo.CountDocuments(ctx, s.userCollection, bson.M{
 		"_id":   bson.M{"$ne": id},
 		"email": req.Msg.GetEmail(),
 	})
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
+	}
 	if count > 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("email already exists"))
+		return nil, errs.AlreadyExists("user", "email")
 	}
 
-	data.Name = utils.StringCompareOrPassValue(data.Name, req.Msg.GetName())
-	data.Email = utils.StringCompareOrPassValue(data.Email, req.Msg.GetEmail())
-	data.Role = utils.StringCompareOrPassValue(data.Role, strings.ToLower(req.Msg.GetRole()))
-	data.PreUpdate()
+	if req.Msg.Role != nil && !s.roleExists(req.Msg.GetRole()) {
+		return nil, errs.InvalidArgument("role", "role does not exists")
+	}
 
-	obj := bson.M{"$set": data}
-	if _, err = repo.UpdateOne(s.userCollection, filter, obj); err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	// only the changed fields are set, the state changed since the user was found is kept
+	set := bson.M{"updated_at": time.Now()}
+	if name := req.Msg.GetName(); name != "" {
+		set["name"] = name
+	}
+	if email := req.Msg.GetEmail(); email != "" {
+		set["email"] = email
+	}
+	if role := strings.ToLower(req.Msg.GetRole()); role != "" {
+		set["role"] = role
+	}
+
+	result, err := repo.UpdateOne(ctx, s.userCollection, filter, bson.M{"$set": set})
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, errs.NotFound("user")
 	}
 
 	res := &userv1.CommonResponse{
@@ -216,12 +309,15 @@ func (s *Biz) UpdateUser(req *connect.Request[userv1.UpdateUserRequest]) (
 }
 
 // DeleteUser is the user.v1.UserBiz.DeleteUser method.
-func (s *Biz) DeleteUser(req *connect.Request[userv1.CommonUUIDRequest]) (
+func (s *Biz) DeleteUser(ctx context.Context, req *connect.Request[userv1.CommonUUIDRequest]) (
 	*connect.Response[userv1.CommonResponse], error,
 ) {
+	ctx, span := tracing.Start(ctx, "userbiz.DeleteUser")
+	defer span.End()
+
 	id, err := primitive.ObjectIDFromHex(req.Msg.GetId())
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, errs.InvalidArgument("id", "must be a valid object id")
 	}
 
 	filter := bson.M{
@@ -232,13 +328,21 @@ func (s *Biz) DeleteUser(req *connect.Request[userv1.CommonUUIDRequest]) (
 	}
 
 	// count all users with filter
-	count, _ := repo.CountDocuments(s.userCollection, filter)
+	count, err := repo.CountDocuments(ctx, s.userCollection, filter)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
+	}
 	if count <= 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("user does not exists"))
+		return nil, errs.NotFound("user")
 	}
 
-	if _, err = repo.SoftDeleteOne(s.userCollection, filter); err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	// a deleted user keeps no session
+	if err = s.session.RevokeAll(ctx, req.Msg.GetId()); err != nil {
+		return nil, err
+	}
+
+	if _, err = repo.SoftDeleteOne(ctx, s.userCollection, filter); err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
 	}
 
 	res := &userv1.CommonResponse{
@@ -246,3 +350,77 @@ func (s *Biz) DeleteUser(req *connect.Request[userv1.CommonUUIDRequest]) (
 	}
 	return connect.NewResponse(res), nil
 }
+
+// FindUserByEmail returns the user with the email.
+func (s *Biz) FindUserByEmail(ctx context.Context, email string) (*usermodel.User, error) {
+	ctx, span := tracing.Start(ctx, "userbiz.FindUserByEmail")
+	defer span.End()
+
+	opt := options.
+		FindOne().
+		SetProjection(bson.M{"password": 0})
+
+	filter := bson.M{
+		"email": email,
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}
+
+	data, err := repo.FindOne[usermodel.User](ctx, s.userCollection, filter, opt)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
+	}
+
+	return data, nil
+}
+
+// ResetPassword replaces the password of the user and revokes its sessions unless keepSessions is set.
+func (s *Biz) ResetPassword(ctx context.Context, id, password string, keepSessions bool) error {
+	ctx, span := tracing.Start(ctx, "userbiz.ResetPassword")
+	defer span.End()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errs.InvalidArgument("id", "must be a valid object id")
+	}
+
+	if violations := s.password.validate(password); len(violations) > 0 {
+		return errs.Validation(violations...)
+	}
+
+	filter := bson.M{
+		"_id": oid,
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}
+
+	data := &usermodel.User{Password: password}
+	if err = data.HashPassword(); err != nil {
+		return errs.Internal(ctx, err)
+	}
+	data.PreUpdate()
+
+	// only the password is set, the state changed since the user was found is kept
+	obj := bson.M{"$set": bson.M{"password": data.Password, "updated_at": data.UpdatedAt}}
+	result, err := repo.UpdateOne(ctx, s.userCollection, filter, obj)
+	if err != nil {
+		return errs.FromRepo(ctx, "user", err)
+	}
+	if result.MatchedCount == 0 {
+		return errs.NotFound("user")
+	}
+
+	if keepSessions {
+		return nil
+	}
+
+	// the sessions opened with the previous password are revoked, tracked or not
+	return s.session.RevokeAll(ctx, id)
+}
+
+// roleExists returns true if the role has at least one policy.
+func (s *Biz) roleExists(role string) bool {
+	return len(s.casbin.Enforcer().GetFilteredPolicy(0, strings.ToLower(role))) > 0
+}
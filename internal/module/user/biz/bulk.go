@@ -0,0 +1,479 @@
+package userbiz
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
+	"github.com/xdorro/golang-grpc-base-project/pkg/validate"
+)
+
+// importRules are the constraints of an imported row, they match the rules of CreateUserRequest.
+// The password is checked by the password policy.
+var importRules = []*validate.FieldRule{
+	{Field: "name", MaxLen: 255},
+	{Field: "email", Required: true, Email: true, MaxLen: 255},
+	{Field: "role", MaxLen: 64, Pattern: regexp.MustCompile(`^[A-Za-z0-9_-]+$`)},
+}
+
+// msgInvalidInvite is the message of an unknown, used or expired invite token.
+const msgInvalidInvite = "invite token is invalid or expired"
+
+// importRow is a row of the import with its result.
+type importRow struct {
+	user     *userbulkv1.ImportUser
+	result   *userbulkv1.ImportResult
+	data     *usermodel.User
+	existing *usermodel.User
+}
+
+// ImportUsers imports the rows returned by next until io.EOF, in batches. Every row gets a result,
+// a row failing the validation or the write does not stop the import.
+func (s *Biz) ImportUsers(ctx context.Context, opts *userbulkv1.ImportOptions,
+	next func() (*userbulkv1.ImportUser, error),
+) (*userbulkv1.ImportUsersResponse, error) {
+	ctx, span := tracing.Start(ctx, "userbiz.ImportUsers")
+	defer span.End()
+
+	if opts == nil {
+		opts = &userbulkv1.ImportOptions{}
+	}
+
+	// the rows are read first, so an oversized import is rejected before any write
+	rows := make([]*importRow, 0)
+	for {
+		user, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			user = &userbulkv1.ImportUser{}
+		}
+
+		if len(rows) == s.bulk.MaxRows {
+			return nil, errs.InvalidArgument("users", fmt.Sprintf("must have at most %d rows", s.bulk.MaxRows))
+		}
+
+		rows = append(rows, &importRow{
+			user:   user,
+			result: &userbulkv1.ImportResult{Row: int64(len(rows) + 1), Email: user.Email},
+		})
+	}
+
+	res := &userbulkv1.ImportUsersResponse{
+		DryRun:  opts.DryRun,
+		Results: make([]*userbulkv1.ImportResult, 0, len(rows)),
+	}
+
+	seen := make(map[string]int64)
+	for start := 0; start < len(rows); start += s.bulk.BatchSize {
+		end := start + s.bulk.BatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		if err := s.importBatch(ctx, opts, rows[start:end], seen); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, row := range rows {
+		switch row.result.Action {
+		case userbulkv1.ActionCreate:
+			res.Created++
+		case userbulkv1.ActionUpdate:
+			res.Updated++
+		default:
+			res.Failed++
+		}
+
+		res.Results = append(res.Results, row.result)
+	}
+
+	return res, nil
+}
+
+// importBatch validates and writes a batch of rows, seen are the rows of the emails already imported.
+func (s *Biz) importBatch(ctx context.Context, opts *userbulkv1.ImportOptions, rows []*importRow,
+	seen map[string]int64,
+) error {
+	emails := make([]string, 0, len(rows))
+	for _, row := range rows {
+		emails = append(emails, row.user.Email)
+	}
+
+	// the soft deleted users are kept, their email can not be reused
+	opt := options.
+		Find().
+		SetProjection(bson.M{"password": 0, "invite_token": 0})
+
+	existing, err := repo.Find[usermodel.User](ctx, s.userCollection, bson.M{"email": bson.M{"$in": emails}}, opt)
+	if err != nil {
+		return errs.FromRepo(ctx, "user", err)
+	}
+
+	users := make(map[string]*usermodel.User, len(existing))
+	for _, user := range existing {
+		users[user.Email] = user
+	}
+
+	inserts := make([]*importRow, 0, len(rows))
+	for _, row := range rows {
+		s.validateRow(row, users[row.user.Email], opts, seen)
+		if row.result.Action == userbulkv1.ActionFail || opts.DryRun {
+			continue
+		}
+
+		if row.result.Action == userbulkv1.ActionCreate {
+			if err = s.prepareInsert(ctx, row); err != nil {
+				s.failRow(row, err)
+				continue
+			}
+
+			inserts = append(inserts, row)
+			continue
+		}
+
+		if err = s.updateRow(ctx, row); err != nil {
+			s.failRow(row, err)
+		}
+	}
+
+	s.insertRows(ctx, inserts)
+	return nil
+}
+
+// validateRow sets the action of the row, or its violations.
+func (s *Biz) validateRow(row *importRow, existing *usermodel.User, opts *userbulkv1.ImportOptions,
+	seen map[string]int64,
+) {
+	violations := make([]*errs.FieldViolation, 0)
+	for _, rule := range importRules {
+		var value string
+		switch rule.Field {
+		case "name":
+			value = row.user.Name
+		case "email":
+			value = row.user.Email
+		case "role":
+			value = row.user.Role
+		}
+
+		violations = append(violations, rule.ValidateString(value)...)
+	}
+
+	if row.user.Password != "" {
+		violations = append(violations, s.password.validate(row.user.Password)...)
+	}
+
+	if row.user.Role != "" && !s.roleExists(row.user.Role) {
+		violations = append(violations, &errs.FieldViolation{Field: "role", Description: "role does not exists"})
+	}
+
+	if first, ok := seen[row.user.Email]; ok && row.user.Email != "" {
+		violations = append(violations, &errs.FieldViolation{
+			Field:       "email",
+			Description: fmt.Sprintf("duplicates row %d", first),
+		})
+	} else if row.user.Email != "" {
+		seen[row.user.Email] = row.result.Row
+	}
+
+	row.result.Action = userbulkv1.ActionCreate
+	switch {
+	case existing == nil:
+	case !existing.DeletedAt.IsZero() || !opts.Upsert:
+		violations = append(violations, &errs.FieldViolation{Field: "email", Description: "already exists"})
+	default:
+		row.result.Action = userbulkv1.ActionUpdate
+		row.result.Id = existing.Id
+		row.existing = existing
+
+		// the credentials of the existing users are only replaced on request
+		if row.user.Password != "" && !opts.UpdatePassword {
+			violations = append(violations, &errs.FieldViolation{
+				Field:       "password",
+				Description: "replacing the password of an existing user requires update_password",
+			})
+		}
+
+		if row.user.Role != "" && !strings.EqualFold(row.user.Role, existing.Role) && !opts.UpdateRole {
+			violations = append(violations, &errs.FieldViolation{
+				Field:       "role",
+				Description: "changing the role of an existing user requires update_role",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		s.failRow(row, errs.Validation(violations...))
+		for _, v := range violations {
+			row.result.Violations = append(row.result.Violations, &userbulkv1.Violation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+	}
+}
+
+// prepareInsert builds the user of the row, with its hashed password or an invite token.
+func (s *Biz) prepareInsert(ctx context.Context, row *importRow) error {
+	role := strings.ToLower(row.user.Role)
+	if role == "" {
+		role = "user"
+	}
+
+	row.data = &usermodel.User{
+		Name:     row.user.Name,
+		Email:    row.user.Email,
+		Password: row.user.Password,
+		Role:     role,
+		Status:   usermodel.StatusActive,
+	}
+	row.data.PreCreate()
+
+	if row.user.Password != "" {
+		if err := row.data.HashPassword(); err != nil {
+			return errs.Internal(ctx, err)
+		}
+
+		return nil
+	}
+
+	token, hash, err := newInviteToken()
+	if err != nil {
+		return errs.Internal(ctx, err)
+	}
+
+	// the user is pending until it accepts its invite
+	row.data.Status = usermodel.StatusPending
+	row.data.InviteToken = hash
+	row.data.InviteExpiresAt = time.Now().Add(s.bulk.InviteTTL)
+	row.result.InviteToken = token
+
+	return nil
+}
+
+// insertRows inserts the users of the rows, the rows of the failed documents are failed.
+func (s *Biz) insertRows(ctx context.Context, rows []*importRow) {
+	if len(rows) == 0 {
+		return
+	}
+
+	docs := make([]any, 0, len(rows))
+	for _, row := range rows {
+		docs = append(docs, row.data)
+	}
+
+	_, err := repo.InsertMany(ctx, s.userCollection, docs, options.InsertMany().SetOrdered(false))
+	if err == nil {
+		for _, row := range rows {
+			row.result.Id = row.data.Id
+		}
+
+		return
+	}
+
+	failed := make(map[int]error)
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		for _, writeErr := range bulkErr.WriteErrors {
+			failed[writeErr.Index] = writeErr
+		}
+	}
+
+	for i, row := range rows {
+		if _, ok := failed[i]; ok || len(failed) == 0 {
+			s.failRow(row, errs.FromRepo(ctx, "user", err))
+			continue
+		}
+
+		row.result.Id = row.data.Id
+	}
+}
+
+// updateRow sets the fields of the row on the user with its email, the sessions of the user are
+// revoked first when its password or its role changes.
+func (s *Biz) updateRow(ctx context.Context, row *importRow) error {
+	data := &usermodel.User{
+		Name:     row.user.Name,
+		Password: row.user.Password,
+		Role:     strings.ToLower(row.user.Role),
+	}
+	data.PreUpdate()
+
+	if data.Password != "" || (data.Role != "" && data.Role != row.existing.Role) {
+		if err := s.session.RevokeAll(ctx, row.existing.Id); err != nil {
+			return err
+		}
+	}
+
+	obj := bson.M{"$set": data}
+	if data.Password != "" {
+		if err := data.HashPassword(); err != nil {
+			return errs.Internal(ctx, err)
+		}
+
+		// a password replaces the pending invite
+		obj["$unset"] = bson.M{"invite_token": "", "invite_expires_at": ""}
+		if row.existing.State() == usermodel.StatusPending {
+			data.Status = usermodel.StatusActive
+		}
+	}
+
+	filter := bson.M{
+		"email": row.user.Email,
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}
+
+	if _, err := repo.UpdateOne(ctx, s.userCollection, filter, obj); err != nil {
+		return errs.FromRepo(ctx, "user", err)
+	}
+
+	return nil
+}
+
+// failRow marks the row as failed with the message of the error.
+func (s *Biz) failRow(row *importRow, err error) {
+	row.result.Action = userbulkv1.ActionFail
+	row.result.Id = ""
+	row.result.InviteToken = ""
+	row.result.Error = err.Error()
+
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		row.result.Error = connectErr.Message()
+	}
+}
+
+// ExportUsers sends the users listed by FindAllUsers in batches, without their password.
+func (s *Biz) ExportUsers(ctx context.Context, send func([]*userbulkv1.User) error) error {
+	ctx, span := tracing.Start(ctx, "userbiz.ExportUsers")
+	defer span.End()
+
+	filter := bson.M{
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}
+
+	opt := options.
+		Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetProjection(bson.M{"password": 0, "invite_token": 0, "invite_expires_at": 0})
+
+	err := repo.Each[usermodel.User](ctx, s.userCollection, filter, s.bulk.BatchSize, func(list []*usermodel.User) error {
+		users := make([]*userbulkv1.User, 0, len(list))
+		for _, m := range list {
+			users = append(users, &userbulkv1.User{
+				Id:        m.Id,
+				Name:      m.Name,
+				Email:     m.Email,
+				Role:      m.Role,
+				Status:    m.State(),
+				CreatedAt: m.CreatedAt.Unix(),
+			})
+		}
+
+		return send(users)
+	}, opt)
+	if err != nil {
+		return errs.FromRepo(ctx, "user", err)
+	}
+
+	return nil
+}
+
+// AcceptInvite sets the password of the invited user and consumes the invite token.
+func (s *Biz) AcceptInvite(ctx context.Context, email, token, password string) (string, error) {
+	ctx, span := tracing.Start(ctx, "userbiz.AcceptInvite")
+	defer span.End()
+
+	if violations := s.password.validate(password); len(violations) > 0 {
+		return "", errs.Validation(violations...)
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	filter := bson.M{
+		"email":             email,
+		"invite_token":      hex.EncodeToString(sum[:]),
+		"invite_expires_at": bson.M{"$gt": time.Now()},
+		// a suspended or deactivated user can not accept its invite
+		"status": bson.M{
+			"$nin": []int32{usermodel.StatusSuspended, usermodel.StatusDeactivated},
+		},
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}
+
+	opt := options.
+		FindOne().
+		SetProjection(bson.M{"password": 0})
+
+	data, err := repo.FindOne[usermodel.User](ctx, s.userCollection, filter, opt)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", errs.Unauthenticated(msgInvalidInvite)
+	}
+	if err != nil {
+		return "", errs.FromRepo(ctx, "user", err)
+	}
+
+	update := &usermodel.User{Password: password, Status: usermodel.StatusActive}
+	if err = update.HashPassword(); err != nil {
+		return "", errs.Internal(ctx, err)
+	}
+	update.PreUpdate()
+
+	// the filter keeps the token single use when it is accepted twice concurrently
+	obj := bson.M{
+		"$set":   update,
+		"$unset": bson.M{"invite_token": "", "invite_expires_at": ""},
+	}
+
+	result, err := repo.UpdateOne(ctx, s.userCollection, filter, obj)
+	if err != nil {
+		return "", errs.FromRepo(ctx, "user", err)
+	}
+	if result.MatchedCount == 0 {
+		return "", errs.Unauthenticated(msgInvalidInvite)
+	}
+
+	return data.Id, nil
+}
+
+// newInviteToken returns a random invite token and its sha256 hash, only the hash is stored.
+func newInviteToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	sum := sha256.Sum256([]byte(token))
+
+	return token, hex.EncodeToString(sum[:]), nil
+}
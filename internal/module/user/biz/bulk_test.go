@@ -0,0 +1,150 @@
+package userbiz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+)
+
+// testModel is the casbin model of the config.
+const testModel = `
+[request_definition]
+r = sub, obj
+
+[policy_definition]
+p = sub, obj
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = (g(r.sub, p.sub) || keyMatch(r.sub, p.sub)) && keyMatch(r.obj, p.obj)
+`
+
+// testCasbin is an in-memory casbin of the tests.
+type testCasbin struct {
+	enforcer *casbin.CachedEnforcer
+}
+
+func newTestCasbin(t *testing.T, policies ...[]string) *testCasbin {
+	t.Helper()
+
+	m, err := model.NewModelFromString(testModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enforcer, err := casbin.NewCachedEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, policy := range policies {
+		if _, err = enforcer.AddPolicy(policy[0], policy[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &testCasbin{enforcer: enforcer}
+}
+
+func (c *testCasbin) Enforcer() *casbin.CachedEnforcer {
+	return c.enforcer
+}
+
+func (c *testCasbin) Enforce(_ context.Context, rvals ...any) (bool, error) {
+	return c.enforcer.Enforce(rvals...)
+}
+
+func TestValidateRowUpdate(t *testing.T) {
+	s := &Biz{
+		password: &passwordPolicy{minLength: 8, breached: map[string]struct{}{}},
+		casbin: newTestCasbin(t,
+			[]string{"user", "/user.v1.UserService/FindUserByID"},
+			[]string{"admin", "/user.v1.UserService/FindAllUsers"},
+		),
+	}
+	existing := &usermodel.User{Email: "a@example.com", Role: "user"}
+	existing.Id = "64b7f0c2e4b0a1a2b3c4d5e6"
+
+	tests := []struct {
+		name   string
+		user   *userbulkv1.ImportUser
+		opts   *userbulkv1.ImportOptions
+		action string
+		fields []string
+	}{
+		{
+			name:   "name only",
+			user:   &userbulkv1.ImportUser{Email: "a@example.com", Name: "A"},
+			opts:   &userbulkv1.ImportOptions{Upsert: true},
+			action: userbulkv1.ActionUpdate,
+		},
+		{
+			name:   "without upsert",
+			user:   &userbulkv1.ImportUser{Email: "a@example.com"},
+			opts:   &userbulkv1.ImportOptions{},
+			action: userbulkv1.ActionFail,
+			fields: []string{"email"},
+		},
+		{
+			name:   "password without update_password",
+			user:   &userbulkv1.ImportUser{Email: "a@example.com", Password: "n3w-Passw0rd"},
+			opts:   &userbulkv1.ImportOptions{Upsert: true, UpdateRole: true},
+			action: userbulkv1.ActionFail,
+			fields: []string{"password"},
+		},
+		{
+			name:   "password with update_password",
+			user:   &userbulkv1.ImportUser{Email: "a@example.com", Password: "n3w-Passw0rd"},
+			opts:   &userbulkv1.ImportOptions{Upsert: true, UpdatePassword: true},
+			action: userbulkv1.ActionUpdate,
+		},
+		{
+			name:   "role without update_role",
+			user:   &userbulkv1.ImportUser{Email: "a@example.com", Role: "admin"},
+			opts:   &userbulkv1.ImportOptions{Upsert: true, UpdatePassword: true},
+			action: userbulkv1.ActionFail,
+			fields: []string{"role"},
+		},
+		{
+			name:   "same role without update_role",
+			user:   &userbulkv1.ImportUser{Email: "a@example.com", Role: "User"},
+			opts:   &userbulkv1.ImportOptions{Upsert: true},
+			action: userbulkv1.ActionUpdate,
+		},
+		{
+			name:   "role with update_role",
+			user:   &userbulkv1.ImportUser{Email: "a@example.com", Role: "admin"},
+			opts:   &userbulkv1.ImportOptions{Upsert: true, UpdateRole: true},
+			action: userbulkv1.ActionUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := &importRow{user: tt.user, result: &userbulkv1.ImportResult{Row: 1}}
+			s.validateRow(row, existing, tt.opts, map[string]int64{})
+
+			if row.result.Action != tt.action {
+				t.Errorf("action = %q, want %q (%s)", row.result.Action, tt.action, row.result.Error)
+			}
+
+			fields := make([]string, 0, len(row.result.Violations))
+			for _, v := range row.result.Violations {
+				fields = append(fields, v.Field)
+			}
+			if len(fields) != len(tt.fields) || (len(fields) > 0 && fields[0] != tt.fields[0]) {
+				t.Errorf("violations = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
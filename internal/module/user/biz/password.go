@@ -0,0 +1,87 @@
+package userbiz
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+)
+
+// maxPasswordBytes is the length of the passwords bcrypt hashes, the bytes after are ignored.
+const maxPasswordBytes = 72
+
+// passwordPolicy is the password policy of the users.
+type passwordPolicy struct {
+	minLength int
+	breached  map[string]struct{}
+}
+
+// newPasswordPolicy loads the password policy and the breached password list.
+func newPasswordPolicy(cfg *config.Password) *passwordPolicy {
+	p := &passwordPolicy{
+		minLength: cfg.MinLength,
+		breached:  make(map[string]struct{}),
+	}
+
+	file := cfg.BreachedFile
+	if file == "" {
+		return p
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		log.Err(err).Msg("Error open breached password file")
+		return p
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			p.breached[strings.ToLower(line)] = struct{}{}
+		}
+	}
+
+	log.Info().
+		Int("breached", len(p.breached)).
+		Msg("Loaded password policy")
+
+	return p
+}
+
+// validate returns the violations of the password.
+func (p *passwordPolicy) validate(password string) []*errs.FieldViolation {
+	violations := make([]*errs.FieldViolation, 0)
+
+	if utf8.RuneCountInString(password) < p.minLength {
+		violations = append(violations, &errs.FieldViolation{
+			Field:       "password",
+			Description: fmt.Sprintf("must be at least %d characters", p.minLength),
+		})
+	}
+
+	if len(password) > maxPasswordBytes {
+		violations = append(violations, &errs.FieldViolation{
+			Field:       "password",
+			Description: fmt.Sprintf("must be at most %d bytes", maxPasswordBytes),
+		})
+	}
+
+	if _, ok := p.breached[strings.ToLower(password)]; ok {
+		violations = append(violations, &errs.FieldViolation{
+			Field:       "password",
+			Description: "has appeared in a data breach",
+		})
+	}
+
+	return violations
+}
@@ -0,0 +1,30 @@
+package userbiz
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	p := &passwordPolicy{minLength: 8, breached: map[string]struct{}{"password1": {}}}
+
+	tests := []struct {
+		password string
+		want     []*errs.FieldViolation
+	}{
+		{"correct horse", []*errs.FieldViolation{}},
+		{"short", []*errs.FieldViolation{{Field: "password", Description: "must be at least 8 characters"}}},
+		{"Password1", []*errs.FieldViolation{{Field: "password", Description: "has appeared in a data breach"}}},
+		{strings.Repeat("é", 36), []*errs.FieldViolation{}},
+		// bcrypt ignores the bytes after 72, whatever the number of characters
+		{strings.Repeat("é", 37), []*errs.FieldViolation{{Field: "password", Description: "must be at most 72 bytes"}}},
+	}
+	for _, tt := range tests {
+		if got := p.validate(tt.password); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("validate(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
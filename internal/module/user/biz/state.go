@@ -0,0 +1,93 @@
+package userbiz
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
+)
+
+// maxReasonLength is the maximum length of the reason of a transition.
+const maxReasonLength = 512
+
+// ChangeState moves the user to the state with the reason. Every session of a user leaving
+// the active state is revoked, a transition to the current state only revokes them again.
+func (s *Biz) ChangeState(ctx context.Context, id string, to int32, reason string) (*usermodel.User, error) {
+	ctx, span := tracing.Start(ctx, "userbiz.ChangeState")
+	defer span.End()
+
+	if to != usermodel.StatusActive && reason == "" {
+		return nil, errs.InvalidArgument("reason", "is required")
+	}
+	if utf8.RuneCountInString(reason) > maxReasonLength {
+		return nil, errs.InvalidArgument("reason", "must be at most 512 characters")
+	}
+
+	data, err := s.FindState(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.State() != to {
+		if !data.CanTransition(to) {
+			return nil, errs.FailedPrecondition(errs.ReasonFailedPrecondition, "user can not be "+
+				usermodel.StatusName(to)+" when "+usermodel.StatusName(data.State()))
+		}
+
+		update := &usermodel.User{
+			Status:          to,
+			StatusReason:    reason,
+			StatusChangedAt: time.Now(),
+		}
+		update.PreUpdate()
+
+		obj := bson.M{"$set": update}
+		if reason == "" {
+			obj["$unset"] = bson.M{"status_reason": ""}
+		}
+
+		if _, err = repo.UpdateOne(ctx, s.userCollection, repo.ActiveIDFilter(data.Id), obj); err != nil {
+			return nil, errs.FromRepo(ctx, "user", err)
+		}
+
+		data.Status, data.StatusReason, data.StatusChangedAt = to, reason, update.StatusChangedAt
+	}
+
+	// the access tokens are checked by the interceptor, so the sessions end right away
+	if !data.IsActive() {
+		if err = s.session.RevokeAll(ctx, data.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// FindState returns the user with its state, without its password.
+func (s *Biz) FindState(ctx context.Context, id string) (*usermodel.User, error) {
+	ctx, span := tracing.Start(ctx, "userbiz.FindState")
+	defer span.End()
+
+	if !primitive.IsValidObjectID(id) {
+		return nil, errs.InvalidArgument("id", "must be a valid object id")
+	}
+
+	opt := options.
+		FindOne().
+		SetProjection(bson.M{"password": 0, "invite_token": 0})
+
+	data, err := repo.FindOne[usermodel.User](ctx, s.userCollection, repo.ActiveIDFilter(id), opt)
+	if err != nil {
+		return nil, errs.FromRepo(ctx, "user", err)
+	}
+
+	return data, nil
+}
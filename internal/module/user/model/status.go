@@ -0,0 +1,69 @@
+package usermodel
+
+import (
+	"fmt"
+)
+
+// The lifecycle states of a user, stored in User.Status. The users stored before the states
+// have no status and are active.
+const (
+	StatusActive      int32 = 1
+	StatusSuspended   int32 = 2
+	StatusPending     int32 = 3
+	StatusDeactivated int32 = 4
+)
+
+// statusNames are the names of the states.
+var statusNames = map[int32]string{
+	StatusActive:      "active",
+	StatusSuspended:   "suspended",
+	StatusPending:     "pending",
+	StatusDeactivated: "deactivated",
+}
+
+// transitions are the states reachable from each state. A pending user becomes active by
+// accepting its invite, never by an admin since it has no password yet.
+var transitions = map[int32][]int32{
+	StatusActive:      {StatusSuspended, StatusDeactivated},
+	StatusSuspended:   {StatusActive, StatusDeactivated},
+	StatusPending:     {StatusDeactivated},
+	StatusDeactivated: {StatusActive},
+}
+
+// StatusName returns the name of the state.
+func StatusName(status int32) string {
+	if status == 0 {
+		status = StatusActive
+	}
+
+	if name, ok := statusNames[status]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown(%d)", status)
+}
+
+// State returns the state of the user.
+func (m *User) State() int32 {
+	if m.Status == 0 {
+		return StatusActive
+	}
+
+	return m.Status
+}
+
+// IsActive returns true if the user can sign in.
+func (m *User) IsActive() bool {
+	return m.State() == StatusActive
+}
+
+// CanTransition returns true if the user can move to the state.
+func (m *User) CanTransition(to int32) bool {
+	for _, status := range transitions[m.State()] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,43 @@
+package usermodel
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	states := []int32{StatusActive, StatusSuspended, StatusPending, StatusDeactivated}
+	allowed := map[int32]map[int32]bool{
+		StatusActive:      {StatusSuspended: true, StatusDeactivated: true},
+		StatusSuspended:   {StatusActive: true, StatusDeactivated: true},
+		StatusPending:     {StatusDeactivated: true},
+		StatusDeactivated: {StatusActive: true},
+	}
+
+	for _, from := range states {
+		for _, to := range states {
+			user := &User{Status: from}
+			if got := user.CanTransition(to); got != allowed[from][to] {
+				t.Errorf("%s to %s: CanTransition() = %v, want %v", StatusName(from), StatusName(to), got, allowed[from][to])
+			}
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	legacy := &User{}
+	if legacy.State() != StatusActive || !legacy.IsActive() {
+		t.Error("a user stored without status is not active")
+	}
+
+	for _, status := range []int32{StatusSuspended, StatusPending, StatusDeactivated} {
+		if (&User{Status: status}).IsActive() {
+			t.Errorf("a %s user is active", StatusName(status))
+		}
+	}
+
+	if name := StatusName(0); name != "active" {
+		t.Errorf("StatusName(0) = %q, want active", name)
+	}
+
+	if name := StatusName(9); name != "unknown(9)" {
+		t.Errorf("StatusName(9) = %q, want unknown(9)", name)
+	}
+}
@@ -1,8 +1,10 @@
 package usermodel
 
 import (
-	"github.com/rs/zerolog/log"
+	"time"
+
 	userv1 "github.com/xdorro/proto-base-project/proto-gen-go/user/v1"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 
@@ -28,6 +30,36 @@ type User struct {
 	Password string `json:"password" bson:"password,omitempty"`
 	Role     string `json:"role" bson:"role,omitempty"`
 	Status   int32  `json:"status,omitempty" bson:"status,omitempty"`
+
+	// StatusReason and StatusChangedAt describe the last state transition, see status.go.
+	StatusReason    string    `json:"status_reason,omitempty" bson:"status_reason,omitempty"`
+	StatusChangedAt time.Time `json:"-" bson:"status_changed_at,omitempty"`
+
+	// InviteToken is the sha256 hash of the invite token of a user imported without password.
+	InviteToken     string    `json:"-" bson:"invite_token,omitempty"`
+	InviteExpiresAt time.Time `json:"-" bson:"invite_expires_at,omitempty"`
+
+	// AnonymizedAt is set once the retention removed the personal data of the deleted user.
+	AnonymizedAt time.Time `json:"-" bson:"anonymized_at,omitempty"`
+}
+
+// AnonymizedName is the name of the anonymized users.
+const AnonymizedName = "Deleted user"
+
+// AnonymizeUpdate returns the update removing the personal data of the users, they are deleted
+// and deactivated. The email stays unique and tells the anonymized users apart.
+func AnonymizeUpdate(now time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"name":          AnonymizedName,
+			"email":         bson.M{"$concat": bson.A{"deleted-", bson.M{"$toString": "$_id"}, "@anonymized.invalid"}},
+			"status":        StatusDeactivated,
+			"deleted_at":    bson.M{"$ifNull": bson.A{"$deleted_at", now}},
+			"anonymized_at": now,
+			"updated_at":    now,
+		}}},
+		{{Key: "$unset", Value: bson.A{"password", "status_reason", "invite_token", "invite_expires_at"}}},
+	}
 }
 
 // CollectionName returns the name of the collection from struct name
@@ -54,7 +86,6 @@ func (m *User) PreUpdate() {
 func (m *User) HashPassword() error {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(m.Password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Err(err).Msg("Error hash password")
 		return err
 	}
 
@@ -64,12 +95,7 @@ func (m *User) HashPassword() error {
 
 // ComparePassword compares a password with a hash
 func (m *User) ComparePassword(password string) bool {
-	if err := bcrypt.CompareHashAndPassword([]byte(m.Password), []byte(password)); err != nil {
-		log.Err(err).Msg("Error compare hash and password")
-		return false
-	}
-
-	return true
+	return bcrypt.CompareHashAndPassword([]byte(m.Password), []byte(password)) == nil
 }
 
 // UserToProto converts a user to a proto
@@ -0,0 +1,96 @@
+package userservice
+
+import (
+	"context"
+	"io"
+
+	"github.com/bufbuild/connect-go"
+
+	userbiz "github.com/xdorro/golang-grpc-base-project/internal/module/user/biz"
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1/userbulkv1connect"
+)
+
+var _ IUserBulkService = &BulkService{}
+
+// IUserBulkService user bulk service interface.
+type IUserBulkService interface {
+	userbulkv1connect.UserBulkServiceHandler
+}
+
+// BulkService struct.
+type BulkService struct {
+	// option
+	userBiz userbiz.IUserBiz
+
+	userbulkv1connect.UnimplementedUserBulkServiceHandler
+}
+
+// NewBulkService new bulk service.
+func NewBulkService(opt *Option) IUserBulkService {
+	s := &BulkService{
+		userBiz: opt.UserBiz,
+	}
+
+	return s
+}
+
+// ImportUsers is the userbulk.v1.UserBulkService.ImportUsers method.
+func (s *BulkService) ImportUsers(ctx context.Context, stream *connect.ClientStream[userbulkv1.ImportUsersRequest]) (
+	*connect.Response[userbulkv1.ImportUsersResponse], error,
+) {
+	// the options are read from the first message, before the import starts
+	var opts *userbulkv1.ImportOptions
+	var pending []*userbulkv1.ImportUser
+	if stream.Receive() {
+		opts = stream.Msg().Options
+		pending = stream.Msg().Users
+	} else if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	res, err := s.userBiz.ImportUsers(ctx, opts, func() (*userbulkv1.ImportUser, error) {
+		for len(pending) == 0 {
+			if !stream.Receive() {
+				if err := stream.Err(); err != nil {
+					return nil, err
+				}
+
+				return nil, io.EOF
+			}
+
+			pending = stream.Msg().Users
+		}
+
+		user := pending[0]
+		pending = pending[1:]
+
+		return user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+// ExportUsers is the userbulk.v1.UserBulkService.ExportUsers method.
+func (s *BulkService) ExportUsers(ctx context.Context, _ *connect.Request[userbulkv1.ExportUsersRequest],
+	stream *connect.ServerStream[userbulkv1.ExportUsersResponse],
+) error {
+	return s.userBiz.ExportUsers(ctx, func(users []*userbulkv1.User) error {
+		return stream.Send(&userbulkv1.ExportUsersResponse{Users: users})
+	})
+}
+
+// AcceptInvite is the userbulk.v1.UserBulkService.AcceptInvite method.
+func (s *BulkService) AcceptInvite(ctx context.Context, req *connect.Request[userbulkv1.AcceptInviteRequest]) (
+	*connect.Response[userbulkv1.AcceptInviteResponse], error,
+) {
+	id, err := s.userBiz.AcceptInvite(ctx, req.Msg.Email, req.Msg.Token, req.Msg.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&userbulkv1.AcceptInviteResponse{Id: id}), nil
+}
@@ -40,36 +40,36 @@ func NewService(opt *Option) IUserService {
 }
 
 // FindAllUsers is the user.v1.UserService.FindAllUsers method.
-func (s *Service) FindAllUsers(_ context.Context, req *connect.Request[userv1.FindAllUsersRequest]) (
+func (s *Service) FindAllUsers(ctx context.Context, req *connect.Request[userv1.FindAllUsersRequest]) (
 	*connect.Response[userv1.FindAllUsersResponse], error,
 ) {
-	return s.userBiz.FindAllUsers(req)
+	return s.userBiz.FindAllUsers(ctx, req)
 }
 
 // FindUserByID is the user.v1.UserService.FindUserByID method.
-func (s *Service) FindUserByID(_ context.Context, req *connect.Request[userv1.CommonUUIDRequest]) (
+func (s *Service) FindUserByID(ctx context.Context, req *connect.Request[userv1.CommonUUIDRequest]) (
 	*connect.Response[userv1.User], error,
 ) {
-	return s.userBiz.FindUserByID(req)
+	return s.userBiz.FindUserByID(ctx, req)
 }
 
 // CreateUser is the user.v1.UserService.CreateUser method.
-func (s *Service) CreateUser(_ context.Context, req *connect.Request[userv1.CreateUserRequest]) (
+func (s *Service) CreateUser(ctx context.Context, req *connect.Request[userv1.CreateUserRequest]) (
 	*connect.Response[userv1.CommonResponse], error,
 ) {
-	return s.userBiz.CreateUser(req)
+	return s.userBiz.CreateUser(ctx, req)
 }
 
 // UpdateUser is the user.v1.UserService.UpdateUser method.
-func (s *Service) UpdateUser(_ context.Context, req *connect.Request[userv1.UpdateUserRequest]) (
+func (s *Service) UpdateUser(ctx context.Context, req *connect.Request[userv1.UpdateUserRequest]) (
 	*connect.Response[userv1.CommonResponse], error,
 ) {
-	return s.userBiz.UpdateUser(req)
+	return s.userBiz.UpdateUser(ctx, req)
 }
 
 // DeleteUser is the user.v1.UserService.DeleteUser method.
-func (s *Service) DeleteUser(_ context.Context, req *connect.Request[userv1.CommonUUIDRequest]) (
+func (s *Service) DeleteUser(ctx context.Context, req *connect.Request[userv1.CommonUUIDRequest]) (
 	*connect.Response[userv1.CommonResponse], error,
 ) {
-	return s.userBiz.DeleteUser(req)
+	return s.userBiz.DeleteUser(ctx, req)
 }
@@ -0,0 +1,97 @@
+package userservice
+
+import (
+	"context"
+
+	"github.com/bufbuild/connect-go"
+
+	userbiz "github.com/xdorro/golang-grpc-base-project/internal/module/user/biz"
+	usermodel "github.com/xdorro/golang-grpc-base-project/internal/module/user/model"
+	userstatev1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userstate/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userstate/v1/userstatev1connect"
+)
+
+var _ IUserStateService = &StateService{}
+
+// IUserStateService user state service interface.
+type IUserStateService interface {
+	userstatev1connect.UserStateServiceHandler
+}
+
+// StateService struct.
+type StateService struct {
+	// option
+	userBiz userbiz.IUserBiz
+
+	userstatev1connect.UnimplementedUserStateServiceHandler
+}
+
+// NewStateService new state service.
+func NewStateService(opt *Option) IUserStateService {
+	s := &StateService{
+		userBiz: opt.UserBiz,
+	}
+
+	return s
+}
+
+// SuspendUser is the userstate.v1.UserStateService.SuspendUser method.
+func (s *StateService) SuspendUser(ctx context.Context, req *connect.Request[userstatev1.UserStateRequest]) (
+	*connect.Response[userstatev1.UserState], error,
+) {
+	return s.changeState(ctx, req.Msg, usermodel.StatusSuspended)
+}
+
+// ReactivateUser is the userstate.v1.UserStateService.ReactivateUser method.
+func (s *StateService) ReactivateUser(ctx context.Context, req *connect.Request[userstatev1.UserStateRequest]) (
+	*connect.Response[userstatev1.UserState], error,
+) {
+	return s.changeState(ctx, req.Msg, usermodel.StatusActive)
+}
+
+// DeactivateUser is the userstate.v1.UserStateService.DeactivateUser method.
+func (s *StateService) DeactivateUser(ctx context.Context, req *connect.Request[userstatev1.UserStateRequest]) (
+	*connect.Response[userstatev1.UserState], error,
+) {
+	return s.changeState(ctx, req.Msg, usermodel.StatusDeactivated)
+}
+
+// FindUserState is the userstate.v1.UserStateService.FindUserState method.
+func (s *StateService) FindUserState(ctx context.Context, req *connect.Request[userstatev1.FindUserStateRequest]) (
+	*connect.Response[userstatev1.UserState], error,
+) {
+	data, err := s.userBiz.FindState(ctx, req.Msg.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(UserStateToAPI(data)), nil
+}
+
+// changeState moves the user of the request to the state.
+func (s *StateService) changeState(ctx context.Context, msg *userstatev1.UserStateRequest, to int32) (
+	*connect.Response[userstatev1.UserState], error,
+) {
+	data, err := s.userBiz.ChangeState(ctx, msg.GetId(), to, msg.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(UserStateToAPI(data)), nil
+}
+
+// UserStateToAPI converts the state of a user to its message.
+func UserStateToAPI(m *usermodel.User) *userstatev1.UserState {
+	res := &userstatev1.UserState{
+		Id:     m.Id,
+		Email:  m.Email,
+		State:  usermodel.StatusName(m.State()),
+		Reason: m.StatusReason,
+	}
+
+	if !m.StatusChangedAt.IsZero() {
+		res.ChangedAt = m.StatusChangedAt.Unix()
+	}
+
+	return res
+}
@@ -7,5 +7,7 @@ import (
 // ProviderServiceSet is Service providers.
 var ProviderServiceSet = wire.NewSet(
 	NewService,
+	NewBulkService,
+	NewStateService,
 	wire.Struct(new(Option), "*"),
 )
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+const (
+	// adminAuthNone serves the admin endpoints without authentication.
+	adminAuthNone = "none"
+	// adminAuthBasic authenticates with the basic auth.
+	adminAuthBasic = "basic"
+	// adminAuthToken authenticates with a bearer token.
+	adminAuthToken = "token"
+)
+
+// newAdminServer creates the admin server, nil if it is disabled.
+func (s *Server) newAdminServer() *http.Server {
+	if !s.admin.Enabled {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+
+	// kubernetes probes, never authenticated
+	mux.Handle("/livez", s.health.LivezHandler())
+	mux.Handle("/readyz", s.health.ReadyzHandler())
+
+	// prometheus metrics
+	mux.Handle("/metrics", s.adminAuth(promhttp.Handler()))
+
+	// debug endpoints
+	mux.Handle("/debug/config", s.adminAuth(http.HandlerFunc(s.configHandler)))
+	mux.Handle("/debug/loglevel", s.adminAuth(http.HandlerFunc(s.logLevelHandler)))
+	mux.Handle("/debug/casbin", s.adminAuth(http.HandlerFunc(s.casbinHandler)))
+
+	if s.appDebug {
+		mux.Handle("/debug/pprof/", s.adminAuth(http.HandlerFunc(pprof.Index)))
+		mux.Handle("/debug/pprof/cmdline", s.adminAuth(http.HandlerFunc(pprof.Cmdline)))
+		mux.Handle("/debug/pprof/profile", s.adminAuth(http.HandlerFunc(pprof.Profile)))
+		mux.Handle("/debug/pprof/symbol", s.adminAuth(http.HandlerFunc(pprof.Symbol)))
+		mux.Handle("/debug/pprof/trace", s.adminAuth(http.HandlerFunc(pprof.Trace)))
+	}
+
+	return &http.Server{
+		Addr:              s.admin.Address,
+		Handler:           mux,
+		ReadHeaderTimeout: time.Second,
+		// the cpu profile and trace take up to their seconds parameter
+		WriteTimeout:   2 * time.Minute,
+		MaxHeaderBytes: 8 * 1024, // 8KiB
+	}
+}
+
+// adminAuth authenticates the admin requests with the configured auth.
+func (s *Server) adminAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		switch s.admin.Auth {
+		case adminAuthBasic:
+			username, password, found := r.BasicAuth()
+			ok = found && secureEqual(username, s.admin.Username) && secureEqual(password, s.admin.Password)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Basic realm="admin"`)
+			}
+		case adminAuthToken:
+			token, err := utils.AuthFromHeader(r.Header, utils.TokenType)
+			ok = err == nil && secureEqual(token, s.admin.Token)
+		default:
+			ok = true
+		}
+
+		if !ok {
+			log.Warn().
+				Str("path", r.URL.Path).
+				Str("client_ip", utils.ClientIP(r.Header, r.RemoteAddr, s.config.App.Proxies())).
+				Msg("Admin request unauthenticated")
+			utils.ResponseWithJson(w, http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// configHandler dumps the runtime config without the secrets.
+func (s *Server) configHandler(w http.ResponseWriter, _ *http.Request) {
+	utils.ResponseWithJson(w, http.StatusOK, s.config.Redacted())
+}
+
+// logLevelHandler returns the global log level, or sets it with PUT or POST ?level=debug
+// until the next config reload.
+func (s *Server) logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		level, err := zerolog.ParseLevel(strings.ToLower(r.URL.Query().Get("level")))
+		if err != nil || level == zerolog.NoLevel {
+			utils.ResponseWithJson(w, http.StatusBadRequest, map[string]string{"error": "invalid level"})
+			return
+		}
+
+		zerolog.SetGlobalLevel(level)
+		log.Info().Str("level", level.String()).Msg("Log level changed")
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	utils.ResponseWithJson(w, http.StatusOK, map[string]string{"level": zerolog.GlobalLevel().String()})
+}
+
+// casbinHandler dumps the casbin policies and role assignments.
+func (s *Server) casbinHandler(w http.ResponseWriter, _ *http.Request) {
+	enforcer := s.casbin.Enforcer()
+	utils.ResponseWithJson(w, http.StatusOK, map[string]any{
+		"policies": enforcer.GetPolicy(),
+		"roles":    enforcer.GetGroupingPolicy(),
+	})
+}
+
+// secureEqual compares the secrets in constant time.
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/health"
+)
+
+// newAdminHandler returns the handler of the admin server with the admin config.
+func newAdminHandler(t *testing.T, admin *config.Admin, debug bool) http.Handler {
+	t.Helper()
+
+	cfg := &config.Config{Health: config.Health{Interval: time.Hour, Timeout: time.Second}}
+	s := &Server{
+		appDebug: debug,
+		admin:    admin,
+		config:   cfg,
+		health:   health.NewChecker(cfg),
+	}
+
+	srv := s.newAdminServer()
+	if srv == nil {
+		t.Fatal("newAdminServer() = nil, want the admin server")
+	}
+
+	return srv.Handler
+}
+
+func serveAdmin(handler http.Handler, method, target string, prepare func(r *http.Request)) int {
+	req := httptest.NewRequest(method, target, nil)
+	if prepare != nil {
+		prepare(req)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+func TestNewAdminServerDisabled(t *testing.T) {
+	s := &Server{admin: &config.Admin{}}
+	if srv := s.newAdminServer(); srv != nil {
+		t.Errorf("newAdminServer() = %v, want nil when disabled", srv)
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	basic := &config.Admin{Enabled: true, Auth: adminAuthBasic, Username: "admin", Password: "secret"}
+	token := &config.Admin{Enabled: true, Auth: adminAuthToken, Token: "secret"}
+
+	tests := []struct {
+		name    string
+		admin   *config.Admin
+		target  string
+		prepare func(r *http.Request)
+		want    int
+	}{
+		{
+			name:   "probe without credentials",
+			admin:  basic,
+			target: "/livez",
+			want:   http.StatusOK,
+		},
+		{
+			name:   "basic without credentials",
+			admin:  basic,
+			target: "/metrics",
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:    "basic with a wrong password",
+			admin:   basic,
+			target:  "/metrics",
+			prepare: func(r *http.Request) { r.SetBasicAuth("admin", "wrong") },
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "basic",
+			admin:   basic,
+			target:  "/metrics",
+			prepare: func(r *http.Request) { r.SetBasicAuth("admin", "secret") },
+			want:    http.StatusOK,
+		},
+		{
+			name:    "token with a wrong token",
+			admin:   token,
+			target:  "/debug/config",
+			prepare: func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") },
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "token",
+			admin:   token,
+			target:  "/debug/config",
+			prepare: func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			want:    http.StatusOK,
+		},
+		{
+			name:   "none",
+			admin:  &config.Admin{Enabled: true, Auth: adminAuthNone},
+			target: "/debug/loglevel",
+			want:   http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newAdminHandler(t, tt.admin, false)
+			if got := serveAdmin(handler, http.MethodGet, tt.target, tt.prepare); got != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminPprof(t *testing.T) {
+	admin := &config.Admin{Enabled: true, Auth: adminAuthNone}
+
+	if got := serveAdmin(newAdminHandler(t, admin, false), http.MethodGet, "/debug/pprof/", nil); got != http.StatusNotFound {
+		t.Errorf("GET /debug/pprof/ without debug = %d, want %d", got, http.StatusNotFound)
+	}
+
+	if got := serveAdmin(newAdminHandler(t, admin, true), http.MethodGet, "/debug/pprof/", nil); got != http.StatusOK {
+		t.Errorf("GET /debug/pprof/ with debug = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestLogLevelHandler(t *testing.T) {
+	level := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(level) })
+
+	handler := newAdminHandler(t, &config.Admin{Enabled: true, Auth: adminAuthNone}, false)
+
+	tests := []struct {
+		method string
+		target string
+		want   int
+	}{
+		{method: http.MethodPut, target: "/debug/loglevel?level=WARN", want: http.StatusOK},
+		{method: http.MethodPost, target: "/debug/loglevel?level=verbose", want: http.StatusBadRequest},
+		{method: http.MethodPost, target: "/debug/loglevel", want: http.StatusBadRequest},
+		{method: http.MethodDelete, target: "/debug/loglevel", want: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		if got := serveAdmin(handler, tt.method, tt.target, nil); got != tt.want {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.target, got, tt.want)
+		}
+	}
+
+	if got := zerolog.GlobalLevel(); got != zerolog.WarnLevel {
+		t.Errorf("GlobalLevel() = %v, want %v", got, zerolog.WarnLevel)
+	}
+}
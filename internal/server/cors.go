@@ -1,26 +1,88 @@
 package server
 
 import (
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/rs/cors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+const (
+	// rejectedLogInterval is the minimum interval between two logs of the same rejected origin.
+	rejectedLogInterval = time.Minute
+	// rejectedLogSize caps the number of rejected origins remembered.
+	rejectedLogSize = 1024
 )
 
-// newCORS creates a new cors.
-func newCORS() *cors.Cors {
-	// To let web developers play with the demo service from browsers, we need a
-	// very permissive CORS setup.
+// corsRejectedTotal counts the requests of rejected origins.
+var corsRejectedTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "http_cors_rejected_total",
+	Help: "Total number of requests from origins rejected by the CORS policy.",
+})
+
+// corsAllowedHeaders are the headers of the Connect, gRPC-Web and application protocols.
+var corsAllowedHeaders = []string{
+	"Content-Type",
+	"Connect-Protocol-Version",
+	"Connect-Timeout-Ms",
+	"Connect-Accept-Encoding",
+	"Connect-Content-Encoding",
+	"Grpc-Timeout",
+	"X-Grpc-Web",
+	"X-User-Agent",
+	"Authorization",
+	utils.HeaderAPIKey,
+	utils.HeaderRequestID,
+	utils.HeaderIncludeDeleted,
+}
+
+// originPolicy matches the origins of a CORS profile and logs the rejected ones.
+type originPolicy struct {
+	any       bool
+	exact     map[string]bool
+	wildcards []wildcardOrigin
+	proxies   []*net.IPNet
+
+	mu       sync.Mutex
+	rejected map[string]time.Time
+}
+
+// wildcardOrigin matches the subdomains of a domain, e.g. https://*.example.com.
+type wildcardOrigin struct {
+	scheme string
+	suffix string
+}
+
+// newCORS creates the cors handler of the CORS profile, the rejected origins are logged with
+// the client ip from the headers of the trusted proxies.
+func newCORS(profile *config.CORSProfile, proxies []*net.IPNet) *cors.Cors {
+	policy := newOriginPolicy(profile.AllowedOrigins)
+	policy.proxies = proxies
+
 	return cors.New(cors.Options{
 		AllowedMethods: []string{
 			http.MethodHead,
 			http.MethodGet,
 			http.MethodPost,
+			// the REST gateway methods
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
 		},
-		AllowOriginFunc: func(origin string) bool {
-			// Allow all origins, which effectively disables CORS.
-			return true
-		},
-		AllowedHeaders: []string{"*"},
+		AllowOriginRequestFunc: policy.allow,
+		AllowedHeaders:         append(append([]string{}, corsAllowedHeaders...), profile.AllowedHeaders...),
+		AllowCredentials:       profile.AllowCredentials,
+		MaxAge:                 int(profile.MaxAge.Seconds()),
 		ExposedHeaders: []string{
 			// Content-Type is in the default safelist.
 			"Accept",
@@ -34,6 +96,88 @@ func newCORS() *cors.Cors {
 			"Grpc-Message",
 			"Grpc-Status",
 			"Grpc-Status-Details-Bin",
+			"X-Request-Id",
 		},
 	})
 }
+
+// newOriginPolicy parses the allowed origins, the origins are validated by the config.
+func newOriginPolicy(origins []string) *originPolicy {
+	p := &originPolicy{
+		exact:    make(map[string]bool),
+		rejected: make(map[string]time.Time),
+	}
+
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+
+		switch {
+		case origin == "*":
+			p.any = true
+		case strings.Contains(origin, "://*."):
+			scheme, domain, _ := strings.Cut(origin, "://*.")
+			p.wildcards = append(p.wildcards, wildcardOrigin{scheme: scheme, suffix: "." + domain})
+		default:
+			p.exact[origin] = true
+		}
+	}
+
+	return p
+}
+
+// allow returns true if the origin is allowed, the rejected origins are logged.
+func (p *originPolicy) allow(r *http.Request, origin string) bool {
+	if p.matches(strings.ToLower(origin)) {
+		return true
+	}
+
+	corsRejectedTotal.Inc()
+	if p.shouldLog(origin) {
+		log.Warn().
+			Str("origin", origin).
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("client_ip", utils.ClientIP(r.Header, r.RemoteAddr, p.proxies)).
+			Msg("CORS origin rejected")
+	}
+
+	return false
+}
+
+// matches returns true if the lowercase origin is allowed.
+func (p *originPolicy) matches(origin string) bool {
+	if p.any || p.exact[origin] {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	for _, w := range p.wildcards {
+		if u.Scheme == w.scheme && strings.HasSuffix(u.Host, w.suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shouldLog returns true if the rejected origin was not logged recently.
+func (p *originPolicy) shouldLog(origin string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := p.rejected[origin]; ok && now.Sub(last) < rejectedLogInterval {
+		return false
+	}
+
+	if len(p.rejected) >= rejectedLogSize {
+		p.rejected = make(map[string]time.Time)
+	}
+	p.rejected[origin] = now
+
+	return true
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+func TestOriginPolicyMatches(t *testing.T) {
+	p := newOriginPolicy([]string{"https://App.example.com", "https://*.example.org", "http://localhost:3000"})
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "https://app.example.com", want: true},
+		{origin: "https://api.example.org", want: true},
+		{origin: "https://a.b.example.org", want: true},
+		{origin: "http://localhost:3000", want: true},
+		{origin: "http://app.example.com"},
+		{origin: "https://example.org"},
+		{origin: "http://api.example.org"},
+		{origin: "https://evil-example.org"},
+		{origin: "http://localhost:3001"},
+		{origin: "null"},
+	}
+
+	for _, tt := range tests {
+		if got := p.matches(tt.origin); got != tt.want {
+			t.Errorf("matches(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	if !newOriginPolicy([]string{"*"}).matches("https://any.example.net") {
+		t.Error("matches() = false, want true for every origin")
+	}
+}
+
+func TestOriginPolicyShouldLog(t *testing.T) {
+	p := newOriginPolicy(nil)
+
+	if !p.shouldLog("https://evil.example.com") {
+		t.Error("shouldLog() = false, want true for the first rejection")
+	}
+
+	if p.shouldLog("https://evil.example.com") {
+		t.Error("shouldLog() = true, want false for a rejection logged recently")
+	}
+
+	p.rejected["https://evil.example.com"] = time.Now().Add(-rejectedLogInterval)
+	if !p.shouldLog("https://evil.example.com") {
+		t.Error("shouldLog() = false, want true once the interval elapsed")
+	}
+
+	for n := len(p.rejected); n < rejectedLogSize; n++ {
+		p.rejected[time.Duration(n).String()] = time.Now()
+	}
+	if !p.shouldLog("https://other.example.com") || len(p.rejected) != 1 {
+		t.Errorf("shouldLog() remembers %d origins, want the cache reset", len(p.rejected))
+	}
+}
+
+func TestNewCORS(t *testing.T) {
+	c := newCORS(&config.CORSProfile{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowCredentials: true,
+		AllowedHeaders:   []string{"X-Tenant"},
+		MaxAge:           10 * time.Minute,
+	}, nil)
+	handler := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		origin string
+		header string
+		want   string
+	}{
+		{name: "allowed origin", origin: "https://app.example.com", header: "X-Tenant", want: "https://app.example.com"},
+		{name: "rejected origin", origin: "https://evil.example.com", header: "X-Tenant"},
+		{name: "header not allowed", origin: "https://app.example.com", header: "X-Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/user.v1.UserService/FindAllUsers", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", tt.header)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.want {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.want)
+			}
+
+			if tt.want == "" {
+				return
+			}
+
+			if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want true", got)
+			}
+
+			if got := rec.Header().Get("Access-Control-Max-Age"); got != "600" {
+				t.Errorf("Access-Control-Max-Age = %q, want 600", got)
+			}
+		})
+	}
+}
@@ -2,9 +2,9 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	_ "net/http/pprof"
 	"sync"
 	"time"
 
@@ -13,10 +13,16 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/internal/gateway"
 	"github.com/xdorro/golang-grpc-base-project/internal/service"
-	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/certs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/health"
+	"github.com/xdorro/golang-grpc-base-project/pkg/lifecycle"
+	"github.com/xdorro/golang-grpc-base-project/pkg/logger"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
 )
 
 var _ IServer = (*Server)(nil)
@@ -30,38 +36,69 @@ type IServer interface {
 // Server struct.
 type Server struct {
 	// config
-	appName   string
-	appPort   int
-	pprofPort int
-	appDebug  bool
+	appName    string
+	appPort    int
+	appDebug   bool
+	drainDelay time.Duration
+	cors       *config.CORSProfile
+	admin      *config.Admin
+	config     *config.Config
 
 	// option
-	mux     *http.ServeMux
-	service service.IService
+	lifecycle lifecycle.ILifecycle
+	casbin    casbin.ICasbin
+	certs     certs.IManager
+	tracing   tracing.ITracing
+	health    health.IChecker
+	mux       *http.ServeMux
+	service   service.IService
+	gateway   gateway.IGateway
 
-	mu   sync.Mutex
-	http *http.Server
+	mu        sync.Mutex
+	http      *http.Server
+	adminHTTP *http.Server
 }
 
 // Option server.
 type Option struct {
-	Mux     *http.ServeMux
-	Service service.IService
+	Config    *config.Config
+	Lifecycle lifecycle.ILifecycle
+	Casbin    casbin.ICasbin
+	Certs     certs.IManager
+	Tracing   tracing.ITracing
+	Health    health.IChecker
+	Mux       *http.ServeMux
+	Service   service.IService
+	Gateway   gateway.IGateway
 }
 
 // NewServer new server.
 func NewServer(opt *Option) IServer {
 	s := &Server{
-		appName:   viper.GetString("app.name"),
-		appDebug:  viper.GetBool("app.debug"),
-		appPort:   viper.GetInt("app.port"),
-		pprofPort: viper.GetInt("pprof.port"),
-		mux:       opt.Mux,
-		service:   opt.Service,
+		appName:    opt.Config.App.Name,
+		appDebug:   opt.Config.App.Debug,
+		appPort:    opt.Config.App.Port,
+		drainDelay: opt.Config.Health.DrainDelay,
+		admin:      &opt.Config.Admin,
+		config:     opt.Config,
+		lifecycle:  opt.Lifecycle,
+		casbin:     opt.Casbin,
+		certs:      opt.Certs,
+		tracing:    opt.Tracing,
+		health:     opt.Health,
+		mux:        opt.Mux,
+		service:    opt.Service,
+		gateway:    opt.Gateway,
 	}
 
+	corsProfile, cors := opt.Config.CORS.ActiveProfile(opt.Config.Env)
+	s.cors = cors
+	s.adminHTTP = s.newAdminServer()
+	s.addHooks()
+
 	log.Info().
 		Str("app-name", s.appName).
+		Str("cors-profile", corsProfile).
 		Int("app-port", s.appPort).
 		Msg("Server information loaded")
 
@@ -70,67 +107,141 @@ func NewServer(opt *Option) IServer {
 
 // Run runs the server.
 func (s *Server) Run() error {
+	if err := s.lifecycle.Start(context.Background()); err != nil {
+		return err
+	}
+
 	// we're going to run the different protocol servers in parallel, so
 	// make an errgroup
 	group := new(errgroup.Group)
 
-	// we need a webserver to get the pprof webserver
-	if s.appDebug {
+	// Serve the pprof, metrics, health and debug endpoints on the admin listener.
+	if s.adminHTTP != nil {
 		group.Go(func() error {
-			pprofPort := fmt.Sprintf(":%d", s.pprofPort)
-			log.Info().Msgf("Starting pprof http://localhost%s", pprofPort)
+			log.Info().Msgf("Starting admin http://%s", s.adminHTTP.Addr)
 
-			return http.ListenAndServe(pprofPort, nil)
+			err := s.adminHTTP.ListenAndServe()
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+
+			return err
 		})
 	}
 
 	// Serve the http server on the http listener.
 	group.Go(func() error {
 		appPort := fmt.Sprintf(":%d", s.appPort)
-		log.Info().Msgf("Starting application http://localhost%s", appPort)
 
 		// create new http server
-		s.setServer(&http.Server{
-			Addr: appPort,
-			// Use h2c, so we can serve HTTP/2 without TLS.
-			Handler: h2c.NewHandler(
-				s.customHandler(),
-				&http2.Server{},
-			),
+		srv := &http.Server{
+			Addr:              appPort,
+			Handler:           s.customHandler(),
 			ReadHeaderTimeout: time.Second,
 			ReadTimeout:       1 * time.Minute,
 			WriteTimeout:      1 * time.Minute,
 			MaxHeaderBytes:    8 * 1024, // 8KiB
-		})
+		}
+
+		tlsConfig := s.certs.TLSConfig()
+		if tlsConfig == nil {
+			// Use h2c, so we can serve HTTP/2 without TLS.
+			srv.Handler = h2c.NewHandler(srv.Handler, &http2.Server{})
+			s.setServer(srv)
+
+			log.Info().Msgf("Starting application http://localhost%s", appPort)
+			return srv.ListenAndServe()
+		}
 
-		// run the server
-		return s.http.ListenAndServe()
+		// HTTP/2 is negotiated with ALPN
+		srv.TLSConfig = tlsConfig
+		if err := http2.ConfigureServer(srv, &http2.Server{}); err != nil {
+			return err
+		}
+		s.setServer(srv)
+
+		log.Info().Msgf("Starting application https://localhost%s", appPort)
+		return srv.ListenAndServeTLS("", "")
 	})
 
 	return group.Wait()
 }
 
-// Close closes the server.
+// Close shuts the server down gracefully, phase by phase.
 func (s *Server) Close() error {
-	group := new(errgroup.Group)
+	return s.lifecycle.Stop()
+}
 
-	group.Go(func() error {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+// addHooks registers the shutdown of the server, the store hooks are registered by the service.
+func (s *Server) addHooks() {
+	// report not serving first, so the load balancers drain the traffic
+	s.lifecycle.Append(lifecycle.Hook{
+		Name:  "health",
+		Phase: lifecycle.PhaseUnready,
+		OnStop: func(ctx context.Context) error {
+			s.health.Drain()
+			if s.drainDelay <= 0 {
+				return nil
+			}
 
-		if err := s.http.Shutdown(ctx); err != nil {
-			log.Err(err).Msg("Failed to shutdown http server")
-			return err
-		}
+			log.Info().Msgf("Draining traffic for %s", s.drainDelay)
+			select {
+			case <-time.After(s.drainDelay):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
 
-		return nil
+	s.lifecycle.Append(lifecycle.Hook{
+		Name:  "http",
+		Phase: lifecycle.PhaseDrain,
+		OnStop: func(ctx context.Context) error {
+			srv := s.server()
+			if srv == nil {
+				return nil
+			}
+
+			return srv.Shutdown(ctx)
+		},
 	})
 
-	group.Go(func() error {
-		return s.service.Close()
+	if s.adminHTTP != nil {
+		// the admin server keeps serving the probes and metrics until the requests are drained
+		s.lifecycle.Append(lifecycle.Hook{
+			Name:  "admin",
+			Phase: lifecycle.PhaseJobs,
+			OnStop: func(ctx context.Context) error {
+				return s.adminHTTP.Shutdown(ctx)
+			},
+		})
+	}
+
+	s.lifecycle.Append(lifecycle.Hook{
+		Name:  "tracing",
+		Phase: lifecycle.PhaseFlush,
+		OnStop: func(context.Context) error {
+			return s.tracing.Close()
+		},
 	})
 
-	return group.Wait()
+	// the async payload logs are awaited by the jobs phase
+	s.lifecycle.Append(lifecycle.Hook{
+		Name:  "logger",
+		Phase: lifecycle.PhaseFlush,
+		OnStop: func(context.Context) error {
+			return logger.Close()
+		},
+	})
+
+	s.lifecycle.Append(lifecycle.Hook{
+		Name:  "certs",
+		Phase: lifecycle.PhaseClose,
+		OnStop: func(context.Context) error {
+			return s.certs.Close()
+		},
+	})
 }
 
 // Server adds a new server.
@@ -140,11 +251,14 @@ func (s *Server) setServer(http *http.Server) {
 	s.http = http
 }
 
-// customHandler adds custom handlers to the server.
-func (s *Server) customHandler() http.Handler {
-	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		utils.ResponseWithJson(w, http.StatusOK, "Hello, World!")
-	})
+// server returns the http server, nil if it is not started.
+func (s *Server) server() *http.Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.http
+}
 
-	return newCORS().Handler(s.mux)
+// customHandler wraps the mux, the REST gateway and the docs are registered by the gateway.
+func (s *Server) customHandler() http.Handler {
+	return newCORS(s.cors, s.config.App.Proxies()).Handler(certs.IdentityHandler(s.mux))
 }
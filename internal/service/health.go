@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bufbuild/connect-go"
+	grpchealth "github.com/bufbuild/connect-grpchealth-go"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+const (
+	// healthMongoDB is the health dependency name of MongoDB.
+	healthMongoDB = "mongodb"
+	// healthRedis is the health dependency name of Redis.
+	healthRedis = "redis"
+)
+
+// healthHandler registers the dependency probes and the grpc health handler of the services,
+// the probes are started with the service. The Casbin policy has no probe, it is loaded in memory
+// before the services start, or they fail to start.
+func (s *Service) healthHandler(opts connect.Option) {
+	s.health.AddDependency(healthMongoDB, func(ctx context.Context) error {
+		return s.repo.Client().Ping(ctx, readpref.Primary())
+	})
+
+	// redis is an optional cache, the service keeps serving without it
+	s.health.AddOptionalDependency(healthRedis, func(ctx context.Context) error {
+		return s.redis.Ping(ctx).Err()
+	})
+
+	for _, svc := range s.services {
+		s.health.AddService(svc)
+	}
+
+	s.mux.Handle(grpchealth.NewHandler(s.health, opts))
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	permissionmodel "github.com/xdorro/golang-grpc-base-project/internal/module/permission/model"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1/privacyv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1/retentionv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1/userbulkv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userstate/v1/userstatev1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils/constants"
+)
+
+// protectedProcedures are the sensitive procedures granted to the admin role only, they are seeded
+// with require_auth instead of being public, and are left to the operators afterwards.
+var protectedProcedures = []string{
+	"/" + userbulkv1connect.UserBulkServiceName + "/ImportUsers",
+	"/" + userbulkv1connect.UserBulkServiceName + "/ExportUsers",
+	"/" + userstatev1connect.UserStateServiceName + "/SuspendUser",
+	"/" + userstatev1connect.UserStateServiceName + "/ReactivateUser",
+	"/" + userstatev1connect.UserStateServiceName + "/DeactivateUser",
+	"/" + userstatev1connect.UserStateServiceName + "/FindUserState",
+	"/" + retentionv1connect.RetentionServiceName + "/RestoreUser",
+	"/" + retentionv1connect.RetentionServiceName + "/RestorePermission",
+	"/" + retentionv1connect.RetentionServiceName + "/PurgeUser",
+	"/" + retentionv1connect.RetentionServiceName + "/PurgePermission",
+	"/" + retentionv1connect.RetentionServiceName + "/RunRetention",
+	"/" + privacyv1connect.PrivacyServiceName + "/FindAllErasures",
+	"/" + privacyv1connect.PrivacyServiceName + "/ApproveErasure",
+	"/" + privacyv1connect.PrivacyServiceName + "/RejectErasure",
+}
+
+// selfServiceProcedures are the procedures acting on the caller, they are seeded with require_auth
+// and granted to all the authenticated users.
+var selfServiceProcedures = []string{
+	"/" + privacyv1connect.PrivacyServiceName + "/ExportMyData",
+	"/" + privacyv1connect.PrivacyServiceName + "/RequestErasure",
+}
+
+// allUsers is the casbin subject matching the role of any authenticated user.
+const allUsers = "*"
+
+// SeederReport is the result of a seeder run.
+type SeederReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Inserted  []string          `json:"inserted,omitempty"`
+	Protected []string          `json:"protected,omitempty"`
+	Renamed   map[string]string `json:"renamed,omitempty"`
+	Orphaned  []string          `json:"orphaned,omitempty"`
+	Policies  [][]string        `json:"policies,omitempty"`
+}
+
+// seederServiceInfo runs the seeder in the background at startup.
+func (s *Service) seederServiceInfo(ctx context.Context) {
+	report, err := s.Seed(ctx)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msg("Error seeder permissions")
+		return
+	}
+
+	log.Ctx(ctx).Info().
+		Interface("report", report).
+		Msg("Seeder permissions report")
+}
+
+// Seed inserts the missing permissions of all registered procedures
+// and, when reconcile is enabled, applies renames and removes orphaned permissions.
+func (s *Service) Seed(ctx context.Context) (*SeederReport, error) {
+	report := &SeederReport{
+		DryRun:  s.seeder.Reconcile && s.seeder.DryRun,
+		Renamed: make(map[string]string),
+	}
+
+	if len(s.services) == 0 {
+		return report, nil
+	}
+
+	permissionCollection := s.repo.CollectionModel(&permissionmodel.Permission{})
+
+	// find all permissions with filter
+	filter := bson.M{
+		"deleted_at": bson.M{
+			"$exists": false,
+		},
+	}
+
+	// find all permissions with filter and option
+	opt := options.
+		Find().
+		SetSort(bson.M{"created_at": -1})
+
+	permissions, err := repo.Find[permissionmodel.Permission](ctx, permissionCollection, filter, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	// the renamed permissions keep their row, so their new slugs are not inserted
+	// and their old slugs are not orphaned
+	renamed := make(map[string]string)
+	if s.seeder.Reconcile {
+		renamed = s.seederRenames(ctx, permissions, report)
+	}
+
+	bulk := make([]any, 0)
+	for _, slug := range s.methods {
+		if ok := s.hasSlugInPermissions(permissions, slug); !ok && !hasValue(renamed, slug) {
+			name := slug[strings.LastIndex(slug, "/")+1:]
+			per := &permissionmodel.Permission{
+				Name:        name,
+				Slug:        slug,
+				RequireAuth: isProtected(slug),
+			}
+			per.PreCreate()
+
+			bulk = append(bulk, per)
+			report.Inserted = append(report.Inserted, slug)
+		}
+	}
+
+	if s.seeder.Reconcile {
+		s.seederOrphans(ctx, permissions, renamed, report)
+	}
+
+	if len(bulk) > 0 && !report.DryRun {
+		if _, err = repo.InsertMany(ctx, permissionCollection, bulk); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.seederProtect(ctx, permissions, report.Inserted, report); err != nil {
+		return nil, err
+	}
+
+	if !report.DryRun {
+		_ = s.redis.Del(ctx, constants.ListAuthPermissionsKey)
+		_ = s.casbin.Enforcer().InvalidateCache()
+	}
+
+	return report, nil
+}
+
+// seederProtect grants the protected and self service procedures inserted by this run to the admin
+// role, or to all the users, when they have no policy yet. The permissions seeded before are left
+// as the operators set them, unless seeder.protect re-applies the require_auth and the grants.
+func (s *Service) seederProtect(ctx context.Context, permissions []*permissionmodel.Permission,
+	inserted []string, report *SeederReport,
+) error {
+	enforcer := s.casbin.Enforcer()
+
+	for _, slug := range append(append([]string{}, protectedProcedures...), selfServiceProcedures...) {
+		if !s.hasSlugInMethods(slug) {
+			continue
+		}
+
+		per := s.findSlugInPermissions(permissions, slug)
+		switch {
+		case per == nil && !contains(inserted, slug):
+			// renamed in place, the permission keeps its settings
+			continue
+		case per != nil && (!s.seeder.Protect || per.RequireAuth):
+			continue
+		}
+
+		policies := enforcer.GetFilteredPolicy(1, slug)
+		if per == nil && len(policies) > 0 {
+			continue
+		}
+
+		report.Protected = append(report.Protected, slug)
+		if report.DryRun {
+			continue
+		}
+
+		if per != nil {
+			permissionCollection := s.repo.CollectionModel(&permissionmodel.Permission{})
+			update := bson.M{"$set": bson.M{"require_auth": true, "updated_at": time.Now()}}
+			if _, err := repo.UpdateOne(ctx, permissionCollection, bson.M{"_id": per.Id}, update); err != nil {
+				return err
+			}
+		}
+
+		if len(policies) == 0 {
+			if _, err := enforcer.AddPolicy(s.policySubject(slug), slug); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// policySubject returns the casbin subject granted the protected or self service procedure.
+func (s *Service) policySubject(slug string) string {
+	if contains(selfServiceProcedures, slug) {
+		return allUsers
+	}
+
+	return s.seeder.AdminRole
+}
+
+// isProtected returns true if the procedure requires the authentication when seeded.
+func isProtected(slug string) bool {
+	return contains(protectedProcedures, slug) || contains(selfServiceProcedures, slug)
+}
+
+// contains returns true if the procedures contain the slug, whatever its case.
+func contains(procedures []string, slug string) bool {
+	for _, procedure := range procedures {
+		if strings.EqualFold(slug, procedure) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// seederRenames moves the permissions and the casbin policies of renamed procedures
+// to their new slugs, so the grants carry over. It returns the old slugs of the permissions
+// renamed in place with their new slugs.
+//
+// When the new slug is already seeded, e.g. by a previous start, the policies and the access
+// settings of the old permission are merged into it, and the old permission is orphaned.
+func (s *Service) seederRenames(ctx context.Context, permissions []*permissionmodel.Permission,
+	report *SeederReport,
+) map[string]string {
+	permissionCollection := s.repo.CollectionModel(&permissionmodel.Permission{})
+	enforcer := s.casbin.Enforcer()
+	renamed := make(map[string]string)
+
+	for _, rename := range s.seeder.Renames {
+		if rename == nil || rename.From == "" || rename.To == "" || strings.EqualFold(rename.From, rename.To) {
+			continue
+		}
+
+		per := s.findSlugInPermissions(permissions, rename.From)
+		if per == nil {
+			continue
+		}
+
+		report.Renamed[per.Slug] = rename.To
+		into := s.findSlugInPermissions(permissions, rename.To)
+		if into == nil {
+			renamed[per.Slug] = rename.To
+		}
+
+		oldPolicies := enforcer.GetFilteredPolicy(1, per.Slug)
+		newPolicies := renamePolicies(oldPolicies, rename.To, enforcer.GetFilteredPolicy(1, rename.To))
+		if report.DryRun {
+			continue
+		}
+
+		var err error
+		if into == nil {
+			err = s.seederRename(ctx, per, rename.To)
+		} else if mergeAccess(into, per) {
+			into.PreUpdate()
+			_, err = repo.UpdateOne(ctx, permissionCollection, bson.M{"_id": into.Id}, bson.M{"$set": into})
+		}
+		if err != nil {
+			log.Ctx(ctx).Err(err).Msg("Error rename permission")
+			continue
+		}
+
+		if len(newPolicies) > 0 {
+			if _, err = enforcer.AddPolicies(newPolicies); err != nil {
+				log.Ctx(ctx).Err(err).Msg("Error add renamed policies")
+				continue
+			}
+		}
+
+		if len(oldPolicies) > 0 {
+			if _, err = enforcer.RemovePolicies(oldPolicies); err != nil {
+				log.Ctx(ctx).Err(err).Msg("Error remove renamed policies")
+			}
+		}
+	}
+
+	return renamed
+}
+
+// seederRename stores the new slug of the permission, the listed permission is left as is.
+func (s *Service) seederRename(ctx context.Context, per *permissionmodel.Permission, slug string) error {
+	permissionCollection := s.repo.CollectionModel(&permissionmodel.Permission{})
+
+	update := bson.M{
+		"$set": bson.M{
+			"slug":       slug,
+			"name":       slug[strings.LastIndex(slug, "/")+1:],
+			"updated_at": time.Now(),
+		},
+	}
+	_, err := repo.UpdateOne(ctx, permissionCollection, bson.M{"_id": per.Id}, update)
+
+	return err
+}
+
+// renamePolicies returns the policies of the new slug granting the subjects of the old policies,
+// without the policies the new slug already has.
+func renamePolicies(oldPolicies [][]string, slug string, existing [][]string) [][]string {
+	granted := make(map[string]bool, len(existing))
+	for _, policy := range existing {
+		granted[policy[0]] = true
+	}
+
+	policies := make([][]string, 0, len(oldPolicies))
+	for _, policy := range oldPolicies {
+		if granted[policy[0]] {
+			continue
+		}
+
+		granted[policy[0]] = true
+		policies = append(policies, []string{policy[0], slug})
+	}
+
+	return policies
+}
+
+// mergeAccess merges the access settings of the renamed permission into the permission of its new
+// slug, the settings are never loosened. It returns true if the permission changed.
+func mergeAccess(into, from *permissionmodel.Permission) bool {
+	changed := false
+	if from.RequireAuth && !into.RequireAuth {
+		into.RequireAuth, changed = true, true
+	}
+
+	if from.RequireHash && !into.RequireHash {
+		into.RequireHash, changed = true, true
+	}
+
+	if from.RateLimit > 0 && into.RateLimit == 0 {
+		into.RateLimit, into.RateWindow, into.RateKey = from.RateLimit, from.RateWindow, from.RateKey
+		changed = true
+	}
+
+	return changed
+}
+
+// seederOrphans soft deletes the permissions of procedures that are no longer registered
+// and strips the casbin policies pointing at them.
+// Slugs with a wildcard are custom permissions and are never treated as orphans.
+func (s *Service) seederOrphans(ctx context.Context, permissions []*permissionmodel.Permission,
+	renamed map[string]string, report *SeederReport,
+) {
+	permissionCollection := s.repo.CollectionModel(&permissionmodel.Permission{})
+	enforcer := s.casbin.Enforcer()
+
+	for _, per := range permissions {
+		if _, ok := renamed[per.Slug]; ok || strings.Contains(per.Slug, "*") || s.hasSlugInMethods(per.Slug) {
+			continue
+		}
+
+		report.Orphaned = append(report.Orphaned, per.Slug)
+
+		// the policies of a merged rename are moved, not removed
+		if _, ok := report.Renamed[per.Slug]; !ok {
+			report.Policies = append(report.Policies, enforcer.GetFilteredPolicy(1, per.Slug)...)
+		}
+		if report.DryRun {
+			continue
+		}
+
+		filter := bson.M{"_id": per.Id}
+		if _, err := repo.SoftDeleteOne(ctx, permissionCollection, filter); err != nil {
+			log.Ctx(ctx).Err(err).Msg("Error soft delete orphaned permission")
+			continue
+		}
+
+		if _, err := enforcer.RemoveFilteredPolicy(1, per.Slug); err != nil {
+			log.Ctx(ctx).Err(err).Msg("Error remove orphaned policies")
+		}
+	}
+}
+
+// hasSlugInPermissions
+func (s *Service) hasSlugInPermissions(permissions []*permissionmodel.Permission, slug string) bool {
+	return s.findSlugInPermissions(permissions, slug) != nil
+}
+
+// findSlugInPermissions returns the permission with the given slug.
+func (s *Service) findSlugInPermissions(list []*permissionmodel.Permission, slug string) *permissionmodel.Permission {
+	for _, permission := range list {
+		if strings.EqualFold(slug, permission.Slug) {
+			return permission
+		}
+	}
+
+	return nil
+}
+
+// hasValue returns true if the slug is one of the values of the map.
+func hasValue(m map[string]string, slug string) bool {
+	for _, value := range m {
+		if strings.EqualFold(value, slug) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasSlugInMethods returns true if the slug is a registered procedure.
+func (s *Service) hasSlugInMethods(slug string) bool {
+	for _, method := range s.methods {
+		if strings.EqualFold(slug, method) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	permissionmodel "github.com/xdorro/golang-grpc-base-project/internal/module/permission/model"
+)
+
+func TestRenamePolicies(t *testing.T) {
+	oldPolicies := [][]string{
+		{"admin", "/user.v1.UserService/FindAllUsers"},
+		{"support", "/user.v1.UserService/FindAllUsers"},
+	}
+
+	tests := []struct {
+		name     string
+		existing [][]string
+		want     [][]string
+	}{
+		{
+			name: "new slug without policies",
+			want: [][]string{
+				{"admin", "/user.v1.UserService/ListUsers"},
+				{"support", "/user.v1.UserService/ListUsers"},
+			},
+		},
+		{
+			name:     "new slug already granted to a subject",
+			existing: [][]string{{"admin", "/user.v1.UserService/ListUsers"}},
+			want:     [][]string{{"support", "/user.v1.UserService/ListUsers"}},
+		},
+		{
+			name: "new slug granted to all the subjects",
+			existing: [][]string{
+				{"support", "/user.v1.UserService/ListUsers"},
+				{"admin", "/user.v1.UserService/ListUsers"},
+			},
+			want: [][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renamePolicies(oldPolicies, "/user.v1.UserService/ListUsers", tt.existing)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renamePolicies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeAccess(t *testing.T) {
+	tests := []struct {
+		name    string
+		into    permissionmodel.Permission
+		from    permissionmodel.Permission
+		want    permissionmodel.Permission
+		changed bool
+	}{
+		{
+			name:    "require auth carries over",
+			from:    permissionmodel.Permission{RequireAuth: true, RequireHash: true},
+			want:    permissionmodel.Permission{RequireAuth: true, RequireHash: true},
+			changed: true,
+		},
+		{
+			name: "settings are never loosened",
+			into: permissionmodel.Permission{RequireAuth: true, RateLimit: 5, RateWindow: 60, RateKey: "ip"},
+			from: permissionmodel.Permission{RateLimit: 10, RateWindow: 1, RateKey: "user"},
+			want: permissionmodel.Permission{RequireAuth: true, RateLimit: 5, RateWindow: 60, RateKey: "ip"},
+		},
+		{
+			name:    "rate limit carries over",
+			from:    permissionmodel.Permission{RateLimit: 10, RateWindow: 60, RateKey: "ip"},
+			want:    permissionmodel.Permission{RateLimit: 10, RateWindow: 60, RateKey: "ip"},
+			changed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			into := tt.into
+			if changed := mergeAccess(&into, &tt.from); changed != tt.changed {
+				t.Errorf("mergeAccess() = %v, want %v", changed, tt.changed)
+			}
+
+			if !reflect.DeepEqual(into, tt.want) {
+				t.Errorf("mergeAccess() permission = %+v, want %+v", into, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasValue(t *testing.T) {
+	renamed := map[string]string{"/user.v1.UserService/FindAllUsers": "/user.v1.UserService/ListUsers"}
+
+	if !hasValue(renamed, "/user.v1.userservice/listusers") {
+		t.Error("hasValue() = false, want true for the new slug")
+	}
+
+	if hasValue(renamed, "/user.v1.UserService/FindAllUsers") {
+		t.Error("hasValue() = true, want false for the old slug")
+	}
+}
+
+func TestIsProtected(t *testing.T) {
+	for _, slug := range []string{
+		"/userbulk.v1.UserBulkService/ImportUsers",
+		"/userstate.v1.UserStateService/SuspendUser",
+		"/retention.v1.RetentionService/PurgeUser",
+		"/retention.v1.RetentionService/RunRetention",
+		"/privacy.v1.PrivacyService/ApproveErasure",
+		"/privacy.v1.PrivacyService/ExportMyData",
+	} {
+		if !isProtected(slug) {
+			t.Errorf("isProtected(%q) = false, want true", slug)
+		}
+	}
+
+	if isProtected("/userbulk.v1.UserBulkService/AcceptInvite") {
+		t.Error("isProtected() = true, want false for AcceptInvite")
+	}
+}
+
+func TestPolicySubject(t *testing.T) {
+	s := &Service{seeder: &config.Seeder{AdminRole: "admin"}}
+
+	tests := []struct {
+		slug string
+		want string
+	}{
+		{slug: "/privacy.v1.PrivacyService/ExportMyData", want: "*"},
+		{slug: "/privacy.v1.privacyservice/requesterasure", want: "*"},
+		{slug: "/privacy.v1.PrivacyService/ApproveErasure", want: "admin"},
+		{slug: "/retention.v1.RetentionService/PurgeUser", want: "admin"},
+	}
+
+	for _, tt := range tests {
+		if got := s.policySubject(tt.slug); got != tt.want {
+			t.Errorf("policySubject(%q) = %q, want %q", tt.slug, got, tt.want)
+		}
+	}
+}
+
+// testModel is the casbin model of the config.
+const testModel = `
+[request_definition]
+r = sub, obj
+
+[policy_definition]
+p = sub, obj
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = (g(r.sub, p.sub) || keyMatch(r.sub, p.sub)) && keyMatch(r.obj, p.obj)
+`
+
+func TestSelfServicePolicy(t *testing.T) {
+	m, err := model.NewModelFromString(testModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enforcer, err := casbin.NewEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Service{seeder: &config.Seeder{AdminRole: "admin"}}
+	for _, slug := range []string{
+		"/privacy.v1.PrivacyService/ExportMyData",
+		"/privacy.v1.PrivacyService/ApproveErasure",
+	} {
+		if _, err = enforcer.AddPolicy(s.policySubject(slug), slug); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		role string
+		slug string
+		want bool
+	}{
+		{role: "user", slug: "/privacy.v1.PrivacyService/ExportMyData", want: true},
+		{role: "admin", slug: "/privacy.v1.PrivacyService/ExportMyData", want: true},
+		{role: "user", slug: "/privacy.v1.PrivacyService/ApproveErasure"},
+		{role: "admin", slug: "/privacy.v1.PrivacyService/ApproveErasure", want: true},
+	}
+
+	for _, tt := range tests {
+		if got, _ := enforcer.Enforce(tt.role, tt.slug); got != tt.want {
+			t.Errorf("Enforce(%q, %q) = %v, want %v", tt.role, tt.slug, got, tt.want)
+		}
+	}
+}
+
+// enforcerCasbin serves the policies of an in-memory enforcer.
+type enforcerCasbin struct {
+	enforcer *casbin.CachedEnforcer
+}
+
+func (c *enforcerCasbin) Enforcer() *casbin.CachedEnforcer { return c.enforcer }
+
+func (c *enforcerCasbin) Enforce(_ context.Context, rvals ...any) (bool, error) {
+	return c.enforcer.Enforce(rvals...)
+}
+
+func TestSeederProtect(t *testing.T) {
+	const (
+		importUsers  = "/userbulk.v1.UserBulkService/ImportUsers"
+		exportUsers  = "/userbulk.v1.UserBulkService/ExportUsers"
+		exportMyData = "/privacy.v1.PrivacyService/ExportMyData"
+		suspendUser  = "/userstate.v1.UserStateService/SuspendUser"
+	)
+
+	m, err := model.NewModelFromString(testModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enforcer, err := casbin.NewCachedEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the operator granted the new export to the support role before it was seeded
+	if _, err = enforcer.AddPolicy("support", exportUsers); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Service{
+		seeder:  &config.Seeder{AdminRole: "admin"},
+		casbin:  &enforcerCasbin{enforcer: enforcer},
+		methods: []string{importUsers, exportUsers, exportMyData, suspendUser},
+	}
+
+	// the operator made the suspension public after it was seeded
+	permissions := []*permissionmodel.Permission{{Slug: suspendUser}}
+
+	report := &SeederReport{}
+	if err = s.seederProtect(context.Background(), permissions, []string{importUsers, exportUsers, exportMyData},
+		report); err != nil {
+		t.Fatalf("seederProtect() error = %v", err)
+	}
+
+	if want := []string{importUsers, exportMyData}; !reflect.DeepEqual(report.Protected, want) {
+		t.Errorf("seederProtect() protected = %v, want %v", report.Protected, want)
+	}
+
+	want := [][]string{{"support", exportUsers}, {"admin", importUsers}, {"*", exportMyData}}
+	if got := enforcer.GetPolicy(); !reflect.DeepEqual(got, want) {
+		t.Errorf("seederProtect() policies = %v, want %v", got, want)
+	}
+}
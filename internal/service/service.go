@@ -9,58 +9,78 @@ import (
 	"sync"
 
 	"github.com/bufbuild/connect-go"
-	grpchealth "github.com/bufbuild/connect-grpchealth-go"
 	grpcreflect "github.com/bufbuild/connect-grpcreflect-go"
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 	"github.com/xdorro/proto-base-project/proto-gen-go/auth/v1/authv1connect"
 	"github.com/xdorro/proto-base-project/proto-gen-go/permission/v1/permissionv1connect"
 	"github.com/xdorro/proto-base-project/proto-gen-go/role/v1/rolev1connect"
 	"github.com/xdorro/proto-base-project/proto-gen-go/user/v1/userv1connect"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/xdorro/golang-grpc-base-project/config"
 	"github.com/xdorro/golang-grpc-base-project/internal/interceptor"
+	auditservice "github.com/xdorro/golang-grpc-base-project/internal/module/audit/service"
 	authservice "github.com/xdorro/golang-grpc-base-project/internal/module/auth/service"
-	permissionmodel "github.com/xdorro/golang-grpc-base-project/internal/module/permission/model"
 	permissionservice "github.com/xdorro/golang-grpc-base-project/internal/module/permission/service"
+	privacyservice "github.com/xdorro/golang-grpc-base-project/internal/module/privacy/service"
+	retentionservice "github.com/xdorro/golang-grpc-base-project/internal/module/retention/service"
 	roleservice "github.com/xdorro/golang-grpc-base-project/internal/module/role/service"
 	userservice "github.com/xdorro/golang-grpc-base-project/internal/module/user/service"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1/auditv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1/privacyv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1/retentionv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1/userbulkv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userstate/v1/userstatev1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/casbin"
+	"github.com/xdorro/golang-grpc-base-project/pkg/health"
+	"github.com/xdorro/golang-grpc-base-project/pkg/lifecycle"
 	"github.com/xdorro/golang-grpc-base-project/pkg/redis"
 	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
-	"github.com/xdorro/golang-grpc-base-project/pkg/utils/constants"
 )
 
 var _ IService = &Service{}
 
 // IService service interface.
 type IService interface {
+	// Seed inserts the permissions of the registered procedures, see the seeder config.
+	Seed(ctx context.Context) (*SeederReport, error)
 	Close() error
 }
 
 // Option service option.
 type Option struct {
+	Config      *config.Config
+	Lifecycle   lifecycle.ILifecycle
 	Mux         *http.ServeMux
 	Interceptor interceptor.IInterceptor
 	Repo        repo.IRepo
 	Redis       redis.IRedis
+	Casbin      casbin.ICasbin
+	Health      health.IChecker
 
+	AuditService      auditservice.IAuditService
 	UserService       userservice.IUserService
+	UserBulkService   userservice.IUserBulkService
+	UserStateService  userservice.IUserStateService
 	AuthService       authservice.IAuthService
 	PermissionService permissionservice.IPermissionService
 	RoleService       roleservice.IRoleService
+	RetentionService  retentionservice.IRetentionService
+	PrivacyService    privacyservice.IPrivacyService
 }
 
 // Service struct.
 type Service struct {
-	seederService bool
+	seeder *config.Seeder
 
 	// options
+	lifecycle   lifecycle.ILifecycle
 	mux         *http.ServeMux
 	interceptor interceptor.IInterceptor
 	repo        repo.IRepo
 	redis       redis.IRedis
+	casbin      casbin.ICasbin
+	health      health.IChecker
 
 	mu       sync.Mutex
 	services []string
@@ -70,16 +90,28 @@ type Service struct {
 // NewService new service.
 func NewService(opt *Option) IService {
 	s := &Service{
-		seederService: viper.GetBool("seeder.service"),
-		mux:           opt.Mux,
-		repo:          opt.Repo,
-		redis:         opt.Redis,
+		seeder:    &opt.Config.Seeder,
+		lifecycle: opt.Lifecycle,
+		mux:       opt.Mux,
+		repo:      opt.Repo,
+		redis:     opt.Redis,
+		casbin:    opt.Casbin,
+		health:    opt.Health,
 	}
 
 	// Add connect options
 	connectOption := connect.WithOptions(
 		connect.WithCompressMinBytes(1024),
-		connect.WithInterceptors(opt.Interceptor.UnaryInterceptor()),
+		connect.WithInterceptors(
+			opt.Interceptor.RequestIDInterceptor(),
+			opt.Interceptor.TracingInterceptor(),
+			opt.Interceptor.MetricsInterceptor(),
+			opt.Interceptor.RateLimitInterceptor(),
+			opt.Interceptor.UnaryInterceptor(),
+			opt.Interceptor.AuditInterceptor(),
+			opt.Interceptor.ValidateInterceptor(),
+			opt.Interceptor.StreamInterceptor(),
+		),
 	)
 
 	// Add your handlers here
@@ -88,6 +120,16 @@ func NewService(opt *Option) IService {
 			return userv1connect.NewUserServiceHandler(opt.UserService, connectOption)
 		})
 
+	s.addServiceHandler(userbulkv1connect.UnimplementedUserBulkServiceHandler{},
+		func() (string, http.Handler) {
+			return userbulkv1connect.NewUserBulkServiceHandler(opt.UserBulkService, connectOption)
+		})
+
+	s.addServiceHandler(userstatev1connect.UnimplementedUserStateServiceHandler{},
+		func() (string, http.Handler) {
+			return userstatev1connect.NewUserStateServiceHandler(opt.UserStateService, connectOption)
+		})
+
 	s.addServiceHandler(authv1connect.UnimplementedAuthServiceHandler{},
 		func() (string, http.Handler) {
 			return authv1connect.NewAuthServiceHandler(opt.AuthService, connectOption)
@@ -103,13 +145,46 @@ func NewService(opt *Option) IService {
 			return rolev1connect.NewRoleServiceHandler(opt.RoleService, connectOption)
 		})
 
+	s.addServiceHandler(auditv1connect.UnimplementedAuditServiceHandler{},
+		func() (string, http.Handler) {
+			return auditv1connect.NewAuditServiceHandler(opt.AuditService, connectOption)
+		})
+
+	s.addServiceHandler(retentionv1connect.UnimplementedRetentionServiceHandler{},
+		func() (string, http.Handler) {
+			return retentionv1connect.NewRetentionServiceHandler(opt.RetentionService, connectOption)
+		})
+
+	s.addServiceHandler(privacyv1connect.UnimplementedPrivacyServiceHandler{},
+		func() (string, http.Handler) {
+			return privacyv1connect.NewPrivacyServiceHandler(opt.PrivacyService, connectOption)
+		})
+
 	// Add service handlers
 	s.serviceHandler(connectOption)
 
-	// seeder Service
-	if s.seederService {
-		go s.seederServiceInfo()
-	}
+	s.lifecycle.Append(lifecycle.Hook{
+		Name: "service",
+		OnStart: func(context.Context) error {
+			s.health.Start()
+
+			// seeder Service
+			if s.seeder.Service {
+				s.lifecycle.Go("seeder", s.seederServiceInfo)
+			}
+
+			return nil
+		},
+	})
+
+	// the stores are closed once the requests and the background jobs are done
+	s.lifecycle.Append(lifecycle.Hook{
+		Name:  "stores",
+		Phase: lifecycle.PhaseClose,
+		OnStop: func(context.Context) error {
+			return s.Close()
+		},
+	})
 
 	return s
 }
@@ -132,8 +207,7 @@ func (s *Service) Close() error {
 // serviceHandler add the service handler.
 func (s *Service) serviceHandler(opts connect.Option) {
 	// Health check
-	checker := grpchealth.NewStaticChecker(s.services...)
-	s.mux.Handle(grpchealth.NewHandler(checker, opts))
+	s.healthHandler(opts)
 
 	// Reflect serviceHandler
 	reflector := grpcreflect.NewStaticReflector(s.services...)
@@ -173,68 +247,3 @@ func (s *Service) addServiceHandler(svcMethod any, fn func() (string, http.Handl
 
 	logger.Msgf("Added service handler for %s", svcName)
 }
-
-// seederServiceInfo
-func (s *Service) seederServiceInfo() {
-	if len(s.services) == 0 {
-		return
-	}
-
-	permissionCollection := s.repo.CollectionModel(&permissionmodel.Permission{})
-
-	// find all permissions with filter
-	filter := bson.M{
-		"deleted_at": bson.M{
-			"$exists": false,
-		},
-		"slug": bson.M{
-			"$in": s.methods,
-		},
-	}
-
-	// find all permissions with filter and option
-	opt := options.
-		Find().
-		SetSort(bson.M{"created_at": -1})
-
-	bulk := make([]any, 0)
-	permissions, _ := repo.Find[permissionmodel.Permission](permissionCollection, filter, opt)
-
-	for _, slug := range s.methods {
-		if ok := s.hasSlugInPermissions(permissions, slug); !ok {
-			name := slug[strings.LastIndex(slug, "/")+1:]
-			per := &permissionmodel.Permission{
-				Name: name,
-				Slug: slug,
-			}
-			per.PreCreate()
-
-			bulk = append(bulk, per)
-		}
-	}
-
-	if len(bulk) > 0 {
-		_, err := repo.InsertMany(permissionCollection, bulk)
-		if err != nil {
-			log.Err(err).Msg("Error create permission")
-		}
-
-		_ = s.redis.Del(context.Background(), constants.ListAuthPermissionsKey)
-
-		log.Info().
-			Interface("data", bulk).
-			Msg("Insert permissions")
-	}
-
-}
-
-// hasSlugInPermissions
-func (s *Service) hasSlugInPermissions(permissions []*permissionmodel.Permission, slug string) bool {
-	for _, permission := range permissions {
-		if strings.EqualFold(slug, permission.Slug) {
-			return true
-		}
-	}
-
-	return false
-}
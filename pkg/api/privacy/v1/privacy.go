@@ -0,0 +1,8 @@
+package privacyv1
+
+// The statuses of an Erasure.
+const (
+	ErasurePending   = "pending"
+	ErasureRejected  = "rejected"
+	ErasureCompleted = "completed"
+)
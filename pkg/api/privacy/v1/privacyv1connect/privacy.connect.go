@@ -0,0 +1,184 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: privacy/v1/privacy.proto
+
+package privacyv1connect
+
+import (
+	context "context"
+	errors "errors"
+	connect_go "github.com/bufbuild/connect-go"
+	v1 "github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect_go.IsAtLeastVersion0_1_0
+
+const (
+	// PrivacyServiceName is the fully-qualified name of the PrivacyService service.
+	PrivacyServiceName = "privacy.v1.PrivacyService"
+)
+
+// PrivacyServiceClient is a client for the privacy.v1.PrivacyService service.
+type PrivacyServiceClient interface {
+	// Export everything stored for the caller
+	ExportMyData(context.Context, *connect_go.Request[v1.ExportMyDataRequest]) (*connect_go.Response[v1.DataArchive], error)
+	// Request the erasure of the caller, awaiting the approval of an admin
+	RequestErasure(context.Context, *connect_go.Request[v1.RequestErasureRequest]) (*connect_go.Response[v1.Erasure], error)
+	// Find the erasures, the most recent first
+	FindAllErasures(context.Context, *connect_go.Request[v1.FindAllErasuresRequest]) (*connect_go.Response[v1.FindAllErasuresResponse], error)
+	// Erase the user of a pending erasure
+	ApproveErasure(context.Context, *connect_go.Request[v1.ReviewErasureRequest]) (*connect_go.Response[v1.Erasure], error)
+	// Reject a pending erasure, the note is required
+	RejectErasure(context.Context, *connect_go.Request[v1.ReviewErasureRequest]) (*connect_go.Response[v1.Erasure], error)
+}
+
+// NewPrivacyServiceClient constructs a client for the privacy.v1.PrivacyService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewPrivacyServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts ...connect_go.ClientOption) PrivacyServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &privacyServiceClient{
+		exportMyData: connect_go.NewClient[v1.ExportMyDataRequest, v1.DataArchive](
+			httpClient,
+			baseURL+"/privacy.v1.PrivacyService/ExportMyData",
+			opts...,
+		),
+		requestErasure: connect_go.NewClient[v1.RequestErasureRequest, v1.Erasure](
+			httpClient,
+			baseURL+"/privacy.v1.PrivacyService/RequestErasure",
+			opts...,
+		),
+		findAllErasures: connect_go.NewClient[v1.FindAllErasuresRequest, v1.FindAllErasuresResponse](
+			httpClient,
+			baseURL+"/privacy.v1.PrivacyService/FindAllErasures",
+			opts...,
+		),
+		approveErasure: connect_go.NewClient[v1.ReviewErasureRequest, v1.Erasure](
+			httpClient,
+			baseURL+"/privacy.v1.PrivacyService/ApproveErasure",
+			opts...,
+		),
+		rejectErasure: connect_go.NewClient[v1.ReviewErasureRequest, v1.Erasure](
+			httpClient,
+			baseURL+"/privacy.v1.PrivacyService/RejectErasure",
+			opts...,
+		),
+	}
+}
+
+// privacyServiceClient implements PrivacyServiceClient.
+type privacyServiceClient struct {
+	exportMyData    *connect_go.Client[v1.ExportMyDataRequest, v1.DataArchive]
+	requestErasure  *connect_go.Client[v1.RequestErasureRequest, v1.Erasure]
+	findAllErasures *connect_go.Client[v1.FindAllErasuresRequest, v1.FindAllErasuresResponse]
+	approveErasure  *connect_go.Client[v1.ReviewErasureRequest, v1.Erasure]
+	rejectErasure   *connect_go.Client[v1.ReviewErasureRequest, v1.Erasure]
+}
+
+// ExportMyData calls privacy.v1.PrivacyService.ExportMyData.
+func (c *privacyServiceClient) ExportMyData(ctx context.Context, req *connect_go.Request[v1.ExportMyDataRequest]) (*connect_go.Response[v1.DataArchive], error) {
+	return c.exportMyData.CallUnary(ctx, req)
+}
+
+// RequestErasure calls privacy.v1.PrivacyService.RequestErasure.
+func (c *privacyServiceClient) RequestErasure(ctx context.Context, req *connect_go.Request[v1.RequestErasureRequest]) (*connect_go.Response[v1.Erasure], error) {
+	return c.requestErasure.CallUnary(ctx, req)
+}
+
+// FindAllErasures calls privacy.v1.PrivacyService.FindAllErasures.
+func (c *privacyServiceClient) FindAllErasures(ctx context.Context, req *connect_go.Request[v1.FindAllErasuresRequest]) (*connect_go.Response[v1.FindAllErasuresResponse], error) {
+	return c.findAllErasures.CallUnary(ctx, req)
+}
+
+// ApproveErasure calls privacy.v1.PrivacyService.ApproveErasure.
+func (c *privacyServiceClient) ApproveErasure(ctx context.Context, req *connect_go.Request[v1.ReviewErasureRequest]) (*connect_go.Response[v1.Erasure], error) {
+	return c.approveErasure.CallUnary(ctx, req)
+}
+
+// RejectErasure calls privacy.v1.PrivacyService.RejectErasure.
+func (c *privacyServiceClient) RejectErasure(ctx context.Context, req *connect_go.Request[v1.ReviewErasureRequest]) (*connect_go.Response[v1.Erasure], error) {
+	return c.rejectErasure.CallUnary(ctx, req)
+}
+
+// PrivacyServiceHandler is an implementation of the privacy.v1.PrivacyService service.
+type PrivacyServiceHandler interface {
+	// Export everything stored for the caller
+	ExportMyData(context.Context, *connect_go.Request[v1.ExportMyDataRequest]) (*connect_go.Response[v1.DataArchive], error)
+	// Request the erasure of the caller, awaiting the approval of an admin
+	RequestErasure(context.Context, *connect_go.Request[v1.RequestErasureRequest]) (*connect_go.Response[v1.Erasure], error)
+	// Find the erasures, the most recent first
+	FindAllErasures(context.Context, *connect_go.Request[v1.FindAllErasuresRequest]) (*connect_go.Response[v1.FindAllErasuresResponse], error)
+	// Erase the user of a pending erasure
+	ApproveErasure(context.Context, *connect_go.Request[v1.ReviewErasureRequest]) (*connect_go.Response[v1.Erasure], error)
+	// Reject a pending erasure, the note is required
+	RejectErasure(context.Context, *connect_go.Request[v1.ReviewErasureRequest]) (*connect_go.Response[v1.Erasure], error)
+}
+
+// NewPrivacyServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewPrivacyServiceHandler(svc PrivacyServiceHandler, opts ...connect_go.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle("/privacy.v1.PrivacyService/ExportMyData", connect_go.NewUnaryHandler(
+		"/privacy.v1.PrivacyService/ExportMyData",
+		svc.ExportMyData,
+		opts...,
+	))
+	mux.Handle("/privacy.v1.PrivacyService/RequestErasure", connect_go.NewUnaryHandler(
+		"/privacy.v1.PrivacyService/RequestErasure",
+		svc.RequestErasure,
+		opts...,
+	))
+	mux.Handle("/privacy.v1.PrivacyService/FindAllErasures", connect_go.NewUnaryHandler(
+		"/privacy.v1.PrivacyService/FindAllErasures",
+		svc.FindAllErasures,
+		opts...,
+	))
+	mux.Handle("/privacy.v1.PrivacyService/ApproveErasure", connect_go.NewUnaryHandler(
+		"/privacy.v1.PrivacyService/ApproveErasure",
+		svc.ApproveErasure,
+		opts...,
+	))
+	mux.Handle("/privacy.v1.PrivacyService/RejectErasure", connect_go.NewUnaryHandler(
+		"/privacy.v1.PrivacyService/RejectErasure",
+		svc.RejectErasure,
+		opts...,
+	))
+	return "/privacy.v1.PrivacyService/", mux
+}
+
+// UnimplementedPrivacyServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedPrivacyServiceHandler struct{}
+
+func (UnimplementedPrivacyServiceHandler) ExportMyData(context.Context, *connect_go.Request[v1.ExportMyDataRequest]) (*connect_go.Response[v1.DataArchive], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("privacy.v1.PrivacyService.ExportMyData is not implemented"))
+}
+
+func (UnimplementedPrivacyServiceHandler) RequestErasure(context.Context, *connect_go.Request[v1.RequestErasureRequest]) (*connect_go.Response[v1.Erasure], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("privacy.v1.PrivacyService.RequestErasure is not implemented"))
+}
+
+func (UnimplementedPrivacyServiceHandler) FindAllErasures(context.Context, *connect_go.Request[v1.FindAllErasuresRequest]) (*connect_go.Response[v1.FindAllErasuresResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("privacy.v1.PrivacyService.FindAllErasures is not implemented"))
+}
+
+func (UnimplementedPrivacyServiceHandler) ApproveErasure(context.Context, *connect_go.Request[v1.ReviewErasureRequest]) (*connect_go.Response[v1.Erasure], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("privacy.v1.PrivacyService.ApproveErasure is not implemented"))
+}
+
+func (UnimplementedPrivacyServiceHandler) RejectErasure(context.Context, *connect_go.Request[v1.ReviewErasureRequest]) (*connect_go.Response[v1.Erasure], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("privacy.v1.PrivacyService.RejectErasure is not implemented"))
+}
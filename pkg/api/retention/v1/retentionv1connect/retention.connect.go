@@ -0,0 +1,184 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: retention/v1/retention.proto
+
+package retentionv1connect
+
+import (
+	context "context"
+	errors "errors"
+	connect_go "github.com/bufbuild/connect-go"
+	v1 "github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect_go.IsAtLeastVersion0_1_0
+
+const (
+	// RetentionServiceName is the fully-qualified name of the RetentionService service.
+	RetentionServiceName = "retention.v1.RetentionService"
+)
+
+// RetentionServiceClient is a client for the retention.v1.RetentionService service.
+type RetentionServiceClient interface {
+	// Restore a soft deleted user
+	RestoreUser(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error)
+	// Restore a soft deleted permission
+	RestorePermission(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error)
+	// Remove a soft deleted user with its sessions
+	PurgeUser(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error)
+	// Remove a soft deleted permission with its policies
+	PurgePermission(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error)
+	// Purge or anonymize the records deleted for longer than their retention age
+	RunRetention(context.Context, *connect_go.Request[v1.RunRetentionRequest]) (*connect_go.Response[v1.RunRetentionResponse], error)
+}
+
+// NewRetentionServiceClient constructs a client for the retention.v1.RetentionService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewRetentionServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts ...connect_go.ClientOption) RetentionServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &retentionServiceClient{
+		restoreUser: connect_go.NewClient[v1.RecordRequest, v1.RecordResponse](
+			httpClient,
+			baseURL+"/retention.v1.RetentionService/RestoreUser",
+			opts...,
+		),
+		restorePermission: connect_go.NewClient[v1.RecordRequest, v1.RecordResponse](
+			httpClient,
+			baseURL+"/retention.v1.RetentionService/RestorePermission",
+			opts...,
+		),
+		purgeUser: connect_go.NewClient[v1.RecordRequest, v1.RecordResponse](
+			httpClient,
+			baseURL+"/retention.v1.RetentionService/PurgeUser",
+			opts...,
+		),
+		purgePermission: connect_go.NewClient[v1.RecordRequest, v1.RecordResponse](
+			httpClient,
+			baseURL+"/retention.v1.RetentionService/PurgePermission",
+			opts...,
+		),
+		runRetention: connect_go.NewClient[v1.RunRetentionRequest, v1.RunRetentionResponse](
+			httpClient,
+			baseURL+"/retention.v1.RetentionService/RunRetention",
+			opts...,
+		),
+	}
+}
+
+// retentionServiceClient implements RetentionServiceClient.
+type retentionServiceClient struct {
+	restoreUser       *connect_go.Client[v1.RecordRequest, v1.RecordResponse]
+	restorePermission *connect_go.Client[v1.RecordRequest, v1.RecordResponse]
+	purgeUser         *connect_go.Client[v1.RecordRequest, v1.RecordResponse]
+	purgePermission   *connect_go.Client[v1.RecordRequest, v1.RecordResponse]
+	runRetention      *connect_go.Client[v1.RunRetentionRequest, v1.RunRetentionResponse]
+}
+
+// RestoreUser calls retention.v1.RetentionService.RestoreUser.
+func (c *retentionServiceClient) RestoreUser(ctx context.Context, req *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error) {
+	return c.restoreUser.CallUnary(ctx, req)
+}
+
+// RestorePermission calls retention.v1.RetentionService.RestorePermission.
+func (c *retentionServiceClient) RestorePermission(ctx context.Context, req *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error) {
+	return c.restorePermission.CallUnary(ctx, req)
+}
+
+// PurgeUser calls retention.v1.RetentionService.PurgeUser.
+func (c *retentionServiceClient) PurgeUser(ctx context.Context, req *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error) {
+	return c.purgeUser.CallUnary(ctx, req)
+}
+
+// PurgePermission calls retention.v1.RetentionService.PurgePermission.
+func (c *retentionServiceClient) PurgePermission(ctx context.Context, req *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error) {
+	return c.purgePermission.CallUnary(ctx, req)
+}
+
+// RunRetention calls retention.v1.RetentionService.RunRetention.
+func (c *retentionServiceClient) RunRetention(ctx context.Context, req *connect_go.Request[v1.RunRetentionRequest]) (*connect_go.Response[v1.RunRetentionResponse], error) {
+	return c.runRetention.CallUnary(ctx, req)
+}
+
+// RetentionServiceHandler is an implementation of the retention.v1.RetentionService service.
+type RetentionServiceHandler interface {
+	// Restore a soft deleted user
+	RestoreUser(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error)
+	// Restore a soft deleted permission
+	RestorePermission(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error)
+	// Remove a soft deleted user with its sessions
+	PurgeUser(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error)
+	// Remove a soft deleted permission with its policies
+	PurgePermission(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error)
+	// Purge or anonymize the records deleted for longer than their retention age
+	RunRetention(context.Context, *connect_go.Request[v1.RunRetentionRequest]) (*connect_go.Response[v1.RunRetentionResponse], error)
+}
+
+// NewRetentionServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewRetentionServiceHandler(svc RetentionServiceHandler, opts ...connect_go.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle("/retention.v1.RetentionService/RestoreUser", connect_go.NewUnaryHandler(
+		"/retention.v1.RetentionService/RestoreUser",
+		svc.RestoreUser,
+		opts...,
+	))
+	mux.Handle("/retention.v1.RetentionService/RestorePermission", connect_go.NewUnaryHandler(
+		"/retention.v1.RetentionService/RestorePermission",
+		svc.RestorePermission,
+		opts...,
+	))
+	mux.Handle("/retention.v1.RetentionService/PurgeUser", connect_go.NewUnaryHandler(
+		"/retention.v1.RetentionService/PurgeUser",
+		svc.PurgeUser,
+		opts...,
+	))
+	mux.Handle("/retention.v1.RetentionService/PurgePermission", connect_go.NewUnaryHandler(
+		"/retention.v1.RetentionService/PurgePermission",
+		svc.PurgePermission,
+		opts...,
+	))
+	mux.Handle("/retention.v1.RetentionService/RunRetention", connect_go.NewUnaryHandler(
+		"/retention.v1.RetentionService/RunRetention",
+		svc.RunRetention,
+		opts...,
+	))
+	return "/retention.v1.RetentionService/", mux
+}
+
+// UnimplementedRetentionServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedRetentionServiceHandler struct{}
+
+func (UnimplementedRetentionServiceHandler) RestoreUser(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("retention.v1.RetentionService.RestoreUser is not implemented"))
+}
+
+func (UnimplementedRetentionServiceHandler) RestorePermission(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("retention.v1.RetentionService.RestorePermission is not implemented"))
+}
+
+func (UnimplementedRetentionServiceHandler) PurgeUser(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("retention.v1.RetentionService.PurgeUser is not implemented"))
+}
+
+func (UnimplementedRetentionServiceHandler) PurgePermission(context.Context, *connect_go.Request[v1.RecordRequest]) (*connect_go.Response[v1.RecordResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("retention.v1.RetentionService.PurgePermission is not implemented"))
+}
+
+func (UnimplementedRetentionServiceHandler) RunRetention(context.Context, *connect_go.Request[v1.RunRetentionRequest]) (*connect_go.Response[v1.RunRetentionResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("retention.v1.RetentionService.RunRetention is not implemented"))
+}
@@ -0,0 +1,11 @@
+package userbulkv1
+
+// The actions of an ImportResult.
+const (
+	// ActionCreate is the action of a row creating a user.
+	ActionCreate = "create"
+	// ActionUpdate is the action of a row updating the user with its email.
+	ActionUpdate = "update"
+	// ActionFail is the action of a row that was not written.
+	ActionFail = "fail"
+)
@@ -0,0 +1,160 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: userstate/v1/userstate.proto
+
+package userstatev1connect
+
+import (
+	context "context"
+	errors "errors"
+	connect_go "github.com/bufbuild/connect-go"
+	v1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userstate/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect_go.IsAtLeastVersion0_1_0
+
+const (
+	// UserStateServiceName is the fully-qualified name of the UserStateService service.
+	UserStateServiceName = "userstate.v1.UserStateService"
+)
+
+// UserStateServiceClient is a client for the userstate.v1.UserStateService service.
+type UserStateServiceClient interface {
+	// Suspend an active user, the reason is required
+	SuspendUser(context.Context, *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error)
+	// Reactivate a suspended or deactivated user
+	ReactivateUser(context.Context, *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error)
+	// Deactivate a user, the reason is required
+	DeactivateUser(context.Context, *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error)
+	// Find the state of a user
+	FindUserState(context.Context, *connect_go.Request[v1.FindUserStateRequest]) (*connect_go.Response[v1.UserState], error)
+}
+
+// NewUserStateServiceClient constructs a client for the userstate.v1.UserStateService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewUserStateServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts ...connect_go.ClientOption) UserStateServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &userStateServiceClient{
+		suspendUser: connect_go.NewClient[v1.UserStateRequest, v1.UserState](
+			httpClient,
+			baseURL+"/userstate.v1.UserStateService/SuspendUser",
+			opts...,
+		),
+		reactivateUser: connect_go.NewClient[v1.UserStateRequest, v1.UserState](
+			httpClient,
+			baseURL+"/userstate.v1.UserStateService/ReactivateUser",
+			opts...,
+		),
+		deactivateUser: connect_go.NewClient[v1.UserStateRequest, v1.UserState](
+			httpClient,
+			baseURL+"/userstate.v1.UserStateService/DeactivateUser",
+			opts...,
+		),
+		findUserState: connect_go.NewClient[v1.FindUserStateRequest, v1.UserState](
+			httpClient,
+			baseURL+"/userstate.v1.UserStateService/FindUserState",
+			opts...,
+		),
+	}
+}
+
+// userStateServiceClient implements UserStateServiceClient.
+type userStateServiceClient struct {
+	suspendUser    *connect_go.Client[v1.UserStateRequest, v1.UserState]
+	reactivateUser *connect_go.Client[v1.UserStateRequest, v1.UserState]
+	deactivateUser *connect_go.Client[v1.UserStateRequest, v1.UserState]
+	findUserState  *connect_go.Client[v1.FindUserStateRequest, v1.UserState]
+}
+
+// SuspendUser calls userstate.v1.UserStateService.SuspendUser.
+func (c *userStateServiceClient) SuspendUser(ctx context.Context, req *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error) {
+	return c.suspendUser.CallUnary(ctx, req)
+}
+
+// ReactivateUser calls userstate.v1.UserStateService.ReactivateUser.
+func (c *userStateServiceClient) ReactivateUser(ctx context.Context, req *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error) {
+	return c.reactivateUser.CallUnary(ctx, req)
+}
+
+// DeactivateUser calls userstate.v1.UserStateService.DeactivateUser.
+func (c *userStateServiceClient) DeactivateUser(ctx context.Context, req *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error) {
+	return c.deactivateUser.CallUnary(ctx, req)
+}
+
+// FindUserState calls userstate.v1.UserStateService.FindUserState.
+func (c *userStateServiceClient) FindUserState(ctx context.Context, req *connect_go.Request[v1.FindUserStateRequest]) (*connect_go.Response[v1.UserState], error) {
+	return c.findUserState.CallUnary(ctx, req)
+}
+
+// UserStateServiceHandler is an implementation of the userstate.v1.UserStateService service.
+type UserStateServiceHandler interface {
+	// Suspend an active user, the reason is required
+	SuspendUser(context.Context, *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error)
+	// Reactivate a suspended or deactivated user
+	ReactivateUser(context.Context, *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error)
+	// Deactivate a user, the reason is required
+	DeactivateUser(context.Context, *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error)
+	// Find the state of a user
+	FindUserState(context.Context, *connect_go.Request[v1.FindUserStateRequest]) (*connect_go.Response[v1.UserState], error)
+}
+
+// NewUserStateServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewUserStateServiceHandler(svc UserStateServiceHandler, opts ...connect_go.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle("/userstate.v1.UserStateService/SuspendUser", connect_go.NewUnaryHandler(
+		"/userstate.v1.UserStateService/SuspendUser",
+		svc.SuspendUser,
+		opts...,
+	))
+	mux.Handle("/userstate.v1.UserStateService/ReactivateUser", connect_go.NewUnaryHandler(
+		"/userstate.v1.UserStateService/ReactivateUser",
+		svc.ReactivateUser,
+		opts...,
+	))
+	mux.Handle("/userstate.v1.UserStateService/DeactivateUser", connect_go.NewUnaryHandler(
+		"/userstate.v1.UserStateService/DeactivateUser",
+		svc.DeactivateUser,
+		opts...,
+	))
+	mux.Handle("/userstate.v1.UserStateService/FindUserState", connect_go.NewUnaryHandler(
+		"/userstate.v1.UserStateService/FindUserState",
+		svc.FindUserState,
+		opts...,
+	))
+	return "/userstate.v1.UserStateService/", mux
+}
+
+// UnimplementedUserStateServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedUserStateServiceHandler struct{}
+
+func (UnimplementedUserStateServiceHandler) SuspendUser(context.Context, *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("userstate.v1.UserStateService.SuspendUser is not implemented"))
+}
+
+func (UnimplementedUserStateServiceHandler) ReactivateUser(context.Context, *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("userstate.v1.UserStateService.ReactivateUser is not implemented"))
+}
+
+func (UnimplementedUserStateServiceHandler) DeactivateUser(context.Context, *connect_go.Request[v1.UserStateRequest]) (*connect_go.Response[v1.UserState], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("userstate.v1.UserStateService.DeactivateUser is not implemented"))
+}
+
+func (UnimplementedUserStateServiceHandler) FindUserState(context.Context, *connect_go.Request[v1.FindUserStateRequest]) (*connect_go.Response[v1.UserState], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("userstate.v1.UserStateService.FindUserState is not implemented"))
+}
@@ -0,0 +1,219 @@
+package bulkio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+)
+
+const (
+	// FormatCSV is a CSV file with a header line.
+	FormatCSV = "csv"
+	// FormatNDJSON is a file with one JSON object by line.
+	FormatNDJSON = "ndjson"
+)
+
+// importColumns are the columns of an imported CSV file, email is required.
+var importColumns = []string{"name", "email", "password", "role"}
+
+// exportColumns are the columns of an exported CSV file.
+var exportColumns = []string{"id", "name", "email", "role", "status", "created_at"}
+
+// FormatOf returns the format of the file extension, CSV by default.
+func FormatOf(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	default:
+		return FormatCSV
+	}
+}
+
+// CheckFormat returns an error if the format is unknown.
+func CheckFormat(format string) error {
+	if format != FormatCSV && format != FormatNDJSON {
+		return fmt.Errorf("format must be %s or %s", FormatCSV, FormatNDJSON)
+	}
+
+	return nil
+}
+
+// Reader reads the users of an import file.
+type Reader struct {
+	format  string
+	csv     *csv.Reader
+	columns []int
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewReader returns a reader of the format, the header of a CSV file is read first.
+func NewReader(r io.Reader, format string) (*Reader, error) {
+	if err := CheckFormat(format); err != nil {
+		return nil, err
+	}
+
+	reader := &Reader{format: format}
+	if format == FormatNDJSON {
+		reader.scanner = bufio.NewScanner(r)
+		reader.scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		return reader, nil
+	}
+
+	reader.csv = csv.NewReader(r)
+	reader.csv.TrimLeadingSpace = true
+
+	header, err := reader.csv.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	reader.columns = make([]int, len(importColumns))
+	for i := range reader.columns {
+		reader.columns[i] = -1
+	}
+
+	for i, name := range header {
+		found := false
+		for j, column := range importColumns {
+			if strings.EqualFold(strings.TrimSpace(name), column) {
+				reader.columns[j] = i
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("header: unknown column %q, the columns are %s", name,
+				strings.Join(importColumns, ","))
+		}
+	}
+
+	if reader.columns[1] < 0 {
+		return nil, errors.New("header: the email column is required")
+	}
+
+	return reader, nil
+}
+
+// Read returns the next user, io.EOF at the end of the file. The errors have the line number.
+func (r *Reader) Read() (*userbulkv1.ImportUser, error) {
+	if r.format == FormatNDJSON {
+		return r.readNDJSON()
+	}
+
+	record, err := r.csv.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	value := func(column int) string {
+		if i := r.columns[column]; i >= 0 {
+			return strings.TrimSpace(record[i])
+		}
+
+		return ""
+	}
+
+	return &userbulkv1.ImportUser{
+		Name:     value(0),
+		Email:    value(1),
+		Password: value(2),
+		Role:     value(3),
+	}, nil
+}
+
+// readNDJSON returns the user of the next non empty line.
+func (r *Reader) readNDJSON() (*userbulkv1.ImportUser, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		dec := json.NewDecoder(strings.NewReader(line))
+		dec.DisallowUnknownFields()
+
+		user := &userbulkv1.ImportUser{}
+		if err := dec.Decode(user); err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+
+		return user, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", r.line+1, err)
+	}
+
+	return nil, io.EOF
+}
+
+// Writer writes the exported users.
+type Writer struct {
+	format string
+	csv    *csv.Writer
+	json   *json.Encoder
+	buf    *bufio.Writer
+}
+
+// NewWriter returns a writer of the format, the header of a CSV file is written first.
+func NewWriter(w io.Writer, format string) (*Writer, error) {
+	if err := CheckFormat(format); err != nil {
+		return nil, err
+	}
+
+	writer := &Writer{format: format}
+	if format == FormatNDJSON {
+		writer.buf = bufio.NewWriter(w)
+		writer.json = json.NewEncoder(writer.buf)
+		return writer, nil
+	}
+
+	writer.csv = csv.NewWriter(w)
+	if err := writer.csv.Write(exportColumns); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// Write writes the user, the password is never part of the exported users.
+func (w *Writer) Write(user *userbulkv1.User) error {
+	if w.format == FormatNDJSON {
+		return w.json.Encode(user)
+	}
+
+	createdAt := ""
+	if user.CreatedAt > 0 {
+		createdAt = time.Unix(user.CreatedAt, 0).UTC().Format(time.RFC3339)
+	}
+
+	return w.csv.Write([]string{
+		user.Id,
+		user.Name,
+		user.Email,
+		user.Role,
+		strconv.Itoa(int(user.Status)),
+		createdAt,
+	})
+}
+
+// Flush writes the buffered users.
+func (w *Writer) Flush() error {
+	if w.format == FormatNDJSON {
+		return w.buf.Flush()
+	}
+
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,147 @@
+package bulkio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+)
+
+func readAll(t *testing.T, input, format string) ([]*userbulkv1.ImportUser, error) {
+	t.Helper()
+
+	reader, err := NewReader(strings.NewReader(input), format)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*userbulkv1.ImportUser, 0)
+	for {
+		user, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return users, nil
+		}
+		if err != nil {
+			return users, err
+		}
+
+		users = append(users, user)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]string{
+		"users.csv":    FormatCSV,
+		"users.NDJSON": FormatNDJSON,
+		"users.jsonl":  FormatNDJSON,
+		"users":        FormatCSV,
+		"-":            FormatCSV,
+	}
+
+	for file, want := range tests {
+		if got := FormatOf(file); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", file, got, want)
+		}
+	}
+
+	if err := CheckFormat("xml"); err == nil {
+		t.Error("CheckFormat(xml) error = nil, want an error")
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	users, err := readAll(t, "Email, name\n a@example.com , Alice\nb@example.com,\n", FormatCSV)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if len(users) != 2 {
+		t.Fatalf("Read() = %d users, want 2", len(users))
+	}
+
+	if users[0].GetEmail() != "a@example.com" || users[0].GetName() != "Alice" || users[0].GetRole() != "" {
+		t.Errorf("Read() = %v, want a@example.com Alice", users[0])
+	}
+
+	if users[1].GetEmail() != "b@example.com" || users[1].GetName() != "" {
+		t.Errorf("Read() = %v, want b@example.com", users[1])
+	}
+}
+
+func TestReadCSVHeader(t *testing.T) {
+	for name, input := range map[string]string{
+		"empty file":     "",
+		"unknown column": "email,age\n",
+		"missing email":  "name,role\n",
+	} {
+		if _, err := NewReader(strings.NewReader(input), FormatCSV); err == nil {
+			t.Errorf("NewReader() %s error = nil, want an error", name)
+		}
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	input := "{\"email\":\"a@example.com\",\"role\":\"admin\"}\n\n{\"email\":\"b@example.com\"}\n"
+	users, err := readAll(t, input, FormatNDJSON)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if len(users) != 2 || users[0].GetRole() != "admin" || users[1].GetEmail() != "b@example.com" {
+		t.Errorf("Read() = %v, want a@example.com and b@example.com", users)
+	}
+
+	_, err = readAll(t, "{\"email\":\"a@example.com\"}\n\n{\"age\":3}\n", FormatNDJSON)
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("Read() error = %v, want the unknown field on line 3", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	user := &userbulkv1.User{
+		Id:        "1",
+		Name:      "Alice",
+		Email:     "a@example.com",
+		Role:      "admin",
+		Status:    1,
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Unix(),
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatCSV,
+			want:   "id,name,email,role,status,created_at\n1,Alice,a@example.com,admin,1,2026-01-02T03:04:05Z\n",
+		},
+		{
+			format: FormatNDJSON,
+			want:   `{"id":"1","name":"Alice","email":"a@example.com","role":"admin","status":1,"created_at":1767323045}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, tt.format)
+		if err != nil {
+			t.Fatalf("NewWriter(%s) error = %v", tt.format, err)
+		}
+
+		if err = w.Write(user); err != nil {
+			t.Fatalf("Write(%s) error = %v", tt.format, err)
+		}
+
+		if err = w.Flush(); err != nil {
+			t.Fatalf("Flush(%s) error = %v", tt.format, err)
+		}
+
+		if buf.String() != tt.want {
+			t.Errorf("Write(%s) = %q, want %q", tt.format, buf.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package casbin
+
+import (
+	"github.com/casbin/casbin/v2/persist/cache"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/metrics"
+)
+
+var _ cache.Cache = (*metricsCache)(nil)
+
+// metricsCache is a decision cache counting the cache hits and misses.
+type metricsCache struct {
+	cache.Cache
+}
+
+// newMetricsCache creates a new decision cache.
+func newMetricsCache() cache.Cache {
+	c := cache.DefaultCache(make(map[string]bool))
+	return &metricsCache{
+		Cache: &c,
+	}
+}
+
+// Get returns the cached decision of the key.
+func (c *metricsCache) Get(key string) (bool, error) {
+	res, err := c.Cache.Get(key)
+	if err == cache.ErrNoSuchKey {
+		metrics.CasbinCacheTotal.WithLabelValues("miss").Inc()
+		return res, err
+	}
+
+	metrics.CasbinCacheTotal.WithLabelValues("hit").Inc()
+	return res, err
+}
@@ -1,15 +1,22 @@
 package casbin
 
 import (
+	"context"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/casbin/casbin/v2/model"
 	mongodbadapter "github.com/casbin/mongodb-adapter/v3"
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/metrics"
 	"github.com/xdorro/golang-grpc-base-project/pkg/repo"
+	"github.com/xdorro/golang-grpc-base-project/pkg/tracing"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
 )
 
 var _ ICasbin = (*Casbin)(nil)
@@ -17,11 +24,13 @@ var _ ICasbin = (*Casbin)(nil)
 // ICasbin is the interface that must be implemented by a casbin.
 type ICasbin interface {
 	Enforcer() *casbin.CachedEnforcer
+	Enforce(ctx context.Context, rvals ...any) (bool, error)
 }
 
 // Option casbin option.
 type Option struct {
-	Repo repo.IRepo
+	Config *config.Config
+	Repo   repo.IRepo
 }
 
 // Casbin is a casbin struct.
@@ -40,33 +49,40 @@ type Casbin struct {
 func NewCasbin(opt *Option) ICasbin {
 	c := &Casbin{
 		repo:        opt.Repo,
-		dbName:      viper.GetString("database.name"),
-		casbinModel: viper.GetString("casbin.model"),
-		casbinName:  viper.GetString("casbin.name"),
+		dbName:      opt.Config.Database.Name,
+		casbinModel: opt.Config.Casbin.Model,
+		casbinName:  opt.Config.Casbin.Name,
 	}
 
 	log.Info().Msg("Connecting to Casbin")
 
-	config := &mongodbadapter.AdapterConfig{
+	adapterConfig := &mongodbadapter.AdapterConfig{
 		DatabaseName:   c.dbName,
 		CollectionName: c.casbinName,
 	}
-	adapter, err := mongodbadapter.NewAdapterByDB(c.repo.Client(), config)
+
+	m, err := model.NewModelFromString(c.casbinModel)
 	if err != nil {
-		log.Panic().Err(err).Msg("Failed to create mongodb adapter")
+		log.Panic().Err(err).Msg("Failed to load casbin model")
 	}
 
-	m, _ := model.NewModelFromString(c.casbinModel)
-
-	enforcer, err := casbin.NewCachedEnforcer(m, adapter)
+	// the adapter creates its indexes and the enforcer loads the policy from DB
+	var enforcer *casbin.CachedEnforcer
+	err = utils.Retry("Loading casbin policy", &opt.Config.Database.ConnectBackoff, func() error {
+		adapter, err := mongodbadapter.NewAdapterByDB(c.repo.Client(), adapterConfig)
+		if err != nil {
+			return err
+		}
+
+		enforcer, err = casbin.NewCachedEnforcer(m, adapter)
+		return err
+	})
 	if err != nil {
 		log.Panic().Err(err).Msg("Failed to create casbin enforcer")
 	}
 
-	// Load the policy from DB.
-	if err = enforcer.LoadPolicy(); err != nil {
-		log.Panic().Err(err).Msg("Failed to load policy")
-	}
+	// Count the decision cache hits and misses.
+	enforcer.SetCache(newMetricsCache())
 
 	// Add enforcer to Casbin.
 	c.setClient(enforcer)
@@ -87,3 +103,22 @@ func (c *Casbin) setClient(enforcer *casbin.CachedEnforcer) {
 func (c *Casbin) Enforcer() *casbin.CachedEnforcer {
 	return c.enforcer
 }
+
+// Enforce decides whether the request is allowed and records the decision latency.
+func (c *Casbin) Enforce(ctx context.Context, rvals ...any) (bool, error) {
+	_, span := tracing.Start(ctx, "casbin.Enforce")
+	defer span.End()
+
+	start := time.Now()
+	allowed, err := c.enforcer.Enforce(rvals...)
+
+	result := strconv.FormatBool(allowed)
+	if err != nil {
+		span.RecordError(err)
+		result = metrics.StatusError
+	}
+	span.SetAttributes(attribute.Bool("casbin.allowed", allowed))
+	metrics.CasbinEnforceDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+
+	return allowed, err
+}
@@ -0,0 +1,41 @@
+package casbin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// IncludeDeleted reports whether a list includes the soft deleted records. They are listed when the
+// request sets the x-include-deleted header and the role of the verified caller is granted the
+// procedure restoring them, an admin only procedure, otherwise the request is denied.
+func IncludeDeleted(ctx context.Context, c ICasbin, header http.Header, procedure string) (bool, error) {
+	val := header.Get(utils.HeaderIncludeDeleted)
+	if val == "" {
+		return false, nil
+	}
+
+	include, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, errs.InvalidArgument(utils.HeaderIncludeDeleted, "must be a boolean")
+	}
+
+	if !include {
+		return false, nil
+	}
+
+	claims := session.Claims(ctx)
+	if claims == nil || len(claims.Audience) == 0 {
+		return false, errs.PermissionDenied(procedure)
+	}
+
+	if allowed, _ := c.Enforce(ctx, claims.Audience[0], procedure); !allowed {
+		return false, errs.PermissionDenied(procedure)
+	}
+
+	return true, nil
+}
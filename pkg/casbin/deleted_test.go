@@ -0,0 +1,69 @@
+package casbin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/casbin/casbin/v2"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/session"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+const restoreUser = "/retention.v1.RetentionService/RestoreUser"
+
+// roleCasbin grants the procedures to the roles.
+type roleCasbin map[string]string
+
+func (c roleCasbin) Enforcer() *casbin.CachedEnforcer { return nil }
+
+func (c roleCasbin) Enforce(_ context.Context, rvals ...any) (bool, error) {
+	return c[rvals[0].(string)] == rvals[1], nil
+}
+
+func TestIncludeDeleted(t *testing.T) {
+	c := roleCasbin{"admin": restoreUser}
+
+	tests := []struct {
+		name   string
+		header string
+		role   string
+		want   bool
+		code   connect.Code
+	}{
+		{name: "no header", role: "user"},
+		{name: "header false", header: "false", role: "user"},
+		{name: "granted role", header: "true", role: "admin", want: true},
+		{name: "other role", header: "true", role: "user", code: connect.CodePermissionDenied},
+		{name: "no verified caller", header: "1", code: connect.CodePermissionDenied},
+		{name: "invalid header", header: "all", role: "admin", code: connect.CodeInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.role != "" {
+				ctx = session.WithClaims(ctx, &jwt.RegisteredClaims{Audience: jwt.ClaimStrings{tt.role}})
+			}
+
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set(utils.HeaderIncludeDeleted, tt.header)
+			}
+
+			got, err := IncludeDeleted(ctx, c, header, restoreUser)
+			if got != tt.want {
+				t.Errorf("IncludeDeleted() = %v, want %v", got, tt.want)
+			}
+
+			if tt.code != 0 && connect.CodeOf(err) != tt.code {
+				t.Errorf("IncludeDeleted() error = %v, want %v", err, tt.code)
+			} else if tt.code == 0 && err != nil {
+				t.Errorf("IncludeDeleted() error = %v", err)
+			}
+		})
+	}
+}
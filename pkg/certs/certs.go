@@ -0,0 +1,217 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+const (
+	// ClientAuthNone does not request a client certificate.
+	ClientAuthNone = "none"
+	// ClientAuthRequest verifies the client certificate when one is given.
+	ClientAuthRequest = "request"
+	// ClientAuthRequire requires a verified client certificate.
+	ClientAuthRequire = "require"
+)
+
+var _ IManager = (*Manager)(nil)
+
+// IManager is the interface that must be implemented by a certificate manager.
+type IManager interface {
+	// TLSConfig returns the server TLS config, nil when TLS is disabled.
+	TLSConfig() *tls.Config
+	Close() error
+}
+
+// Manager loads the server certificate and the client CA, and reloads them when the files change.
+type Manager struct {
+	enabled    bool
+	certFile   string
+	keyFile    string
+	caFile     string
+	clientAuth tls.ClientAuthType
+	minVersion uint16
+	interval   time.Duration
+
+	current atomic.Pointer[tls.Config]
+	modTime time.Time
+
+	once sync.Once
+	stop chan struct{}
+}
+
+// NewManager creates the certificate manager, the dev CA certificates are generated when enabled.
+func NewManager(cfg *config.Config) IManager {
+	m := &Manager{
+		enabled:    cfg.TLS.Enabled,
+		certFile:   cfg.TLS.CertFile,
+		keyFile:    cfg.TLS.KeyFile,
+		caFile:     cfg.TLS.ClientCAFile,
+		clientAuth: clientAuthType(cfg.TLS.ClientAuth),
+		minVersion: tls.VersionTLS12,
+		interval:   cfg.TLS.ReloadInterval,
+		stop:       make(chan struct{}),
+	}
+
+	if !m.enabled {
+		return m
+	}
+
+	if cfg.TLS.MinVersion == "1.3" {
+		m.minVersion = tls.VersionTLS13
+	}
+
+	if cfg.TLS.DevCA {
+		if err := newDevCA(cfg.TLS.DevDir, cfg.TLS.DevHosts); err != nil {
+			log.Panic().Err(err).Msg("Failed to create the dev CA")
+		}
+
+		if m.certFile == "" {
+			m.certFile = filepath.Join(cfg.TLS.DevDir, devServerCert)
+			m.keyFile = filepath.Join(cfg.TLS.DevDir, devServerKey)
+		}
+
+		if m.caFile == "" {
+			m.caFile = filepath.Join(cfg.TLS.DevDir, devCACert)
+		}
+	}
+
+	if err := m.load(); err != nil {
+		log.Panic().Err(err).Msg("Failed to load the TLS certificates")
+	}
+
+	log.Info().
+		Str("cert_file", m.certFile).
+		Str("client_auth", cfg.TLS.ClientAuth).
+		Msg("Loaded TLS certificates")
+
+	go m.watch()
+
+	return m
+}
+
+// TLSConfig returns the server TLS config, each handshake uses the last loaded certificates.
+func (m *Manager) TLSConfig() *tls.Config {
+	if !m.enabled {
+		return nil
+	}
+
+	return &tls.Config{
+		MinVersion: m.minVersion,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return m.current.Load(), nil
+		},
+	}
+}
+
+// Close stops the reload of the certificates.
+func (m *Manager) Close() error {
+	m.once.Do(func() {
+		close(m.stop)
+	})
+
+	return nil
+}
+
+// watch reloads the certificates when one of the files changes.
+func (m *Manager) watch() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !m.changed() {
+				continue
+			}
+
+			if err := m.load(); err != nil {
+				log.Err(err).Msg("Failed to reload the TLS certificates, keeping the previous ones")
+				continue
+			}
+
+			log.Info().Str("cert_file", m.certFile).Msg("Reloaded TLS certificates")
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// changed returns true if one of the files was modified since the last load.
+func (m *Manager) changed() bool {
+	return lastModTime(m.certFile, m.keyFile, m.caFile).After(m.modTime)
+}
+
+// load reads the certificate, the key and the client CA, and replaces the current config.
+func (m *Manager) load() error {
+	modTime := lastModTime(m.certFile, m.keyFile, m.caFile)
+
+	cert, err := tls.LoadX509KeyPair(m.certFile, m.keyFile)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   m.minVersion,
+		NextProtos:   []string{"h2", "http/1.1"},
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   m.clientAuth,
+	}
+
+	if m.clientAuth != tls.NoClientCert {
+		pem, err := os.ReadFile(m.caFile)
+		if err != nil {
+			return err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("no client CA certificate found in " + m.caFile)
+		}
+		tlsConfig.ClientCAs = pool
+	}
+
+	m.current.Store(tlsConfig)
+	m.modTime = modTime
+
+	return nil
+}
+
+// lastModTime returns the last modification time of the files.
+func lastModTime(files ...string) time.Time {
+	var last time.Time
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+
+	return last
+}
+
+// clientAuthType returns the client auth type of the config.
+func clientAuthType(clientAuth string) tls.ClientAuthType {
+	switch clientAuth {
+	case ClientAuthRequest:
+		return tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
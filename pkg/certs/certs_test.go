@@ -0,0 +1,189 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+// newTestServer serves the identity of the client certificates with the certificates of the dev CA.
+func newTestServer(t *testing.T, clientAuth string) (*httptest.Server, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	m := NewManager(&config.Config{TLS: config.TLS{
+		Enabled:        true,
+		ClientAuth:     clientAuth,
+		ReloadInterval: time.Hour,
+		DevCA:          true,
+		DevDir:         dir,
+		DevHosts:       []string{"127.0.0.1"},
+	}})
+	t.Cleanup(func() { _ = m.Close() })
+
+	server := httptest.NewUnstartedServer(IdentityHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, Identity(r.Context()))
+	})))
+	server.TLS = m.TLSConfig()
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server, dir
+}
+
+// newTestClient returns a client trusting the dev CA, with the dev client certificate if asked.
+func newTestClient(t *testing.T, dir string, withCert bool) *http.Client {
+	t.Helper()
+
+	caPEM, err := os.ReadFile(filepath.Join(dir, devCACert))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caPEM)
+	tlsConfig := &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	if withCert {
+		cert, err := tls.LoadX509KeyPair(filepath.Join(dir, devClientCert), filepath.Join(dir, devClientKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+}
+
+func get(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func TestManagerRequire(t *testing.T) {
+	server, dir := newTestServer(t, ClientAuthRequire)
+
+	identity, err := get(newTestClient(t, dir, true), server.URL)
+	if err != nil {
+		t.Fatalf("GET with the client certificate error = %v", err)
+	}
+
+	if identity != DevClientName {
+		t.Errorf("identity = %q, want %q", identity, DevClientName)
+	}
+
+	if _, err = get(newTestClient(t, dir, false), server.URL); err == nil {
+		t.Error("GET without a client certificate error = nil, want a handshake error")
+	}
+}
+
+func TestManagerRequest(t *testing.T) {
+	server, dir := newTestServer(t, ClientAuthRequest)
+
+	identity, err := get(newTestClient(t, dir, false), server.URL)
+	if err != nil {
+		t.Fatalf("GET without a client certificate error = %v", err)
+	}
+
+	if identity != "" {
+		t.Errorf("identity = %q, want none without a client certificate", identity)
+	}
+}
+
+func TestManagerDisabled(t *testing.T) {
+	m := NewManager(&config.Config{})
+	if m.TLSConfig() != nil {
+		t.Error("TLSConfig() != nil, want nil when TLS is disabled")
+	}
+}
+
+func TestNewDevCAKeepsFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := newDevCA(dir, nil); err != nil {
+		t.Fatalf("newDevCA() error = %v", err)
+	}
+
+	before, err := os.ReadFile(filepath.Join(dir, devServerCert))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = newDevCA(dir, nil); err != nil {
+		t.Fatalf("newDevCA() again error = %v", err)
+	}
+
+	after, err := os.ReadFile(filepath.Join(dir, devServerCert))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(before) != string(after) {
+		t.Error("newDevCA() replaced the existing server certificate")
+	}
+}
+
+func TestIdentityOf(t *testing.T) {
+	spiffe, _ := url.Parse("spiffe://example.org/ns/default/sa/worker")
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want string
+	}{
+		{
+			name: "uri san",
+			cert: &x509.Certificate{URIs: []*url.URL{spiffe}, DNSNames: []string{"worker.example.org"}},
+			want: spiffe.String(),
+		},
+		{
+			name: "dns san",
+			cert: &x509.Certificate{DNSNames: []string{"worker.example.org"}, Subject: pkix.Name{CommonName: "worker"}},
+			want: "worker.example.org",
+		},
+		{
+			name: "common name",
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "worker"}},
+			want: "worker",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := identityOf(tt.cert); got != tt.want {
+			t.Errorf("identityOf() %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClientAuthType(t *testing.T) {
+	tests := map[string]tls.ClientAuthType{
+		ClientAuthNone:    tls.NoClientCert,
+		ClientAuthRequest: tls.VerifyClientCertIfGiven,
+		ClientAuthRequire: tls.RequireAndVerifyClientCert,
+		"":                tls.NoClientCert,
+	}
+
+	for clientAuth, want := range tests {
+		if got := clientAuthType(clientAuth); got != want {
+			t.Errorf("clientAuthType(%q) = %v, want %v", clientAuth, got, want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	devCACert     = "ca.pem"
+	devCAKey      = "ca-key.pem"
+	devServerCert = "server.pem"
+	devServerKey  = "server-key.pem"
+	devClientCert = "client.pem"
+	devClientKey  = "client-key.pem"
+
+	// DevClientName is the CN of the generated dev client certificate.
+	DevClientName = "dev-client"
+
+	devCAValidity   = 10 * 365 * 24 * time.Hour
+	devCertValidity = 365 * 24 * time.Hour
+)
+
+// newDevCA creates a local CA in the directory, then issues the server certificate for the hosts
+// and a client certificate, the existing files are kept.
+func newDevCA(dir string, hosts []string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	caCertFile, caKeyFile := filepath.Join(dir, devCACert), filepath.Join(dir, devCAKey)
+	if !exists(caCertFile) || !exists(caKeyFile) {
+		template := &x509.Certificate{
+			Subject:               pkix.Name{CommonName: "golang-grpc-base-project dev CA"},
+			NotAfter:              time.Now().Add(devCAValidity),
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+		if err := issue(caCertFile, caKeyFile, template, nil); err != nil {
+			return err
+		}
+
+		log.Warn().Str("file", caCertFile).Msg("Created the dev CA, trust it on the development machines only")
+	}
+
+	ca, err := tls.LoadX509KeyPair(caCertFile, caKeyFile)
+	if err != nil {
+		return err
+	}
+
+	if ca.Leaf, err = x509.ParseCertificate(ca.Certificate[0]); err != nil {
+		return err
+	}
+
+	server := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: hostsOrDefault(hosts)[0]},
+		NotAfter:    time.Now().Add(devCertValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hostsOrDefault(hosts) {
+		if ip := net.ParseIP(host); ip != nil {
+			server.IPAddresses = append(server.IPAddresses, ip)
+		} else {
+			server.DNSNames = append(server.DNSNames, host)
+		}
+	}
+
+	client := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: DevClientName},
+		NotAfter:    time.Now().Add(devCertValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	for _, c := range []struct {
+		cert, key string
+		template  *x509.Certificate
+	}{
+		{devServerCert, devServerKey, server},
+		{devClientCert, devClientKey, client},
+	} {
+		certFile, keyFile := filepath.Join(dir, c.cert), filepath.Join(dir, c.key)
+		if exists(certFile) && exists(keyFile) {
+			continue
+		}
+
+		if err = issue(certFile, keyFile, c.template, &ca); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// issue creates a key and its certificate signed by the CA, self-signed without CA.
+func issue(certFile, keyFile string, template *x509.Certificate, ca *tls.Certificate) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+
+	parent, signer := template, crypto.Signer(key)
+	if ca != nil {
+		var ok bool
+		if signer, ok = ca.PrivateKey.(crypto.Signer); !ok {
+			return errors.New("the dev CA key cannot sign")
+		}
+		parent = ca.Leaf
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	if err != nil {
+		return err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		return err
+	}
+
+	return os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)
+}
+
+// hostsOrDefault returns the hosts, localhost when there is none.
+func hostsOrDefault(hosts []string) []string {
+	if len(hosts) == 0 {
+		return []string{"localhost"}
+	}
+
+	return hosts
+}
+
+// exists returns true if the file exists.
+func exists(file string) bool {
+	_, err := os.Stat(file)
+	return err == nil
+}
@@ -0,0 +1,48 @@
+package certs
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+)
+
+// identityKey is the context key of the client certificate identity.
+type identityKey struct{}
+
+// WithIdentity returns a context carrying the client certificate identity.
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// Identity returns the verified client certificate identity of the context, empty if none.
+func Identity(ctx context.Context) string {
+	identity, _ := ctx.Value(identityKey{}).(string)
+	return identity
+}
+
+// IdentityHandler stores the identity of the verified client certificate in the request context.
+// The certificates which are not verified against the client CA are ignored.
+func IdentityHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			if identity := identityOf(r.TLS.VerifiedChains[0][0]); identity != "" {
+				r = r.WithContext(WithIdentity(r.Context(), identity))
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// identityOf returns the identity of the certificate: its first URI SAN (e.g. a SPIFFE ID),
+// its first DNS SAN or its common name.
+func identityOf(cert *x509.Certificate) string {
+	switch {
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	default:
+		return cert.Subject.CommonName
+	}
+}
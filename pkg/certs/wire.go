@@ -0,0 +1,10 @@
+package certs
+
+import (
+	"github.com/google/wire"
+)
+
+// ProviderCertsSet is Certs providers.
+var ProviderCertsSet = wire.NewSet(
+	NewManager,
+)
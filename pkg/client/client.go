@@ -0,0 +1,205 @@
+// Package client is the Go client of the services, wrapping the Connect clients with
+// the token refresh, the retries and the request ids.
+//
+//	c := client.NewClient(&client.Option{BaseURL: "http://localhost:8088"})
+//	if err := c.Login(ctx, "admin@gmail.com", "123456"); err != nil {
+//		return err
+//	}
+//	users, err := c.User().FindAllUsers(ctx, connect.NewRequest(&userv1.FindAllUsersRequest{Page: 1}))
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/xdorro/proto-base-project/proto-gen-go/auth/v1/authv1connect"
+	"github.com/xdorro/proto-base-project/proto-gen-go/permission/v1/permissionv1connect"
+	"github.com/xdorro/proto-base-project/proto-gen-go/role/v1/rolev1connect"
+	"github.com/xdorro/proto-base-project/proto-gen-go/user/v1/userv1connect"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1/auditv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/privacy/v1/privacyv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/retention/v1/retentionv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1/userbulkv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userstate/v1/userstatev1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// Version is the version of the client, bumped with the breaking changes of the services.
+const Version = "v1.0.0"
+
+const (
+	// ProtocolConnect is the Connect protocol, served over HTTP/1.1 and HTTP/2.
+	ProtocolConnect = "connect"
+	// ProtocolGRPC is the gRPC protocol, the HTTP client must speak HTTP/2.
+	ProtocolGRPC = "grpc"
+	// ProtocolGRPCWeb is the gRPC-Web protocol.
+	ProtocolGRPCWeb = "grpcweb"
+)
+
+var _ IClient = (*Client)(nil)
+
+// IClient is the interface that must be implemented by a client.
+type IClient interface {
+	Auth() authv1connect.AuthServiceClient
+	User() userv1connect.UserServiceClient
+	Role() rolev1connect.RoleServiceClient
+	Permission() permissionv1connect.PermissionServiceClient
+	Audit() auditv1connect.AuditServiceClient
+	UserBulk() userbulkv1connect.UserBulkServiceClient
+	UserState() userstatev1connect.UserStateServiceClient
+	Retention() retentionv1connect.RetentionServiceClient
+	Privacy() privacyv1connect.PrivacyServiceClient
+
+	// Login authenticates the next calls, the access token is refreshed before it expires.
+	Login(ctx context.Context, email, password string) error
+	// SetTokens authenticates the next calls with tokens obtained elsewhere.
+	SetTokens(accessToken, refreshToken string, expire time.Time)
+	// Logout revokes the refresh token and forgets the tokens.
+	Logout(ctx context.Context) error
+}
+
+// Option is a client option struct.
+type Option struct {
+	// BaseURL is the URL of the server, e.g. http://localhost:8088.
+	BaseURL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient connect.HTTPClient
+	// Protocol is connect, grpc or grpcweb, connect by default.
+	Protocol string
+	// Retry is the retry policy of the unavailable and throttled calls, 3 attempts by default.
+	Retry *utils.Backoff
+	// Interceptors are called after the client interceptors.
+	Interceptors []connect.Interceptor
+}
+
+// Client is a client struct.
+type Client struct {
+	tokens *tokenSource
+
+	auth       authv1connect.AuthServiceClient
+	user       userv1connect.UserServiceClient
+	role       rolev1connect.RoleServiceClient
+	permission permissionv1connect.PermissionServiceClient
+	audit      auditv1connect.AuditServiceClient
+	userBulk   userbulkv1connect.UserBulkServiceClient
+	userState  userstatev1connect.UserStateServiceClient
+	retention  retentionv1connect.RetentionServiceClient
+	privacy    privacyv1connect.PrivacyServiceClient
+}
+
+// NewClient returns a new client.
+func NewClient(opt *Option) IClient {
+	httpClient := opt.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	retry := opt.Retry
+	if retry == nil {
+		retry = &utils.Backoff{
+			Attempts: 3,
+			Initial:  200 * time.Millisecond,
+			Max:      2 * time.Second,
+		}
+	}
+
+	baseURL := strings.TrimRight(opt.BaseURL, "/")
+	c := &Client{
+		tokens: &tokenSource{},
+	}
+
+	// the refresh calls go through the same interceptors, the token source skips them
+	interceptors := append([]connect.Interceptor{
+		requestIDInterceptor(),
+		retryInterceptor(retry),
+		c.tokens.interceptor(),
+		&streamInterceptor{tokens: c.tokens},
+	}, opt.Interceptors...)
+
+	options := []connect.ClientOption{
+		connect.WithInterceptors(interceptors...),
+	}
+	switch opt.Protocol {
+	case ProtocolGRPC:
+		options = append(options, connect.WithGRPC())
+	case ProtocolGRPCWeb:
+		options = append(options, connect.WithGRPCWeb())
+	}
+
+	c.auth = authv1connect.NewAuthServiceClient(httpClient, baseURL, options...)
+	c.user = userv1connect.NewUserServiceClient(httpClient, baseURL, options...)
+	c.role = rolev1connect.NewRoleServiceClient(httpClient, baseURL, options...)
+	c.permission = permissionv1connect.NewPermissionServiceClient(httpClient, baseURL, options...)
+	c.audit = auditv1connect.NewAuditServiceClient(httpClient, baseURL, options...)
+	c.userBulk = userbulkv1connect.NewUserBulkServiceClient(httpClient, baseURL, options...)
+	c.userState = userstatev1connect.NewUserStateServiceClient(httpClient, baseURL, options...)
+	c.retention = retentionv1connect.NewRetentionServiceClient(httpClient, baseURL, options...)
+	c.privacy = privacyv1connect.NewPrivacyServiceClient(httpClient, baseURL, options...)
+	c.tokens.auth = c.auth
+
+	return c
+}
+
+// Auth returns the auth service client.
+func (c *Client) Auth() authv1connect.AuthServiceClient {
+	return c.auth
+}
+
+// User returns the user service client.
+func (c *Client) User() userv1connect.UserServiceClient {
+	return c.user
+}
+
+// Role returns the role service client.
+func (c *Client) Role() rolev1connect.RoleServiceClient {
+	return c.role
+}
+
+// Permission returns the permission service client.
+func (c *Client) Permission() permissionv1connect.PermissionServiceClient {
+	return c.permission
+}
+
+// Audit returns the audit service client.
+func (c *Client) Audit() auditv1connect.AuditServiceClient {
+	return c.audit
+}
+
+// UserBulk returns the user bulk service client.
+func (c *Client) UserBulk() userbulkv1connect.UserBulkServiceClient {
+	return c.userBulk
+}
+
+// UserState returns the user state service client.
+func (c *Client) UserState() userstatev1connect.UserStateServiceClient {
+	return c.userState
+}
+
+// Retention returns the retention service client.
+func (c *Client) Retention() retentionv1connect.RetentionServiceClient {
+	return c.retention
+}
+
+// Privacy returns the privacy service client.
+func (c *Client) Privacy() privacyv1connect.PrivacyServiceClient {
+	return c.privacy
+}
+
+// Login authenticates the next calls.
+func (c *Client) Login(ctx context.Context, email, password string) error {
+	return c.tokens.login(ctx, email, password)
+}
+
+// SetTokens authenticates the next calls with the tokens.
+func (c *Client) SetTokens(accessToken, refreshToken string, expire time.Time) {
+	c.tokens.set(accessToken, refreshToken, expire)
+}
+
+// Logout revokes the refresh token and forgets the tokens.
+func (c *Client) Logout(ctx context.Context) error {
+	return c.tokens.logout(ctx)
+}
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	authv1 "github.com/xdorro/proto-base-project/proto-gen-go/auth/v1"
+	"github.com/xdorro/proto-base-project/proto-gen-go/auth/v1/authv1connect"
+	userv1 "github.com/xdorro/proto-base-project/proto-gen-go/user/v1"
+	"github.com/xdorro/proto-base-project/proto-gen-go/user/v1/userv1connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// authHandler issues the access tokens a1, a2... and records the revoked refresh tokens.
+type authHandler struct {
+	authv1connect.UnimplementedAuthServiceHandler
+
+	mu        sync.Mutex
+	issued    int
+	expire    time.Duration
+	revoked   []string
+	loginAuth string
+}
+
+func (h *authHandler) issue() *connect.Response[authv1.TokenResponse] {
+	h.issued++
+
+	return connect.NewResponse(&authv1.TokenResponse{
+		AccessToken:  "a" + strconv.Itoa(h.issued),
+		RefreshToken: "r" + strconv.Itoa(h.issued),
+		TokenExpire:  time.Now().Add(h.expire).Unix(),
+	})
+}
+
+func (h *authHandler) Login(_ context.Context, req *connect.Request[authv1.LoginRequest]) (
+	*connect.Response[authv1.TokenResponse], error,
+) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.loginAuth = req.Header().Get("Authorization")
+	return h.issue(), nil
+}
+
+func (h *authHandler) RefreshToken(_ context.Context, req *connect.Request[authv1.TokenRequest]) (
+	*connect.Response[authv1.TokenResponse], error,
+) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if req.Msg.GetToken() != "r"+strconv.Itoa(h.issued) {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid refresh token"))
+	}
+
+	return h.issue(), nil
+}
+
+func (h *authHandler) RevokeToken(_ context.Context, req *connect.Request[authv1.TokenRequest]) (
+	*connect.Response[authv1.CommonResponse], error,
+) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.revoked = append(h.revoked, req.Msg.GetToken())
+	return connect.NewResponse(&authv1.CommonResponse{}), nil
+}
+
+// userHandler accepts the access token a2 only, after failing the first calls as unavailable.
+type userHandler struct {
+	userv1connect.UnimplementedUserServiceHandler
+
+	mu          sync.Mutex
+	unavailable int
+	calls       int
+	auth        []string
+	requestIDs  []string
+}
+
+func (h *userHandler) FindAllUsers(_ context.Context, req *connect.Request[userv1.FindAllUsersRequest]) (
+	*connect.Response[userv1.FindAllUsersResponse], error,
+) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.calls++
+	h.auth = append(h.auth, req.Header().Get("Authorization"))
+	h.requestIDs = append(h.requestIDs, req.Header().Get(utils.HeaderRequestID))
+
+	if h.calls <= h.unavailable {
+		err := connect.NewError(connect.CodeUnavailable, errors.New("unavailable"))
+		if detail, detailErr := connect.NewErrorDetail(&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(time.Millisecond),
+		}); detailErr == nil {
+			err.AddDetail(detail)
+		}
+
+		return nil, err
+	}
+
+	if req.Header().Get("Authorization") != "Bearer a2" {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid token"))
+	}
+
+	return connect.NewResponse(&userv1.FindAllUsersResponse{}), nil
+}
+
+func newTestClient(t *testing.T, auth *authHandler, user *userHandler) IClient {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle(authv1connect.NewAuthServiceHandler(auth))
+	mux.Handle(userv1connect.NewUserServiceHandler(user))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return NewClient(&Option{
+		BaseURL:    server.URL + "/",
+		HTTPClient: server.Client(),
+		Retry:      &utils.Backoff{Attempts: 3, Initial: time.Millisecond, Max: time.Millisecond},
+	})
+}
+
+func TestClientRefreshOnUnauthenticated(t *testing.T) {
+	auth := &authHandler{expire: time.Hour}
+	user := &userHandler{}
+	c := newTestClient(t, auth, user)
+
+	if err := c.Login(context.Background(), "admin@example.com", "secret"); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if auth.loginAuth != "" {
+		t.Errorf("Login() Authorization = %q, want none", auth.loginAuth)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	if _, err := c.User().FindAllUsers(ctx, connect.NewRequest(&userv1.FindAllUsersRequest{})); err != nil {
+		t.Fatalf("FindAllUsers() error = %v", err)
+	}
+
+	if len(user.auth) != 2 || user.auth[0] != "Bearer a1" || user.auth[1] != "Bearer a2" {
+		t.Errorf("FindAllUsers() tokens = %v, want a1 then the refreshed a2", user.auth)
+	}
+
+	for _, requestID := range user.requestIDs {
+		if requestID != "req-123" {
+			t.Errorf("FindAllUsers() request ids = %v, want req-123", user.requestIDs)
+			break
+		}
+	}
+
+	if err := c.Logout(context.Background()); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	if len(auth.revoked) != 1 || auth.revoked[0] != "r2" {
+		t.Errorf("Logout() revoked = %v, want r2", auth.revoked)
+	}
+}
+
+func TestClientRefreshBeforeExpiry(t *testing.T) {
+	auth := &authHandler{expire: time.Hour}
+	user := &userHandler{}
+	c := newTestClient(t, auth, user)
+
+	// a1 expires within the leeway, a2 is issued before the call
+	auth.issued = 1
+	c.SetTokens("a1", "r1", time.Now().Add(refreshLeeway/2))
+
+	if _, err := c.User().FindAllUsers(context.Background(), connect.NewRequest(&userv1.FindAllUsersRequest{})); err != nil {
+		t.Fatalf("FindAllUsers() error = %v", err)
+	}
+
+	if len(user.auth) != 1 || user.auth[0] != "Bearer a2" {
+		t.Errorf("FindAllUsers() tokens = %v, want the refreshed a2 only", user.auth)
+	}
+}
+
+func TestClientNoRefreshToken(t *testing.T) {
+	c := newTestClient(t, &authHandler{}, &userHandler{})
+	c.SetTokens("a1", "", time.Now().Add(-time.Minute))
+
+	_, err := c.User().FindAllUsers(context.Background(), connect.NewRequest(&userv1.FindAllUsersRequest{}))
+	if !errors.Is(err, ErrNoRefreshToken) {
+		t.Errorf("FindAllUsers() error = %v, want %v", err, ErrNoRefreshToken)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		unavailable int
+		code        connect.Code
+		calls       int
+	}{
+		{name: "retried until success", unavailable: 2, calls: 3},
+		{name: "attempts exhausted", unavailable: 5, code: connect.CodeUnavailable, calls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &userHandler{unavailable: tt.unavailable}
+			c := newTestClient(t, &authHandler{}, user)
+			c.SetTokens("a2", "", time.Time{})
+
+			_, err := c.User().FindAllUsers(context.Background(), connect.NewRequest(&userv1.FindAllUsersRequest{}))
+			if (tt.code == 0 && err != nil) || (tt.code != 0 && connect.CodeOf(err) != tt.code) {
+				t.Errorf("FindAllUsers() error = %v, want code %v", err, tt.code)
+			}
+
+			if user.calls != tt.calls {
+				t.Errorf("FindAllUsers() calls = %d, want %d", user.calls, tt.calls)
+			}
+
+			// the retries keep the generated request id
+			for _, requestID := range user.requestIDs {
+				if requestID == "" || requestID != user.requestIDs[0] {
+					t.Errorf("FindAllUsers() request ids = %v, want one id", user.requestIDs)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// requestIDKey is the context key of the request id.
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose calls send the request id, e.g. to propagate
+// the request id of an incoming request.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// requestIDInterceptor sends the request id of the context, or a new one, kept across the retries.
+func requestIDInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+			if request.Header().Get(utils.HeaderRequestID) == "" {
+				requestID, _ := ctx.Value(requestIDKey{}).(string)
+				if requestID == "" {
+					requestID = uuid.NewString()
+				}
+
+				request.Header().Set(utils.HeaderRequestID, requestID)
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// retryInterceptor retries the unavailable and throttled calls, waiting for the retry delay
+// sent by the server or else an exponential backoff.
+func retryInterceptor(backoff *utils.Backoff) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+			delay := backoff.Initial
+			if delay <= 0 {
+				delay = 100 * time.Millisecond
+			}
+
+			for attempt := 1; ; attempt++ {
+				response, err := next(ctx, request)
+				if !retryable(err) || (backoff.Attempts > 0 && attempt >= backoff.Attempts) {
+					return response, err
+				}
+
+				wait := delay
+				if retry := retryDelay(err); retry > 0 {
+					wait = retry
+				}
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return response, err
+				case <-timer.C:
+				}
+
+				if delay *= 2; backoff.Max > 0 && delay > backoff.Max {
+					delay = backoff.Max
+				}
+			}
+		}
+	}
+}
+
+// retryable returns true if the call was rejected before being processed.
+func retryable(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns the retry delay of the error details, 0 if none.
+func retryDelay(err error) time.Duration {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return 0
+	}
+
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		if err != nil {
+			continue
+		}
+
+		if info, ok := value.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration()
+		}
+	}
+
+	return 0
+}
+
+// streamInterceptor sends the request id and the access token of the streaming calls, which the
+// unary interceptors do not wrap. A stream can not be replayed, so it is neither retried nor
+// refreshed once rejected.
+type streamInterceptor struct {
+	tokens *tokenSource
+}
+
+// WrapUnary passes the unary calls through.
+func (s *streamInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return next
+}
+
+// WrapStreamingClient sets the headers of the streaming calls.
+func (s *streamInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+
+		requestID, _ := ctx.Value(requestIDKey{}).(string)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		conn.RequestHeader().Set(utils.HeaderRequestID, requestID)
+
+		if unauthenticatedProcedures[spec.Procedure] {
+			return conn
+		}
+
+		// an error refreshing the token is returned by the server as unauthenticated
+		if token, err := s.tokens.token(ctx); err == nil && token != "" {
+			conn.RequestHeader().Set("Authorization", utils.TokenType+" "+token)
+		}
+
+		return conn
+	}
+}
+
+// WrapStreamingHandler passes the streaming handlers through.
+func (s *streamInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
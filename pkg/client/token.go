@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	authv1 "github.com/xdorro/proto-base-project/proto-gen-go/auth/v1"
+	"github.com/xdorro/proto-base-project/proto-gen-go/auth/v1/authv1connect"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1/userbulkv1connect"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+// refreshLeeway refreshes the access token this long before it expires.
+const refreshLeeway = 30 * time.Second
+
+// ErrNoRefreshToken is returned when the access token expired and no refresh token is known.
+var ErrNoRefreshToken = errors.New("client: no refresh token")
+
+// unauthenticatedProcedures are called without the access token.
+var unauthenticatedProcedures = map[string]bool{
+	"/" + authv1connect.AuthServiceName + "/Login":        true,
+	"/" + authv1connect.AuthServiceName + "/RefreshToken": true,
+	"/" + authv1connect.AuthServiceName + "/RevokeToken":  true,
+
+	"/" + userbulkv1connect.UserBulkServiceName + "/AcceptInvite": true,
+}
+
+// tokenSource holds the tokens and refreshes the access token.
+type tokenSource struct {
+	auth authv1connect.AuthServiceClient
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expire       time.Time
+}
+
+// interceptor authenticates the calls with the access token, refreshing it before it expires
+// and once when the server rejects it.
+func (t *tokenSource) interceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+			if unauthenticatedProcedures[request.Spec().Procedure] {
+				return next(ctx, request)
+			}
+
+			token, err := t.token(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if token == "" {
+				return next(ctx, request)
+			}
+
+			request.Header().Set("Authorization", utils.TokenType+" "+token)
+			response, err := next(ctx, request)
+			if connect.CodeOf(err) != connect.CodeUnauthenticated {
+				return response, err
+			}
+
+			// the access token may have been revoked or the clocks may be skewed
+			token, refreshErr := t.refresh(ctx, token)
+			if refreshErr != nil {
+				return response, err
+			}
+
+			request.Header().Set("Authorization", utils.TokenType+" "+token)
+			return next(ctx, request)
+		}
+	}
+}
+
+// token returns the access token, refreshed if it is about to expire.
+func (t *tokenSource) token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	token, expire := t.accessToken, t.expire
+	t.mu.Unlock()
+
+	if token == "" || expire.IsZero() || time.Until(expire) > refreshLeeway {
+		return token, nil
+	}
+
+	return t.refresh(ctx, token)
+}
+
+// refresh exchanges the refresh token for new tokens, unless the stale access token
+// was already replaced by a concurrent call.
+func (t *tokenSource) refresh(ctx context.Context, stale string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.accessToken != stale {
+		return t.accessToken, nil
+	}
+
+	if t.refreshToken == "" {
+		return "", ErrNoRefreshToken
+	}
+
+	res, err := t.auth.RefreshToken(ctx, connect.NewRequest(&authv1.TokenRequest{
+		Token: t.refreshToken,
+	}))
+	if err != nil {
+		return "", err
+	}
+
+	t.setLocked(res.Msg)
+	return t.accessToken, nil
+}
+
+// login exchanges the credentials for tokens.
+func (t *tokenSource) login(ctx context.Context, email, password string) error {
+	res, err := t.auth.Login(ctx, connect.NewRequest(&authv1.LoginRequest{
+		Email:    email,
+		Password: password,
+	}))
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.setLocked(res.Msg)
+	t.mu.Unlock()
+
+	return nil
+}
+
+// logout revokes the refresh token and forgets the tokens.
+func (t *tokenSource) logout(ctx context.Context) error {
+	t.mu.Lock()
+	refreshToken := t.refreshToken
+	t.accessToken, t.refreshToken, t.expire = "", "", time.Time{}
+	t.mu.Unlock()
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	_, err := t.auth.RevokeToken(ctx, connect.NewRequest(&authv1.TokenRequest{
+		Token: refreshToken,
+	}))
+
+	return err
+}
+
+// set replaces the tokens.
+func (t *tokenSource) set(accessToken, refreshToken string, expire time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.accessToken, t.refreshToken, t.expire = accessToken, refreshToken, expire
+}
+
+// setLocked replaces the tokens with the token response, the caller must hold the lock.
+func (t *tokenSource) setLocked(res *authv1.TokenResponse) {
+	t.accessToken = res.GetAccessToken()
+	t.refreshToken = res.GetRefreshToken()
+	t.expire = time.Time{}
+	if res.GetTokenExpire() > 0 {
+		t.expire = time.Unix(res.GetTokenExpire(), 0)
+	}
+}
@@ -0,0 +1,219 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain is the error domain of the ErrorInfo details.
+const Domain = "golang-grpc-base-project"
+
+const (
+	// ReasonNotFound is the reason of a missing resource.
+	ReasonNotFound = "NOT_FOUND"
+	// ReasonAlreadyExists is the reason of a duplicated resource.
+	ReasonAlreadyExists = "ALREADY_EXISTS"
+	// ReasonInvalidArgument is the reason of an invalid request.
+	ReasonInvalidArgument = "INVALID_ARGUMENT"
+	// ReasonFailedPrecondition is the reason of a request rejected by the current state.
+	ReasonFailedPrecondition = "FAILED_PRECONDITION"
+	// ReasonUserInactive is the reason of a user which is not active, e.g. suspended.
+	ReasonUserInactive = "USER_INACTIVE"
+	// ReasonUnauthenticated is the reason of a missing or invalid credential.
+	ReasonUnauthenticated = "UNAUTHENTICATED"
+	// ReasonPermissionDenied is the reason of a forbidden procedure.
+	ReasonPermissionDenied = "PERMISSION_DENIED"
+	// ReasonResourceExhausted is the reason of a throttled request.
+	ReasonResourceExhausted = "RESOURCE_EXHAUSTED"
+	// ReasonUnavailable is the reason of a dependency outage.
+	ReasonUnavailable = "UNAVAILABLE"
+	// ReasonInternal is the reason of an unexpected failure.
+	ReasonInternal = "INTERNAL"
+)
+
+// defaultRetryDelay is the retry delay suggested for unavailable errors.
+const defaultRetryDelay = 5 * time.Second
+
+// FieldViolation is a field level violation of a bad request.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// NotFound returns a NotFound error for the resource.
+func NotFound(resource string) *connect.Error {
+	err := connect.NewError(connect.CodeNotFound, fmt.Errorf("%s does not exists", resource))
+	addInfo(err, ReasonNotFound, map[string]string{"resource": resource})
+
+	return err
+}
+
+// AlreadyExists returns an AlreadyExists error for the resource field.
+func AlreadyExists(resource, field string) *connect.Error {
+	err := connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("%s already exists", field))
+	addInfo(err, ReasonAlreadyExists, map[string]string{"resource": resource, "field": field})
+
+	return err
+}
+
+// InvalidArgument returns an InvalidArgument error with a single field violation.
+func InvalidArgument(field, description string) *connect.Error {
+	return Validation(&FieldViolation{Field: field, Description: description})
+}
+
+// Validation returns an InvalidArgument error with the field violations.
+func Validation(violations ...*FieldViolation) *connect.Error {
+	fields := make([]string, 0, len(violations))
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range violations {
+		fields = append(fields, v.Field)
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	msg := "invalid argument"
+	if len(violations) == 1 {
+		msg = fmt.Sprintf("%s: %s", violations[0].Field, violations[0].Description)
+	}
+
+	err := connect.NewError(connect.CodeInvalidArgument, errors.New(msg))
+	addInfo(err, ReasonInvalidArgument, map[string]string{"fields": strings.Join(fields, ",")})
+	addDetail(err, badRequest)
+
+	return err
+}
+
+// FailedPrecondition returns a FailedPrecondition error.
+func FailedPrecondition(reason, msg string) *connect.Error {
+	err := connect.NewError(connect.CodeFailedPrecondition, errors.New(msg))
+	addInfo(err, reason, nil)
+
+	return err
+}
+
+// Unauthenticated returns an Unauthenticated error.
+func Unauthenticated(msg string) *connect.Error {
+	err := connect.NewError(connect.CodeUnauthenticated, errors.New(msg))
+	addInfo(err, ReasonUnauthenticated, nil)
+
+	return err
+}
+
+// PermissionDenied returns a PermissionDenied error.
+func PermissionDenied(procedure string) *connect.Error {
+	err := connect.NewError(connect.CodePermissionDenied, errors.New("permission denied"))
+	addInfo(err, ReasonPermissionDenied, map[string]string{"procedure": procedure})
+
+	return err
+}
+
+// ResourceExhausted returns a ResourceExhausted error with the retry delay.
+func ResourceExhausted(retry time.Duration) *connect.Error {
+	err := connect.NewError(connect.CodeResourceExhausted, errors.New("too many requests"))
+	addInfo(err, ReasonResourceExhausted, nil)
+	addDetail(err, &errdetails.RetryInfo{RetryDelay: durationpb.New(retry)})
+
+	return err
+}
+
+// Unavailable returns an Unavailable error, the cause is logged but never sent to the client.
+func Unavailable(ctx context.Context, cause error) *connect.Error {
+	log.Ctx(ctx).Err(cause).Msg("Service unavailable")
+
+	err := connect.NewError(connect.CodeUnavailable, errors.New("service unavailable"))
+	addInfo(err, ReasonUnavailable, nil)
+	addDetail(err, &errdetails.RetryInfo{RetryDelay: durationpb.New(defaultRetryDelay)})
+
+	return err
+}
+
+// Internal returns an Internal error, the cause is logged but never sent to the client.
+func Internal(ctx context.Context, cause error) *connect.Error {
+	log.Ctx(ctx).Err(cause).Msg("Internal error")
+
+	err := connect.NewError(connect.CodeInternal, errors.New("internal error"))
+	addInfo(err, ReasonInternal, nil)
+
+	return err
+}
+
+// FromRepo maps a repository error of the resource to a domain error.
+func FromRepo(ctx context.Context, resource string, err error) *connect.Error {
+	var connectErr *connect.Error
+	switch {
+	case errors.As(err, &connectErr):
+		return connectErr
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return NotFound(resource)
+	case mongo.IsDuplicateKeyError(err):
+		return AlreadyExists(resource, "key")
+	case IsUnavailable(err):
+		return Unavailable(ctx, err)
+	default:
+		return Internal(ctx, err)
+	}
+}
+
+// IsUnavailable returns true if the error is caused by an unreachable dependency.
+func IsUnavailable(err error) bool {
+	var selectionErr topology.ServerSelectionError
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, mongo.ErrClientDisconnected) ||
+		errors.As(err, &selectionErr) ||
+		mongo.IsTimeout(err) ||
+		mongo.IsNetworkError(err)
+}
+
+// Sanitize converts any error into a domain error, so internal messages never leak to the client.
+func Sanitize(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return FromRepo(ctx, "resource", err)
+	}
+
+	switch connectErr.Code() {
+	case connect.CodeUnknown, connect.CodeInternal, connect.CodeDataLoss:
+		if len(connectErr.Details()) == 0 {
+			return Internal(ctx, err)
+		}
+	}
+
+	return err
+}
+
+// addInfo adds an ErrorInfo detail to the error.
+func addInfo(err *connect.Error, reason string, metadata map[string]string) {
+	addDetail(err, &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   Domain,
+		Metadata: metadata,
+	})
+}
+
+// addDetail adds a detail message to the error.
+func addDetail(err *connect.Error, msg proto.Message) {
+	detail, e := connect.NewErrorDetail(msg)
+	if e != nil {
+		log.Err(e).Msg("Error create error detail")
+		return
+	}
+
+	err.AddDetail(detail)
+}
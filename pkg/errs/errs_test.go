@@ -0,0 +1,125 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+)
+
+// details returns the error details of the type.
+func details[T proto.Message](t *testing.T, err *connect.Error) []T {
+	t.Helper()
+
+	res := make([]T, 0)
+	for _, detail := range err.Details() {
+		value, e := detail.Value()
+		if e != nil {
+			t.Fatalf("detail.Value() error = %v", e)
+		}
+
+		if msg, ok := value.(T); ok {
+			res = append(res, msg)
+		}
+	}
+
+	return res
+}
+
+func TestValidation(t *testing.T) {
+	err := Validation(
+		&FieldViolation{Field: "email", Description: "is required"},
+		&FieldViolation{Field: "password", Description: "is too short"},
+	)
+
+	if err.Code() != connect.CodeInvalidArgument || err.Message() != "invalid argument" {
+		t.Errorf("Validation() = %v, want invalid argument", err)
+	}
+
+	infos := details[*errdetails.ErrorInfo](t, err)
+	if len(infos) != 1 || infos[0].GetReason() != ReasonInvalidArgument || infos[0].GetMetadata()["fields"] != "email,password" {
+		t.Errorf("Validation() error info = %v", infos)
+	}
+
+	requests := details[*errdetails.BadRequest](t, err)
+	if len(requests) != 1 || len(requests[0].GetFieldViolations()) != 2 {
+		t.Fatalf("Validation() bad request = %v, want 2 violations", requests)
+	}
+
+	if v := requests[0].GetFieldViolations()[1]; v.GetField() != "password" || v.GetDescription() != "is too short" {
+		t.Errorf("Validation() violation = %v", v)
+	}
+
+	if err = InvalidArgument("id", "must be a valid object id"); err.Message() != "id: must be a valid object id" {
+		t.Errorf("InvalidArgument() message = %q", err.Message())
+	}
+}
+
+func TestResourceExhausted(t *testing.T) {
+	err := ResourceExhausted(30 * time.Second)
+
+	retries := details[*errdetails.RetryInfo](t, err)
+	if len(retries) != 1 || retries[0].GetRetryDelay().AsDuration() != 30*time.Second {
+		t.Errorf("ResourceExhausted() retry info = %v, want 30s", retries)
+	}
+}
+
+func TestFromRepo(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		err  error
+		want connect.Code
+	}{
+		{name: "no documents", err: fmt.Errorf("find: %w", mongo.ErrNoDocuments), want: connect.CodeNotFound},
+		{name: "duplicate key", err: mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, want: connect.CodeAlreadyExists},
+		{name: "deadline", err: context.DeadlineExceeded, want: connect.CodeUnavailable},
+		{name: "disconnected", err: mongo.ErrClientDisconnected, want: connect.CodeUnavailable},
+		{name: "domain error", err: PermissionDenied("/user.v1.UserService/DeleteUser"), want: connect.CodePermissionDenied},
+		{name: "unexpected", err: errors.New("boom"), want: connect.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromRepo(ctx, "user", tt.err); got.Code() != tt.want {
+				t.Errorf("FromRepo() = %v, want %v", got.Code(), tt.want)
+			}
+		})
+	}
+
+	if got := FromRepo(ctx, "user", mongo.ErrNoDocuments); got.Message() != "user does not exists" {
+		t.Errorf("FromRepo() message = %q", got.Message())
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	ctx := context.Background()
+
+	if Sanitize(ctx, nil) != nil {
+		t.Error("Sanitize(nil) != nil")
+	}
+
+	// the internal messages never reach the client
+	for _, err := range []error{
+		errors.New("dial tcp 10.0.0.1:27017: connection refused"),
+		connect.NewError(connect.CodeInternal, errors.New("mongo: secret")),
+		connect.NewError(connect.CodeUnknown, errors.New("panic: secret")),
+	} {
+		var got *connect.Error
+		if !errors.As(Sanitize(ctx, err), &got) || got.Code() != connect.CodeInternal || got.Message() != "internal error" {
+			t.Errorf("Sanitize(%v) = %v, want internal error", err, got)
+		}
+	}
+
+	domain := NotFound("user")
+	if got := Sanitize(ctx, domain); got != domain {
+		t.Errorf("Sanitize() = %v, want the domain error", got)
+	}
+}
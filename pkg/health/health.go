@@ -0,0 +1,284 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	grpchealth "github.com/bufbuild/connect-grpchealth-go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+const (
+	// StatusUp is the status of a reachable dependency.
+	StatusUp = "up"
+	// StatusDown is the status of an unreachable dependency.
+	StatusDown = "down"
+	// StatusDegraded is the status of the process serving with an optional dependency down.
+	StatusDegraded = "degraded"
+	// StatusDraining is the status of the process during the graceful shutdown.
+	StatusDraining = "draining"
+)
+
+var _ IChecker = (*Checker)(nil)
+
+// Probe checks a dependency, a nil error means the dependency is up.
+type Probe func(ctx context.Context) error
+
+// IChecker is the interface that must be implemented by a health checker.
+type IChecker interface {
+	grpchealth.Checker
+
+	// AddDependency registers a dependency probed periodically.
+	AddDependency(name string, probe Probe)
+	// AddOptionalDependency registers a dependency probed periodically, which never stops the serving.
+	AddOptionalDependency(name string, probe Probe)
+	// AddService registers a service depending on the dependencies, all of them when none are given.
+	AddService(service string, dependencies ...string)
+	// Start probes the dependencies until the checker is drained.
+	Start()
+	// Drain reports every service as not serving, so load balancers stop routing traffic.
+	Drain()
+
+	LivezHandler() http.Handler
+	ReadyzHandler() http.Handler
+}
+
+// Checker is a dependency-aware health checker.
+type Checker struct {
+	interval time.Duration
+	timeout  time.Duration
+
+	mu           sync.RWMutex
+	draining     bool
+	probes       map[string]Probe
+	dependencies map[string]string
+	optional     map[string]bool
+	services     map[string][]string
+
+	once sync.Once
+	stop chan struct{}
+}
+
+// NewChecker creates a new health checker.
+func NewChecker(cfg *config.Config) IChecker {
+	return &Checker{
+		interval:     cfg.Health.Interval,
+		timeout:      cfg.Health.Timeout,
+		probes:       make(map[string]Probe),
+		dependencies: make(map[string]string),
+		optional:     make(map[string]bool),
+		services:     make(map[string][]string),
+		stop:         make(chan struct{}),
+	}
+}
+
+// AddDependency registers a dependency probed periodically, it is down until the first probe.
+func (c *Checker) AddDependency(name string, probe Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.probes[name] = probe
+	c.dependencies[name] = StatusDown
+}
+
+// AddOptionalDependency registers a dependency probed periodically, which is reported by the
+// readiness endpoint but never stops the serving.
+func (c *Checker) AddOptionalDependency(name string, probe Probe) {
+	c.AddDependency(name, probe)
+
+	c.mu.Lock()
+	c.optional[name] = true
+	c.mu.Unlock()
+}
+
+// AddService registers a service depending on the dependencies, all of them when none are given.
+func (c *Checker) AddService(service string, dependencies ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.services[service] = dependencies
+}
+
+// Start probes the dependencies now and then every interval, until the checker is drained.
+func (c *Checker) Start() {
+	c.probe()
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.probe()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Drain reports every service as not serving and stops the probes.
+func (c *Checker) Drain() {
+	c.once.Do(func() {
+		c.mu.Lock()
+		c.draining = true
+		c.mu.Unlock()
+
+		close(c.stop)
+
+		log.Info().Msg("Health checker draining")
+	})
+}
+
+// Check implements grpchealth.Checker.
+func (c *Checker) Check(_ context.Context, req *grpchealth.CheckRequest) (*grpchealth.CheckResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if req.Service == "" {
+		return &grpchealth.CheckResponse{Status: c.statusOf(nil)}, nil
+	}
+
+	dependencies, ok := c.services[req.Service]
+	if !ok {
+		return nil, errs.NotFound("service")
+	}
+
+	return &grpchealth.CheckResponse{Status: c.statusOf(dependencies)}, nil
+}
+
+// LivezHandler reports the process is alive, regardless of the dependencies,
+// so an outage of a dependency never restarts the pods.
+func (c *Checker) LivezHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		utils.ResponseWithJson(w, http.StatusOK, map[string]string{"status": StatusUp})
+	})
+}
+
+// ReadyzHandler reports whether the process can serve traffic, with the status of each dependency.
+func (c *Checker) ReadyzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		c.mu.RLock()
+		status := c.statusOf(nil)
+		body := map[string]any{
+			"status":       StatusUp,
+			"dependencies": c.dependencyStatuses(),
+		}
+		switch {
+		case c.draining:
+			body["status"] = StatusDraining
+		case status != grpchealth.StatusServing:
+			body["status"] = StatusDown
+		case c.degraded():
+			body["status"] = StatusDegraded
+		}
+		c.mu.RUnlock()
+
+		code := http.StatusOK
+		if status != grpchealth.StatusServing {
+			code = http.StatusServiceUnavailable
+		}
+
+		utils.ResponseWithJson(w, code, body)
+	})
+}
+
+// probe runs all the probes concurrently and records the dependency statuses.
+func (c *Checker) probe() {
+	c.mu.RLock()
+	probes := make(map[string]Probe, len(c.probes))
+	for name, probe := range c.probes {
+		probes[name] = probe
+	}
+	c.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	results := make(map[string]string, len(probes))
+	var resultsMu sync.Mutex
+
+	for name, probe := range probes {
+		wg.Add(1)
+		go func(name string, probe Probe) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+			defer cancel()
+
+			status := StatusUp
+			if err := probe(ctx); err != nil {
+				log.Err(err).Str("dependency", name).Msg("Health probe failed")
+				status = StatusDown
+			}
+
+			resultsMu.Lock()
+			results[name] = status
+			resultsMu.Unlock()
+		}(name, probe)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, status := range results {
+		if previous := c.dependencies[name]; previous != status {
+			log.Info().
+				Str("dependency", name).
+				Str("status", status).
+				Msg("Health dependency status changed")
+		}
+		c.dependencies[name] = status
+	}
+}
+
+// statusOf returns the serving status of the dependencies, all of them when none are given.
+// The caller must hold the lock.
+func (c *Checker) statusOf(dependencies []string) grpchealth.Status {
+	if c.draining {
+		return grpchealth.StatusNotServing
+	}
+
+	if len(dependencies) == 0 {
+		for name := range c.dependencies {
+			dependencies = append(dependencies, name)
+		}
+	}
+
+	for _, name := range dependencies {
+		if !c.optional[name] && c.dependencies[name] != StatusUp {
+			return grpchealth.StatusNotServing
+		}
+	}
+
+	return grpchealth.StatusServing
+}
+
+// degraded returns true if an optional dependency is down.
+// The caller must hold the lock.
+func (c *Checker) degraded() bool {
+	for name := range c.optional {
+		if c.dependencies[name] != StatusUp {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dependencyStatuses returns a copy of the dependency statuses.
+// The caller must hold the lock.
+func (c *Checker) dependencyStatuses() map[string]string {
+	result := make(map[string]string, len(c.dependencies))
+	for name, status := range c.dependencies {
+		result[name] = status
+	}
+
+	return result
+}
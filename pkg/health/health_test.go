@@ -0,0 +1,153 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	grpchealth "github.com/bufbuild/connect-grpchealth-go"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+var errDown = errors.New("down")
+
+func newChecker(t *testing.T) IChecker {
+	t.Helper()
+
+	c := NewChecker(&config.Config{Health: config.Health{Interval: time.Hour, Timeout: time.Second}})
+	t.Cleanup(c.Drain)
+
+	return c
+}
+
+func probe(err error) Probe {
+	return func(context.Context) error { return err }
+}
+
+func check(t *testing.T, c IChecker, service string) grpchealth.Status {
+	t.Helper()
+
+	res, err := c.Check(context.Background(), &grpchealth.CheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q) error = %v", service, err)
+	}
+
+	return res.Status
+}
+
+func readyz(t *testing.T, c IChecker) (int, map[string]any) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c.ReadyzHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	body := map[string]any{}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("readyz body error = %v", err)
+	}
+
+	return rec.Code, body
+}
+
+func TestCheck(t *testing.T) {
+	c := newChecker(t)
+	c.AddDependency("mongodb", probe(nil))
+	c.AddDependency("casbin", probe(errDown))
+	c.AddService("user.v1.UserService", "mongodb")
+	c.AddService("role.v1.RoleService")
+
+	if got := check(t, c, ""); got != grpchealth.StatusNotServing {
+		t.Errorf("Check() before the first probe = %v, want not serving", got)
+	}
+
+	c.Start()
+
+	tests := []struct {
+		service string
+		want    grpchealth.Status
+	}{
+		{service: "", want: grpchealth.StatusNotServing},
+		{service: "user.v1.UserService", want: grpchealth.StatusServing},
+		{service: "role.v1.RoleService", want: grpchealth.StatusNotServing},
+	}
+
+	for _, tt := range tests {
+		if got := check(t, c, tt.service); got != tt.want {
+			t.Errorf("Check(%q) = %v, want %v", tt.service, got, tt.want)
+		}
+	}
+
+	_, err := c.Check(context.Background(), &grpchealth.CheckRequest{Service: "unknown.v1.Service"})
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("Check() unknown service code = %v, want %v", connect.CodeOf(err), connect.CodeNotFound)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name     string
+		required error
+		optional error
+		code     int
+		status   string
+	}{
+		{name: "all up", code: http.StatusOK, status: StatusUp},
+		{name: "optional down", optional: errDown, code: http.StatusOK, status: StatusDegraded},
+		{name: "required down", required: errDown, code: http.StatusServiceUnavailable, status: StatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newChecker(t)
+			c.AddDependency("mongodb", probe(tt.required))
+			c.AddOptionalDependency("redis", probe(tt.optional))
+			c.Start()
+
+			code, body := readyz(t, c)
+			if code != tt.code {
+				t.Errorf("readyz code = %d, want %d", code, tt.code)
+			}
+
+			if body["status"] != tt.status {
+				t.Errorf("readyz status = %v, want %s", body["status"], tt.status)
+			}
+
+			dependencies, _ := body["dependencies"].(map[string]any)
+			if len(dependencies) != 2 {
+				t.Errorf("readyz dependencies = %v, want mongodb and redis", dependencies)
+			}
+		})
+	}
+}
+
+func TestDrain(t *testing.T) {
+	c := newChecker(t)
+	c.AddDependency("mongodb", probe(nil))
+	c.AddService("user.v1.UserService")
+	c.Start()
+	c.Drain()
+	// draining twice is a no-op
+	c.Drain()
+
+	if got := check(t, c, "user.v1.UserService"); got != grpchealth.StatusNotServing {
+		t.Errorf("Check() while draining = %v, want not serving", got)
+	}
+
+	code, body := readyz(t, c)
+	if code != http.StatusServiceUnavailable || body["status"] != StatusDraining {
+		t.Errorf("readyz while draining = %d %v, want %d %s", code, body["status"],
+			http.StatusServiceUnavailable, StatusDraining)
+	}
+
+	rec := httptest.NewRecorder()
+	c.LivezHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("livez while draining = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
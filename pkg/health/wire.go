@@ -0,0 +1,10 @@
+package health
+
+import (
+	"github.com/google/wire"
+)
+
+// ProviderHealthSet is health providers.
+var ProviderHealthSet = wire.NewSet(
+	NewChecker,
+)
@@ -0,0 +1,270 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+// Phase is a step of the graceful shutdown, the phases are stopped in order.
+type Phase int
+
+const (
+	// PhaseUnready reports not serving, so the load balancers stop routing traffic.
+	PhaseUnready Phase = iota
+	// PhaseDrain stops accepting connections and waits for the in-flight requests.
+	PhaseDrain
+	// PhaseJobs waits for the background jobs.
+	PhaseJobs
+	// PhaseFlush flushes the logs and the traces.
+	PhaseFlush
+	// PhaseClose closes the stores.
+	PhaseClose
+)
+
+// phases are the phases in shutdown order.
+var phases = []Phase{PhaseUnready, PhaseDrain, PhaseJobs, PhaseFlush, PhaseClose}
+
+// String returns the name of the phase.
+func (p Phase) String() string {
+	switch p {
+	case PhaseUnready:
+		return "unready"
+	case PhaseDrain:
+		return "drain"
+	case PhaseJobs:
+		return "jobs"
+	case PhaseFlush:
+		return "flush"
+	case PhaseClose:
+		return "close"
+	default:
+		return fmt.Sprintf("phase(%d)", int(p))
+	}
+}
+
+var _ ILifecycle = (*Lifecycle)(nil)
+
+// Hook is a component started and stopped with the application.
+type Hook struct {
+	Name  string
+	Phase Phase
+	// OnStart is called in registration order before the server starts serving.
+	OnStart func(ctx context.Context) error
+	// OnStop is called in the phase of the hook, the hooks of a phase are stopped concurrently.
+	OnStop func(ctx context.Context) error
+}
+
+// ILifecycle is the interface that must be implemented by a lifecycle.
+type ILifecycle interface {
+	// Append registers a hook.
+	Append(hook Hook)
+	// Go runs a background job awaited by the jobs phase, its context is canceled
+	// when the jobs phase times out.
+	Go(name string, fn func(ctx context.Context))
+	// Start calls the start hooks in registration order.
+	Start(ctx context.Context) error
+	// Stop calls the stop hooks phase by phase, it returns once all the phases are done.
+	Stop() error
+}
+
+// Lifecycle runs the start and stop hooks of the application.
+type Lifecycle struct {
+	timeouts map[Phase]time.Duration
+
+	mu    sync.Mutex
+	hooks []Hook
+
+	jobs    sync.WaitGroup
+	pending atomic.Int64
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	once sync.Once
+	err  error
+}
+
+// NewLifecycle creates a new lifecycle.
+func NewLifecycle(cfg *config.Config) ILifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Lifecycle{
+		timeouts: map[Phase]time.Duration{
+			PhaseUnready: cfg.Shutdown.UnreadyTimeout,
+			PhaseDrain:   cfg.Shutdown.DrainTimeout,
+			PhaseJobs:    cfg.Shutdown.JobsTimeout,
+			PhaseFlush:   cfg.Shutdown.FlushTimeout,
+			PhaseClose:   cfg.Shutdown.CloseTimeout,
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Append registers a hook.
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, hook)
+}
+
+// Go runs a background job awaited by the jobs phase.
+func (l *Lifecycle) Go(name string, fn func(ctx context.Context)) {
+	l.jobs.Add(1)
+	l.pending.Add(1)
+
+	go func() {
+		defer l.jobs.Done()
+		defer l.pending.Add(-1)
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error().Str("job", name).Interface("panic", r).Msg("Background job panicked")
+			}
+		}()
+
+		fn(l.ctx)
+	}()
+}
+
+// Start calls the start hooks in registration order, it stops at the first error.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	for _, hook := range l.snapshot() {
+		if hook.OnStart == nil {
+			continue
+		}
+
+		if err := hook.OnStart(ctx); err != nil {
+			return fmt.Errorf("start %s: %w", hook.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Stop calls the stop hooks phase by phase, each phase bounded by its timeout.
+// The later calls return the result of the first one.
+func (l *Lifecycle) Stop() error {
+	l.once.Do(func() {
+		hooks := l.snapshot()
+
+		for _, phase := range phases {
+			start := time.Now()
+			if err := l.stopPhase(phase, hooks); err != nil && l.err == nil {
+				l.err = err
+			}
+
+			log.Info().
+				Str("phase", phase.String()).
+				Dur("took", time.Since(start)).
+				Msg("Shutdown phase done")
+		}
+
+		l.cancel()
+	})
+
+	return l.err
+}
+
+// stopPhase calls the stop hooks of the phase concurrently, then waits for the background jobs
+// in the jobs phase.
+func (l *Lifecycle) stopPhase(phase Phase, hooks []Hook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeouts[phase])
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs error
+	)
+
+	for _, hook := range hooks {
+		if hook.Phase != phase || hook.OnStop == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(hook Hook) {
+			defer wg.Done()
+
+			if err := l.stopHook(ctx, hook); err != nil {
+				log.Err(err).Str("phase", phase.String()).Str("hook", hook.Name).Msg("Failed to stop")
+
+				mu.Lock()
+				if errs == nil {
+					errs = fmt.Errorf("stop %s: %w", hook.Name, err)
+				}
+				mu.Unlock()
+			}
+		}(hook)
+	}
+
+	if phase == PhaseJobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := l.waitJobs(ctx); err != nil {
+				mu.Lock()
+				if errs == nil {
+					errs = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+// stopHook calls the stop hook, it gives up on the hook when the context is done.
+func (l *Lifecycle) stopHook(ctx context.Context, hook Hook) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- hook.OnStop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitJobs waits for the background jobs, their context is canceled when the context is done.
+func (l *Lifecycle) waitJobs(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		l.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		log.Warn().
+			Int64("pending", l.pending.Load()).
+			Msg("Background jobs did not finish in time, canceling them")
+		l.cancel()
+
+		return fmt.Errorf("wait jobs: %w", ctx.Err())
+	}
+}
+
+// snapshot returns a copy of the registered hooks.
+func (l *Lifecycle) snapshot() []Hook {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]Hook(nil), l.hooks...)
+}
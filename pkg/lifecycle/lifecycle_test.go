@@ -0,0 +1,160 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+func newLifecycle(timeout time.Duration) ILifecycle {
+	return NewLifecycle(&config.Config{Shutdown: config.Shutdown{
+		UnreadyTimeout: timeout,
+		DrainTimeout:   timeout,
+		JobsTimeout:    timeout,
+		FlushTimeout:   timeout,
+		CloseTimeout:   timeout,
+	}})
+}
+
+func TestStart(t *testing.T) {
+	l := newLifecycle(time.Second)
+	errStart := errors.New("start")
+
+	var calls []string
+	l.Append(Hook{Name: "redis", OnStart: func(context.Context) error { calls = append(calls, "redis"); return nil }})
+	l.Append(Hook{Name: "noop"})
+	l.Append(Hook{Name: "server", OnStart: func(context.Context) error { calls = append(calls, "server"); return errStart }})
+	l.Append(Hook{Name: "jobs", OnStart: func(context.Context) error { calls = append(calls, "jobs"); return nil }})
+
+	err := l.Start(context.Background())
+	if !errors.Is(err, errStart) || err.Error() != "start server: start" {
+		t.Errorf("Start() error = %v, want start server: start", err)
+	}
+
+	if len(calls) != 2 || calls[0] != "redis" || calls[1] != "server" {
+		t.Errorf("Start() calls = %v, want [redis server]", calls)
+	}
+}
+
+func TestStopPhases(t *testing.T) {
+	l := newLifecycle(time.Second)
+
+	var mu sync.Mutex
+	var calls []Phase
+	stop := func(phase Phase) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			calls = append(calls, phase)
+			mu.Unlock()
+
+			return nil
+		}
+	}
+
+	// registered out of order, stopped in phase order
+	for _, phase := range []Phase{PhaseClose, PhaseFlush, PhaseUnready, PhaseJobs, PhaseDrain, PhaseClose} {
+		l.Append(Hook{Name: phase.String(), Phase: phase, OnStop: stop(phase)})
+	}
+
+	if err := l.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	want := []Phase{PhaseUnready, PhaseDrain, PhaseJobs, PhaseFlush, PhaseClose, PhaseClose}
+	if len(calls) != len(want) {
+		t.Fatalf("Stop() calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("Stop() calls = %v, want %v", calls, want)
+			break
+		}
+	}
+}
+
+func TestStopErrors(t *testing.T) {
+	l := newLifecycle(20 * time.Millisecond)
+	errClose := errors.New("close")
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	closed := false
+	l.Append(Hook{Name: "stuck", Phase: PhaseDrain, OnStop: func(context.Context) error {
+		<-release
+		return nil
+	}})
+	l.Append(Hook{Name: "mongodb", Phase: PhaseClose, OnStop: func(context.Context) error {
+		closed = true
+		return errClose
+	}})
+
+	err := l.Stop()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() error = %v, want the timeout of the first failed phase", err)
+	}
+
+	if !closed {
+		t.Error("Stop() skips the phases after a failed phase")
+	}
+
+	// the later calls return the result of the first one
+	if again := l.Stop(); again != err {
+		t.Errorf("Stop() again = %v, want %v", again, err)
+	}
+}
+
+func TestGo(t *testing.T) {
+	l := newLifecycle(time.Second)
+
+	done := make(chan struct{})
+	l.Go("panic", func(context.Context) { panic("job") })
+	l.Go("audit", func(context.Context) {
+		time.Sleep(10 * time.Millisecond)
+		close(done)
+	})
+
+	if err := l.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	select {
+	case <-done:
+	default:
+		t.Error("Stop() returns before the background jobs are done")
+	}
+}
+
+func TestGoCanceled(t *testing.T) {
+	l := newLifecycle(20 * time.Millisecond)
+
+	canceled := make(chan struct{})
+	l.Go("stuck", func(ctx context.Context) {
+		<-ctx.Done()
+		close(canceled)
+	})
+
+	if err := l.Stop(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() error = %v, want the jobs timeout", err)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("Stop() does not cancel the background jobs once the jobs phase times out")
+	}
+}
+
+func TestPhaseString(t *testing.T) {
+	if got := PhaseFlush.String(); got != "flush" {
+		t.Errorf("String() = %q, want flush", got)
+	}
+
+	if got := Phase(9).String(); got != "phase(9)" {
+		t.Errorf("String() = %q, want phase(9)", got)
+	}
+}
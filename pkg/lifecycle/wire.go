@@ -0,0 +1,10 @@
+package lifecycle
+
+import (
+	"github.com/google/wire"
+)
+
+// ProviderLifecycleSet is lifecycle providers.
+var ProviderLifecycleSet = wire.NewSet(
+	NewLifecycle,
+)
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// FieldRequestID is the log field of the request id.
+	FieldRequestID = "request_id"
+	// FieldProcedure is the log field of the procedure.
+	FieldProcedure = "procedure"
+	// FieldUserID is the log field of the authenticated user id.
+	FieldUserID = "user_id"
+	// FieldClientIdentity is the log field of the verified client certificate identity.
+	FieldClientIdentity = "client_identity"
+)
+
+// requestIDKey is the context key of the request id.
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the request id.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request id of the context, empty if none.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// With returns a context carrying the context logger with the extra fields,
+// retrieved with log.Ctx.
+func With(ctx context.Context, fields func(c zerolog.Context) zerolog.Context) context.Context {
+	logger := fields(zerolog.Ctx(ctx).With()).Logger()
+	return logger.WithContext(ctx)
+}
@@ -11,8 +11,13 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// roller is the log file writer, closed by Close.
+var roller *lumberjack.Roller
+
 // NewLogger the default logger
 func NewLogger(logFileUrl string) {
+	roller = getLogWriter(logFileUrl)
+
 	// UNIX Time is faster and smaller than most timestamps
 	consoleWriter := &zerolog.ConsoleWriter{
 		Out:        os.Stdout,
@@ -22,7 +27,7 @@ func NewLogger(logFileUrl string) {
 
 	// Multi Writer
 	writer := []io.Writer{
-		getLogWriter(logFileUrl),
+		roller,
 		consoleWriter,
 	}
 
@@ -45,6 +50,18 @@ func NewLogger(logFileUrl string) {
 		Timestamp().
 		Caller().
 		Logger()
+
+	// log.Ctx falls back to the global logger when the context has none
+	zerolog.DefaultContextLogger = &log.Logger
+}
+
+// Close flushes and closes the log file, it is reopened by the next log.
+func Close() error {
+	if roller == nil {
+		return nil
+	}
+
+	return roller.Close()
 }
 
 // getLogWriter returns a lumberjack.logger
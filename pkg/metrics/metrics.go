@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const (
+	// StatusOK is the status label of a successful call.
+	StatusOK = "ok"
+	// StatusError is the status label of a failed call.
+	StatusError = "error"
+)
+
+var (
+	// RPCRequestsTotal counts the handled RPCs by procedure and connect code.
+	RPCRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rpc_requests_total",
+		Help: "Total number of RPCs handled, by procedure and code.",
+	}, []string{"procedure", "code"})
+
+	// RPCDuration observes the RPC latency by procedure.
+	RPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rpc_request_duration_seconds",
+		Help:    "Latency of the handled RPCs, by procedure.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"procedure"})
+
+	// MongoCommandDuration observes the MongoDB command latency by command and status.
+	MongoCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongodb_command_duration_seconds",
+		Help:    "Latency of the MongoDB commands, by command and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"command", "status"})
+
+	// CasbinEnforceDuration observes the Casbin enforce latency by result.
+	CasbinEnforceDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "casbin_enforce_duration_seconds",
+		Help:    "Latency of the Casbin enforce decisions, by result.",
+		Buckets: []float64{.0001, .0005, .001, .005, .01, .05, .1},
+	}, []string{"result"})
+
+	// CasbinCacheTotal counts the Casbin decision cache lookups by result (hit or miss).
+	CasbinCacheTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "casbin_cache_requests_total",
+		Help: "Total number of Casbin decision cache lookups, by result.",
+	}, []string{"result"})
+
+	// RetentionRecordsTotal counts the soft deleted records removed by the retention, by collection
+	// and action (purge or anonymize).
+	RetentionRecordsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "retention_records_total",
+		Help: "Total number of soft deleted records purged or anonymized, by collection and action.",
+	}, []string{"collection", "action"})
+)
+
+// ObserveRPC records a handled RPC.
+func ObserveRPC(procedure, code string, duration time.Duration) {
+	RPCRequestsTotal.WithLabelValues(procedure, code).Inc()
+	RPCDuration.WithLabelValues(procedure).Observe(duration.Seconds())
+}
+
+// NewCommandMonitor returns a MongoDB command monitor recording the command latency.
+func NewCommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			MongoCommandDuration.
+				WithLabelValues(evt.CommandName, StatusOK).
+				Observe(time.Duration(evt.DurationNanos).Seconds())
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			MongoCommandDuration.
+				WithLabelValues(evt.CommandName, StatusError).
+				Observe(time.Duration(evt.DurationNanos).Seconds())
+		},
+	}
+}
+
+// redisCollector exports the connection pool stats of a redis client.
+type redisCollector struct {
+	client redis.UniversalClient
+
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+}
+
+// redisOnce guards the registration of the redis collector.
+var redisOnce sync.Once
+
+// RegisterRedis registers the pool stats collector of the redis client, only the first client is registered.
+func RegisterRedis(client redis.UniversalClient) {
+	redisOnce.Do(func() {
+		err := prometheus.Register(&redisCollector{
+			client:     client,
+			hits:       prometheus.NewDesc("redis_pool_hits_total", "Number of times a free connection was found in the pool.", nil, nil),
+			misses:     prometheus.NewDesc("redis_pool_misses_total", "Number of times a free connection was not found in the pool.", nil, nil),
+			timeouts:   prometheus.NewDesc("redis_pool_timeouts_total", "Number of times a wait timeout occurred.", nil, nil),
+			totalConns: prometheus.NewDesc("redis_pool_conns_total", "Number of total connections in the pool.", nil, nil),
+			idleConns:  prometheus.NewDesc("redis_pool_conns_idle", "Number of idle connections in the pool.", nil, nil),
+			staleConns: prometheus.NewDesc("redis_pool_conns_stale_total", "Number of stale connections removed from the pool.", nil, nil),
+		})
+
+		var registered prometheus.AlreadyRegisteredError
+		if err != nil && !errors.As(err, &registered) {
+			panic(err)
+		}
+	})
+}
+
+// Describe implements prometheus.Collector.
+func (c *redisCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.staleConns
+}
+
+// Collect implements prometheus.Collector.
+func (c *redisCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// sample returns the counter value, or the histogram sample count, of the gathered series of the
+// metric with the label values.
+func sample(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+	series:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value != label.GetValue() {
+					continue series
+				}
+			}
+
+			if metric.GetHistogram() != nil {
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+
+			return metric.GetCounter().GetValue()
+		}
+	}
+
+	return 0
+}
+
+func TestObserveRPC(t *testing.T) {
+	procedure := "/user.v1.UserService/TestObserveRPC"
+	ObserveRPC(procedure, StatusOK, 10*time.Millisecond)
+	ObserveRPC(procedure, StatusOK, 20*time.Millisecond)
+	ObserveRPC(procedure, "not_found", 5*time.Millisecond)
+
+	if got := sample(t, "rpc_requests_total", map[string]string{"procedure": procedure, "code": StatusOK}); got != 2 {
+		t.Errorf("rpc_requests_total{code=ok} = %v, want 2", got)
+	}
+
+	if got := sample(t, "rpc_requests_total", map[string]string{"procedure": procedure, "code": "not_found"}); got != 1 {
+		t.Errorf("rpc_requests_total{code=not_found} = %v, want 1", got)
+	}
+
+	if got := sample(t, "rpc_request_duration_seconds", map[string]string{"procedure": procedure}); got != 3 {
+		t.Errorf("rpc_request_duration_seconds count = %v, want 3", got)
+	}
+}
+
+func TestCommandMonitor(t *testing.T) {
+	monitor := NewCommandMonitor()
+
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "testFind", DurationNanos: int64(time.Millisecond)},
+	})
+	monitor.Failed(context.Background(), &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "testFind", DurationNanos: int64(time.Millisecond)},
+	})
+
+	for _, status := range []string{StatusOK, StatusError} {
+		labels := map[string]string{"command": "testFind", "status": status}
+		if got := sample(t, "mongodb_command_duration_seconds", labels); got != 1 {
+			t.Errorf("mongodb_command_duration_seconds{status=%s} count = %v, want 1", status, got)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis/v9"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/redis"
+)
+
+const (
+	// StoreRedis keeps the counters in redis, shared by all the nodes.
+	StoreRedis = "redis"
+	// StoreMemory keeps the counters in memory, for a single node and tests.
+	StoreMemory = "memory"
+
+	// keyPrefix is the redis key prefix of the counters.
+	keyPrefix = "ratelimit:%s"
+
+	// sweepInterval is the interval between two sweeps of the expired keys of the memory limiter.
+	sweepInterval = time.Minute
+)
+
+// ThrottledTotal counts the rejected calls by procedure and key type.
+var ThrottledTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rpc_throttled_total",
+	Help: "Total number of RPCs rejected by the rate limiter.",
+}, []string{"procedure", "key_type"})
+
+// ILimiter is the interface that must be implemented by a rate limiter.
+type ILimiter interface {
+	// Allow records a call for the key and returns whether it is allowed,
+	// with the delay before the next call is allowed when it is not.
+	Allow(ctx context.Context, key string, limit int64, window time.Duration) (bool, time.Duration, error)
+}
+
+// slidingWindowScript is a sliding window log on a sorted set.
+//
+// KEYS[1] key, ARGV[1] now (ms), ARGV[2] window (ms), ARGV[3] limit, ARGV[4] member.
+// Returns {allowed, retry after (ms)}.
+var slidingWindowScript = goredis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call("ZREMRANGEBYSCORE", key, 0, now - window)
+local count = redis.call("ZCARD", key)
+if count < limit then
+	redis.call("ZADD", key, now, ARGV[4])
+	redis.call("PEXPIRE", key, window)
+	return {1, 0}
+end
+
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// redisLimiter is a redis sliding window limiter, the calls go through the redis circuit breaker.
+type redisLimiter struct {
+	client redis.IRedis
+}
+
+// NewRedisLimiter creates a new redis sliding window limiter.
+func NewRedisLimiter(client redis.IRedis) ILimiter {
+	return &redisLimiter{
+		client: client,
+	}
+}
+
+// Allow records a call for the key.
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit int64, window time.Duration) (
+	bool, time.Duration, error,
+) {
+	now := time.Now().UnixMilli()
+	res, err := redis.RunScript(ctx, l.client, slidingWindowScript, []string{fmt.Sprintf(keyPrefix, key)},
+		now, window.Milliseconds(), limit, strconv.FormatInt(now, 10)+"-"+uuid.NewString(),
+	)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// memoryLimiter is an in-memory sliding window limiter.
+type memoryLimiter struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*memoryKey
+	lastSweep time.Time
+}
+
+// memoryKey is the sliding window log of a key.
+type memoryKey struct {
+	calls  []time.Time
+	window time.Duration
+}
+
+// NewMemoryLimiter creates a new in-memory sliding window limiter.
+func NewMemoryLimiter() ILimiter {
+	return newMemoryLimiter(time.Now)
+}
+
+// newMemoryLimiter creates a new in-memory sliding window limiter with the clock.
+func newMemoryLimiter(now func() time.Time) *memoryLimiter {
+	return &memoryLimiter{
+		now:       now,
+		keys:      make(map[string]*memoryKey),
+		lastSweep: now(),
+	}
+}
+
+// Allow records a call for the key.
+func (l *memoryLimiter) Allow(_ context.Context, key string, limit int64, window time.Duration) (
+	bool, time.Duration, error,
+) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	k, ok := l.keys[key]
+	if !ok {
+		k = &memoryKey{}
+		l.keys[key] = k
+	}
+	k.window = window
+	k.expire(now)
+
+	if int64(len(k.calls)) >= limit {
+		return false, k.calls[0].Add(window).Sub(now), nil
+	}
+
+	k.calls = append(k.calls, now)
+	return true, 0, nil
+}
+
+// sweep deletes the keys without calls in their window, at most once per sweep interval.
+func (l *memoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, k := range l.keys {
+		if k.expire(now); len(k.calls) == 0 {
+			delete(l.keys, key)
+		}
+	}
+}
+
+// expire drops the calls out of the window.
+func (k *memoryKey) expire(now time.Time) {
+	start := 0
+	for start < len(k.calls) && !k.calls[start].After(now.Add(-k.window)) {
+		start++
+	}
+
+	if start == len(k.calls) {
+		k.calls = nil
+		return
+	}
+
+	k.calls = k.calls[start:]
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is a settable clock of the tests.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestMemoryLimiterAllow(t *testing.T) {
+	c := &clock{now: time.Unix(1700000000, 0)}
+	l := newMemoryLimiter(c.Now)
+	ctx := context.Background()
+
+	for n := 0; n < 2; n++ {
+		if allowed, _, _ := l.Allow(ctx, "key", 2, time.Minute); !allowed {
+			t.Fatalf("Allow() call %d = false, want true", n+1)
+		}
+		c.now = c.now.Add(10 * time.Second)
+	}
+
+	allowed, retry, err := l.Allow(ctx, "key", 2, time.Minute)
+	if err != nil || allowed {
+		t.Fatalf("Allow() over the limit = %v, %v, want false", allowed, err)
+	}
+
+	if retry != 40*time.Second {
+		t.Errorf("Allow() retry = %v, want 40s", retry)
+	}
+
+	if allowed, _, _ = l.Allow(ctx, "other", 2, time.Minute); !allowed {
+		t.Error("Allow() of another key = false, want true")
+	}
+
+	c.now = c.now.Add(retry)
+	if allowed, _, _ = l.Allow(ctx, "key", 2, time.Minute); !allowed {
+		t.Error("Allow() after the oldest call left the window = false, want true")
+	}
+}
+
+func TestMemoryLimiterSweep(t *testing.T) {
+	c := &clock{now: time.Unix(1700000000, 0)}
+	l := newMemoryLimiter(c.Now)
+	ctx := context.Background()
+
+	_, _, _ = l.Allow(ctx, "short", 1, time.Second)
+	_, _, _ = l.Allow(ctx, "long", 1, time.Hour)
+
+	c.now = c.now.Add(sweepInterval)
+	_, _, _ = l.Allow(ctx, "next", 1, time.Second)
+
+	if _, ok := l.keys["short"]; ok {
+		t.Error("the expired key is not swept")
+	}
+
+	if _, ok := l.keys["long"]; !ok {
+		t.Error("the key with a call in its window is swept")
+	}
+
+	if len(l.keys) != 2 {
+		t.Errorf("len(keys) = %d, want 2", len(l.keys))
+	}
+}
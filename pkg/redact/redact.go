@@ -0,0 +1,200 @@
+package redact
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// Redacted is the value written in place of a redacted field.
+	Redacted = "[REDACTED]"
+
+	// debugRedactFieldNumber is the field number of the debug_redact option in google.protobuf.FieldOptions.
+	debugRedactFieldNumber = 16
+)
+
+// Redactor redacts sensitive fields from messages and headers before logging.
+type Redactor struct {
+	fields  map[string]struct{}
+	headers map[string]struct{}
+}
+
+// New creates a new redactor.
+//
+// A field entry matches either a field name at any depth (password), a dot separated
+// path from the root message (user.password) or a full proto field name (auth.v1.LoginRequest.password).
+// Matching is case-insensitive.
+func New(fields, headers []string) *Redactor {
+	r := &Redactor{
+		fields:  make(map[string]struct{}, len(fields)),
+		headers: make(map[string]struct{}, len(headers)),
+	}
+
+	for _, field := range fields {
+		r.fields[strings.ToLower(field)] = struct{}{}
+	}
+
+	for _, header := range headers {
+		r.headers[strings.ToLower(header)] = struct{}{}
+	}
+
+	return r
+}
+
+// Header returns a copy of the header with the sensitive values redacted.
+func (r *Redactor) Header(header http.Header) http.Header {
+	result := make(http.Header, len(header))
+	for key, values := range header {
+		if _, ok := r.headers[strings.ToLower(key)]; ok {
+			result[key] = []string{Redacted}
+			continue
+		}
+
+		result[key] = values
+	}
+
+	return result
+}
+
+// Message returns a copy of the message with the sensitive fields redacted.
+func (r *Redactor) Message(msg any) any {
+	if msg == nil {
+		return nil
+	}
+
+	if m, ok := msg.(proto.Message); ok {
+		clone := proto.Clone(m)
+		r.redactProto(clone.ProtoReflect(), "")
+		return clone
+	}
+
+	// plain messages are redacted from their json representation
+	bytes, err := json.Marshal(msg)
+	if err != nil {
+		return msg
+	}
+
+	var value any
+	if err = json.Unmarshal(bytes, &value); err != nil {
+		return msg
+	}
+
+	return r.redactValue(value, "")
+}
+
+// redactProto redacts the fields of a proto message in place.
+func (r *Redactor) redactProto(m protoreflect.Message, prefix string) {
+	redacted := make([]protoreflect.FieldDescriptor, 0)
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		path := join(prefix, fd.JSONName())
+		if r.matchField(path, fd.JSONName(), string(fd.Name()), string(fd.FullName())) || isDebugRedact(fd) {
+			redacted = append(redacted, fd)
+			return true
+		}
+
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				r.redactProto(list.Get(i).Message(), path)
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+				r.redactProto(value.Message(), path)
+				return true
+			})
+		case !fd.IsMap() && fd.Message() != nil:
+			r.redactProto(v.Message(), path)
+		}
+
+		return true
+	})
+
+	for _, fd := range redacted {
+		if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			m.Set(fd, protoreflect.ValueOfString(Redacted))
+			continue
+		}
+
+		m.Clear(fd)
+	}
+}
+
+// redactValue redacts the fields of a decoded json value.
+func (r *Redactor) redactValue(value any, prefix string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, val := range v {
+			path := join(prefix, key)
+			if r.matchField(path, key) {
+				v[key] = Redacted
+				continue
+			}
+
+			v[key] = r.redactValue(val, path)
+		}
+	case []any:
+		for i, val := range v {
+			v[i] = r.redactValue(val, prefix)
+		}
+	}
+
+	return value
+}
+
+// matchField returns true if one of the names is configured to be redacted.
+func (r *Redactor) matchField(names ...string) bool {
+	for _, name := range names {
+		if _, ok := r.fields[strings.ToLower(name)]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDebugRedact returns true if the field is annotated with [debug_redact = true].
+func isDebugRedact(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+
+	unknown := opts.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		num, typ, n := protowire.ConsumeTag(unknown)
+		if n < 0 {
+			return false
+		}
+		unknown = unknown[n:]
+
+		if num == debugRedactFieldNumber && typ == protowire.VarintType {
+			v, m := protowire.ConsumeVarint(unknown)
+			return m > 0 && v == 1
+		}
+
+		m := protowire.ConsumeFieldValue(num, typ, unknown)
+		if m < 0 {
+			return false
+		}
+		unknown = unknown[m:]
+	}
+
+	return false
+}
+
+// join joins a path prefix with a field name.
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
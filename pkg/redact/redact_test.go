@@ -0,0 +1,84 @@
+package redact
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	userbulkv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/userbulk/v1"
+)
+
+func TestHeader(t *testing.T) {
+	r := New(nil, []string{"Authorization"})
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	header.Set("X-Request-Id", "abc")
+
+	got := r.Header(header)
+	if got.Get("Authorization") != Redacted {
+		t.Errorf("Header() authorization = %q, want %q", got.Get("Authorization"), Redacted)
+	}
+
+	if got.Get("X-Request-Id") != "abc" {
+		t.Errorf("Header() x-request-id = %q, want abc", got.Get("X-Request-Id"))
+	}
+
+	if header.Get("Authorization") != "Bearer token" {
+		t.Error("Header() changed the header of the request")
+	}
+}
+
+func TestMessageProto(t *testing.T) {
+	r := New([]string{"users.email", "userbulk.v1.ImportUser.name"}, nil)
+
+	msg := &userbulkv1.ImportUsersRequest{
+		Options: &userbulkv1.ImportOptions{DryRun: true},
+		Users: []*userbulkv1.ImportUser{
+			{Name: "User", Email: "user@example.com", Password: "secret", Role: "user"},
+		},
+	}
+
+	got, ok := r.Message(msg).(*userbulkv1.ImportUsersRequest)
+	if !ok {
+		t.Fatalf("Message() = %T, want *userbulkv1.ImportUsersRequest", got)
+	}
+
+	user := got.GetUsers()[0]
+	// the password is annotated with debug_redact, the email matches a path and the name a full name
+	want := &userbulkv1.ImportUser{Name: Redacted, Email: Redacted, Password: Redacted, Role: "user"}
+	if user.GetName() != want.GetName() || user.GetEmail() != want.GetEmail() ||
+		user.GetPassword() != want.GetPassword() || user.GetRole() != want.GetRole() {
+		t.Errorf("Message() user = %v, want %v", user, want)
+	}
+
+	if msg.GetUsers()[0].GetPassword() != "secret" {
+		t.Error("Message() changed the message of the request")
+	}
+}
+
+func TestMessagePlain(t *testing.T) {
+	r := New([]string{"Password", "profile.phone"}, nil)
+
+	msg := map[string]any{
+		"email":    "user@example.com",
+		"password": "secret",
+		"profile":  map[string]any{"phone": "0123", "city": "Hanoi"},
+		"devices":  []any{map[string]any{"password": "pin"}},
+	}
+
+	want := map[string]any{
+		"email":    "user@example.com",
+		"password": Redacted,
+		"profile":  map[string]any{"phone": Redacted, "city": "Hanoi"},
+		"devices":  []any{map[string]any{"password": Redacted}},
+	}
+
+	if got := r.Message(msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("Message() = %v, want %v", got, want)
+	}
+
+	if r.Message(nil) != nil {
+		t.Error("Message(nil) != nil")
+	}
+}
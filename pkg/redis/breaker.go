@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrCircuitOpen is returned by the helpers while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("redis circuit breaker is open")
+
+const (
+	// breakerClosed lets the calls through.
+	breakerClosed = iota
+	// breakerOpen rejects the calls until the cooldown is over.
+	breakerOpen
+	// breakerHalfOpen lets a single trial call through.
+	breakerHalfOpen
+)
+
+// Breaker is a circuit breaker that stops calling redis after consecutive failures.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    int
+	failures int
+	openedAt time.Time
+}
+
+// NewBreaker creates a new circuit breaker, opened after threshold consecutive failures
+// and half-opened after the cooldown.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Allow returns true if the call can be made.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+
+		// let a trial call through
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// a trial call is in flight
+		return false
+	default:
+		return true
+	}
+}
+
+// Done records the result of a call, a redis.Nil reply is a success.
+func (b *Breaker) Done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil || errors.Is(err, redis.Nil) {
+		if b.state != breakerClosed {
+			log.Info().Msg("Redis circuit breaker closed")
+		}
+
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+		if b.state != breakerOpen {
+			log.Warn().Err(err).Dur("cooldown", b.cooldown).Msg("Redis circuit breaker opened")
+		}
+
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// Open returns true if the breaker rejects the calls.
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state != breakerClosed
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+)
+
+func TestBreaker(t *testing.T) {
+	b := NewBreaker(2, time.Hour)
+	errDown := errors.New("down")
+
+	b.Done(errDown)
+	if !b.Allow() || b.Open() {
+		t.Fatal("the breaker is open below the threshold")
+	}
+
+	b.Done(redis.Nil)
+	b.Done(errDown)
+	if b.Open() {
+		t.Fatal("a redis.Nil reply does not reset the failures")
+	}
+
+	b.Done(errDown)
+	if b.Allow() || !b.Open() {
+		t.Fatal("the breaker is closed after the threshold")
+	}
+
+	// the cooldown is over, a single trial call goes through
+	b.openedAt = time.Now().Add(-time.Hour)
+	if !b.Allow() {
+		t.Fatal("the trial call is rejected after the cooldown")
+	}
+	if b.Allow() {
+		t.Fatal("a second call goes through while the trial call is in flight")
+	}
+
+	b.Done(errDown)
+	if b.Allow() {
+		t.Fatal("the breaker is closed after a failed trial call")
+	}
+
+	b.openedAt = time.Now().Add(-time.Hour)
+	b.Allow()
+	b.Done(nil)
+	if !b.Allow() || b.Open() {
+		t.Fatal("the breaker is open after a succeeded trial call")
+	}
+}
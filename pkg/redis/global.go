@@ -3,92 +3,149 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
+	"github.com/go-redis/redis/v9"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
 // Set is a setter for any.
-func Set(r IRedis, key string, value any, expiration time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+func Set(ctx context.Context, r IRedis, key string, value any, expiration time.Duration) error {
+	return call(ctx, r, "Failed to set keys", func(ctx context.Context) error {
+		return r.Set(ctx, key, value, expiration).Err()
+	})
+}
 
-	if err := r.Set(ctx, key, value, expiration).Err(); err != nil {
-		log.Err(err).Msg("Failed to set keys")
+// SetProto is a setter for the proto.
+func SetProto(ctx context.Context, r IRedis, key string, value proto.Message, expiration time.Duration) error {
+	bytes, err := protojson.Marshal(value)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return Set(ctx, r, key, bytes, expiration)
 }
 
-// SetProto is a setter for the proto.
-func SetProto(r IRedis, key string, value proto.Message, expiration time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	bytes, err := protojson.Marshal(value)
+// SetObject is a setter for the object.
+func SetObject(ctx context.Context, r IRedis, key string, value any, expiration time.Duration) error {
+	bytes, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
 
-	if err = r.Set(ctx, key, bytes, expiration).Err(); err != nil {
-		log.Err(err).Msg("Failed to set keys")
+	return Set(ctx, r, key, bytes, expiration)
+}
+
+// Exists checks if the keys exists.
+func Exists(ctx context.Context, r IRedis, keys ...string) (bool, error) {
+	var count int64
+	err := call(ctx, r, "Failed to check keys", func(ctx context.Context) error {
+		var err error
+		count, err = r.Exists(ctx, keys...).Result()
 		return err
-	}
+	})
 
-	return nil
+	return count > 0, err
 }
 
-// SetObject is a setter for the object.
-func SetObject(r IRedis, key string, value any, expiration time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+// Del deletes the keys.
+func Del(ctx context.Context, r IRedis, keys ...string) error {
+	return call(ctx, r, "Failed to delete keys", func(ctx context.Context) error {
+		return r.Del(ctx, keys...).Err()
+	})
+}
 
-	bytes, err := json.Marshal(value)
-	if err != nil {
+// Get returns the value of the key, empty on a cache miss or when redis is unavailable.
+func Get(ctx context.Context, r IRedis, key string) string {
+	var val string
+	_ = call(ctx, r, "Failed to get key", func(ctx context.Context) error {
+		var err error
+		val, err = r.Get(ctx, key).Result()
 		return err
-	}
+	})
 
-	if err = r.Set(ctx, key, bytes, expiration).Err(); err != nil {
-		log.Err(err).Msg("Failed to set keys")
+	return val
+}
+
+// MGet returns the values of the keys, nil for the missing keys.
+func MGet(ctx context.Context, r IRedis, keys ...string) ([]any, error) {
+	var val []any
+	err := call(ctx, r, "Failed to get keys", func(ctx context.Context) error {
+		var err error
+		val, err = r.MGet(ctx, keys...).Result()
 		return err
-	}
+	})
 
-	return nil
+	return val, err
 }
 
-// Exists checks if the keys exists.
-func Exists(r IRedis, keys ...string) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+// HSet sets the fields of the hash and refreshes its expiration.
+func HSet(ctx context.Context, r IRedis, key string, values map[string]any, expiration time.Duration) error {
+	return call(ctx, r, "Failed to set hash fields", func(ctx context.Context) error {
+		pipe := r.TxPipeline()
+		pipe.HSet(ctx, key, values)
+		pipe.Expire(ctx, key, expiration)
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+}
 
-	count := r.Exists(ctx, keys...).Val()
-	return count > 0
+// HDel deletes the fields of the hash.
+func HDel(ctx context.Context, r IRedis, key string, fields ...string) error {
+	return call(ctx, r, "Failed to delete hash fields", func(ctx context.Context) error {
+		return r.HDel(ctx, key, fields...).Err()
+	})
 }
 
-// Del deletes the keys.
-func Del(r IRedis, keys ...string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+// HGetAll returns the fields of the hash.
+func HGetAll(ctx context.Context, r IRedis, key string) (map[string]string, error) {
+	var val map[string]string
+	err := call(ctx, r, "Failed to get hash fields", func(ctx context.Context) error {
+		var err error
+		val, err = r.HGetAll(ctx, key).Result()
+		return err
+	})
 
-	if err := r.Del(ctx, keys...).Err(); err != nil {
-		log.Err(err).Msg("Failed to delete keys")
+	return val, err
+}
+
+// RunScript runs the lua script and returns its integer replies.
+func RunScript(ctx context.Context, r IRedis, script *redis.Script, keys []string, args ...any) ([]int64, error) {
+	var val []int64
+	err := call(ctx, r, "Failed to run script", func(ctx context.Context) error {
+		var err error
+		val, err = script.Run(ctx, r, keys, args...).Int64Slice()
 		return err
-	}
+	})
 
-	return nil
+	return val, err
 }
 
-func Get(r IRedis, key string) string {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+// call runs the redis command through the circuit breaker,
+// the failures are logged once as a warning since redis is an optional cache.
+func call(ctx context.Context, r IRedis, msg string, fn func(ctx context.Context) error) error {
+	breaker := r.Breaker()
+	if !breaker.Allow() {
+		return ErrCircuitOpen
+	}
+
+	cmdCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	val, err := r.Get(ctx, key).Result()
-	if err != nil {
-		log.Err(err).Msg("Failed to get key")
+	err := fn(cmdCtx)
+	breaker.Done(err)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, redis.Nil):
+		log.Ctx(ctx).Debug().Msg("Cache miss")
+	default:
+		log.Ctx(ctx).Warn().Err(err).Msg(msg)
 	}
 
-	return val
+	return err
 }
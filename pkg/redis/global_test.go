@@ -0,0 +1,20 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+)
+
+func TestRunScriptCircuitOpen(t *testing.T) {
+	r := &Redis{breaker: NewBreaker(1, time.Hour)}
+	r.breaker.Done(errors.New("down"))
+
+	_, err := RunScript(context.Background(), r, redis.NewScript("return {1, 0}"), []string{"key"})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("RunScript() error = %v, want %v", err, ErrCircuitOpen)
+	}
+}
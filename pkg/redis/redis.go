@@ -4,11 +4,15 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/go-redis/redis/extra/redisotel/v9"
 	"github.com/go-redis/redis/v9"
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/metrics"
 )
 
 var _ IRedis = (*Redis)(nil)
@@ -17,6 +21,7 @@ var _ IRedis = (*Redis)(nil)
 type IRedis interface {
 	redis.UniversalClient
 
+	Breaker() *Breaker
 	Close() error
 }
 
@@ -26,17 +31,23 @@ type Redis struct {
 	addrs    []string
 	password string
 	db       int
+	timeout  time.Duration
+	backoff  time.Duration
+	breaker  *Breaker
+	closed   atomic.Bool
 
 	redis.UniversalClient
 }
 
 // NewRedis is new redis.
-func NewRedis() IRedis {
-	redisURL := strings.Split(viper.GetString("redis.url"), " ")
+func NewRedis(cfg *config.Config) IRedis {
 	r := &Redis{
-		addrs:    redisURL,
-		password: viper.GetString("redis.password"), // no password set
-		db:       viper.GetInt("redis.db"),          // use default DB
+		addrs:    strings.Fields(cfg.Redis.URL),
+		password: cfg.Redis.Password,
+		db:       cfg.Redis.DB,
+		timeout:  cfg.Redis.Timeout,
+		backoff:  cfg.Redis.MaxConnectBackoff,
+		breaker:  NewBreaker(cfg.Redis.Breaker.Threshold, cfg.Redis.Breaker.Cooldown),
 	}
 
 	log.Info().
@@ -44,30 +55,68 @@ func NewRedis() IRedis {
 		Int("redis_db", r.db).
 		Msg("Connecting to Redis")
 
+	// the connections are opened lazily, redis is an optional cache
 	client := redis.NewUniversalClient(&redis.UniversalOptions{
-		Addrs:    r.addrs,
-		Password: r.password,
-		DB:       r.db,
-		PoolSize: 1000,
+		Addrs:           r.addrs,
+		Password:        r.password,
+		DB:              r.db,
+		PoolSize:        1000,
+		DialTimeout:     r.timeout,
+		ReadTimeout:     r.timeout,
+		WriteTimeout:    r.timeout,
+		MaxRetries:      cfg.Redis.MaxRetries,
+		MinRetryBackoff: cfg.Redis.MinRetryBackoff,
+		MaxRetryBackoff: cfg.Redis.MaxRetryBackoff,
 	})
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	if err := client.Ping(ctx).Err(); err != nil {
-		log.Panic().Err(err).Msg("Connecting to Redis failed")
-	}
-
 	// Add client to redis
 	r.setClient(client)
 
-	log.Info().Msg("Connecting to Redis successfully.")
+	// Export the pool stats
+	metrics.RegisterRedis(client)
+
+	// Trace the commands
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		log.Err(err).Msg("Failed to instrument Redis tracing")
+	}
+
+	go r.connect()
 
 	return r
 }
 
+// connect pings redis with an exponential backoff until it is reachable.
+func (r *Redis) connect() {
+	backoff := time.Second
+
+	for !r.closed.Load() {
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		err := r.Ping(ctx).Err()
+		cancel()
+
+		if err == nil {
+			log.Info().Msg("Connecting to Redis successfully.")
+			return
+		}
+
+		log.Warn().Err(err).Dur("retry_in", backoff).Msg("Connecting to Redis failed")
+		time.Sleep(backoff)
+
+		if backoff *= 2; backoff > r.backoff {
+			backoff = r.backoff
+		}
+	}
+}
+
+// Breaker returns the circuit breaker of the redis helpers.
+func (r *Redis) Breaker() *Breaker {
+	return r.breaker
+}
+
 // Close closes the redis.
 func (r *Redis) Close() error {
+	r.closed.Store(true)
+
 	if err := r.UniversalClient.Close(); err != nil {
 		log.Err(err).Msg("Failed to close from Redis")
 		return err
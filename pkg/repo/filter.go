@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IDs returns the id as a string and, if it is a valid hex, as an object id, since the
+// records are stored with either.
+func IDs(id string) []any {
+	res := []any{id}
+	if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+		res = append(res, oid)
+	}
+
+	return res
+}
+
+// IDFilter returns the filter of the record with the id, deleted or not.
+func IDFilter(id string) bson.M {
+	return bson.M{
+		"_id": bson.M{
+			"$in": IDs(id),
+		},
+	}
+}
+
+// ActiveIDFilter returns the filter of the record with the id, unless it is soft deleted.
+func ActiveIDFilter(id string) bson.M {
+	filter := IDFilter(id)
+	filter["deleted_at"] = bson.M{
+		"$exists": false,
+	}
+
+	return filter
+}
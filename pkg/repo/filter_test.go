@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIDs(t *testing.T) {
+	oid := primitive.NewObjectID()
+
+	if got, want := IDs(oid.Hex()), []any{oid.Hex(), oid}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDs() = %v, want %v", got, want)
+	}
+
+	if got, want := IDs("admin"), []any{"admin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDs() = %v, want %v", got, want)
+	}
+}
+
+func TestActiveIDFilter(t *testing.T) {
+	want := bson.M{
+		"_id":        bson.M{"$in": []any{"admin"}},
+		"deleted_at": bson.M{"$exists": false},
+	}
+
+	if got := ActiveIDFilter("admin"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ActiveIDFilter() = %v, want %v", got, want)
+	}
+
+	if _, ok := IDFilter("admin")["deleted_at"]; ok {
+		t.Error("IDFilter() filters the deleted records")
+	}
+}
@@ -11,13 +11,13 @@ import (
 )
 
 // Find return an array of objects
-func Find[T any](collection *mongo.Collection, filter any, opt ...*options.FindOptions) ([]*T, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func Find[T any](ctx context.Context, collection *mongo.Collection, filter any, opt ...*options.FindOptions) ([]*T, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	cur, err := collection.Find(ctx, filter, opt...)
 	if err != nil {
-		log.Err(err).Msg("Error find all users")
+		log.Ctx(ctx).Err(err).Msg("Error find all users")
 		return nil, err
 	}
 
@@ -29,7 +29,7 @@ func Find[T any](collection *mongo.Collection, filter any, opt ...*options.FindO
 	for cur.Next(ctx) {
 		obj := new(T)
 		if err = cur.Decode(obj); err != nil {
-			log.Err(err).Msg("Error find all")
+			log.Ctx(ctx).Err(err).Msg("Error find all")
 			return nil, err
 		}
 
@@ -39,14 +39,58 @@ func Find[T any](collection *mongo.Collection, filter any, opt ...*options.FindO
 	return data, nil
 }
 
+// Each calls fn with the objects in batches of batchSize, the cursor is not bound to a timeout
+// since the iteration lasts as long as fn, the context cancels it.
+func Each[T any](ctx context.Context, collection *mongo.Collection, filter any, batchSize int,
+	fn func([]*T) error, opt ...*options.FindOptions,
+) error {
+	opt = append(opt, options.Find().SetBatchSize(int32(batchSize)))
+	cur, err := collection.Find(ctx, filter, opt...)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msg("Error find each")
+		return err
+	}
+
+	defer func() {
+		_ = cur.Close(ctx)
+	}()
+
+	batch := make([]*T, 0, batchSize)
+	for cur.Next(ctx) {
+		obj := new(T)
+		if err = cur.Decode(obj); err != nil {
+			log.Ctx(ctx).Err(err).Msg("Error find each")
+			return err
+		}
+
+		if batch = append(batch, obj); len(batch) == batchSize {
+			if err = fn(batch); err != nil {
+				return err
+			}
+			batch = make([]*T, 0, batchSize)
+		}
+	}
+
+	if err = cur.Err(); err != nil {
+		log.Ctx(ctx).Err(err).Msg("Error find each")
+		return err
+	}
+
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+
+	return nil
+}
+
 // CountDocuments returns the number of documents
-func CountDocuments(collection *mongo.Collection, filter any) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func CountDocuments(ctx context.Context, collection *mongo.Collection, filter any) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
-		log.Err(err).Msg("Error count all users")
+		log.Ctx(ctx).Err(err).Msg("Error count all users")
 		return 0, err
 	}
 
@@ -54,16 +98,16 @@ func CountDocuments(collection *mongo.Collection, filter any) (int64, error) {
 }
 
 // FindOne return an object
-func FindOne[T any](collection *mongo.Collection, filter any, opt ...*options.FindOneOptions) (
+func FindOne[T any](ctx context.Context, collection *mongo.Collection, filter any, opt ...*options.FindOneOptions) (
 	*T, error,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	result := new(T)
 	err := collection.FindOne(ctx, filter, opt...).Decode(result)
 	if err != nil {
-		log.Err(err).Msg("Error find user")
+		log.Ctx(ctx).Err(err).Msg("Error find user")
 		return nil, err
 	}
 
@@ -71,15 +115,15 @@ func FindOne[T any](collection *mongo.Collection, filter any, opt ...*options.Fi
 }
 
 // InsertOne inserts one
-func InsertOne(collection *mongo.Collection, data any, opt ...*options.InsertOneOptions) (
+func InsertOne(ctx context.Context, collection *mongo.Collection, data any, opt ...*options.InsertOneOptions) (
 	*mongo.InsertOneResult, error,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	res, err := collection.InsertOne(ctx, data, opt...)
 	if err != nil {
-		log.Err(err).Msg("Error creating user")
+		log.Ctx(ctx).Err(err).Msg("Error creating user")
 		return nil, err
 	}
 
@@ -87,15 +131,15 @@ func InsertOne(collection *mongo.Collection, data any, opt ...*options.InsertOne
 }
 
 // InsertMany insert many
-func InsertMany(collection *mongo.Collection, data []any, opt ...*options.InsertManyOptions) (
+func InsertMany(ctx context.Context, collection *mongo.Collection, data []any, opt ...*options.InsertManyOptions) (
 	*mongo.InsertManyResult, error,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	res, err := collection.InsertMany(ctx, data, opt...)
 	if err != nil {
-		log.Err(err).Msg("Error insert many")
+		log.Ctx(ctx).Err(err).Msg("Error insert many")
 		return nil, err
 	}
 
@@ -103,15 +147,15 @@ func InsertMany(collection *mongo.Collection, data []any, opt ...*options.Insert
 }
 
 // UpdateOne updates one
-func UpdateOne(collection *mongo.Collection, filter, data any, opt ...*options.UpdateOptions) (
+func UpdateOne(ctx context.Context, collection *mongo.Collection, filter, data any, opt ...*options.UpdateOptions) (
 	*mongo.UpdateResult, error,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	res, err := collection.UpdateOne(ctx, filter, data, opt...)
 	if err != nil {
-		log.Err(err).Msg("Error updating user")
+		log.Ctx(ctx).Err(err).Msg("Error updating user")
 		return nil, err
 	}
 
@@ -119,15 +163,15 @@ func UpdateOne(collection *mongo.Collection, filter, data any, opt ...*options.U
 }
 
 // UpdateMany updates many
-func UpdateMany(collection *mongo.Collection, filter, data any, opt ...*options.UpdateOptions) (
+func UpdateMany(ctx context.Context, collection *mongo.Collection, filter, data any, opt ...*options.UpdateOptions) (
 	*mongo.UpdateResult, error,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	res, err := collection.UpdateMany(ctx, filter, data, opt...)
 	if err != nil {
-		log.Err(err).Msg("Error updating user")
+		log.Ctx(ctx).Err(err).Msg("Error updating user")
 		return nil, err
 	}
 
@@ -135,15 +179,15 @@ func UpdateMany(collection *mongo.Collection, filter, data any, opt ...*options.
 }
 
 // DeleteOne deletes one
-func DeleteOne(collection *mongo.Collection, filter any, opt ...*options.DeleteOptions) (
+func DeleteOne(ctx context.Context, collection *mongo.Collection, filter any, opt ...*options.DeleteOptions) (
 	*mongo.DeleteResult, error,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	res, err := collection.DeleteOne(ctx, filter, opt...)
 	if err != nil {
-		log.Err(err).Msg("Error deleting user")
+		log.Ctx(ctx).Err(err).Msg("Error deleting user")
 		return nil, err
 	}
 
@@ -151,15 +195,15 @@ func DeleteOne(collection *mongo.Collection, filter any, opt ...*options.DeleteO
 }
 
 // DeleteMany deletes many
-func DeleteMany(collection *mongo.Collection, filter any, opt ...*options.DeleteOptions) (
+func DeleteMany(ctx context.Context, collection *mongo.Collection, filter any, opt ...*options.DeleteOptions) (
 	*mongo.DeleteResult, error,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	res, err := collection.DeleteMany(ctx, filter, opt...)
 	if err != nil {
-		log.Err(err).Msg("Error deleting user")
+		log.Ctx(ctx).Err(err).Msg("Error deleting user")
 		return nil, err
 	}
 
@@ -167,23 +211,39 @@ func DeleteMany(collection *mongo.Collection, filter any, opt ...*options.Delete
 }
 
 // SoftDeleteOne soft deletes one
-func SoftDeleteOne(collection *mongo.Collection, filter any, opt ...*options.UpdateOptions) (
+func SoftDeleteOne(ctx context.Context, collection *mongo.Collection, filter any, opt ...*options.UpdateOptions) (
 	*mongo.UpdateResult, error,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	data := bson.M{
 		"$set": bson.D{
 			{
-				"deleted_at",
-				time.Now(),
+				Key:   "deleted_at",
+				Value: time.Now(),
 			},
 		},
 	}
 	res, err := collection.UpdateOne(ctx, filter, data, opt...)
 	if err != nil {
-		log.Err(err).Msg("Error soft deleting user")
+		log.Ctx(ctx).Err(err).Msg("Error soft deleting user")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CreateIndexes creates many indexes
+func CreateIndexes(ctx context.Context, collection *mongo.Collection, models []mongo.IndexModel, opt ...*options.CreateIndexesOptions) (
+	[]string, error,
+) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	res, err := collection.Indexes().CreateMany(ctx, models, opt...)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msg("Error create indexes")
 		return nil, err
 	}
 
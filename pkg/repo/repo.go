@@ -2,14 +2,20 @@ package repo
 
 import (
 	"context"
+	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/metrics"
 	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
 )
 
@@ -34,32 +40,36 @@ type Repo struct {
 }
 
 // NewRepo creates a new repository.
-func NewRepo() IRepo {
+func NewRepo(cfg *config.Config) IRepo {
 	r := &Repo{
-		dbURL:  viper.GetString("database.url"),
-		dbName: viper.GetString("database.name"),
+		dbURL:  cfg.Database.URL,
+		dbName: cfg.Database.Name,
 	}
 
 	log.Info().
-		Str("db_url", r.dbURL).
+		Str("db_url", redactURL(r.dbURL)).
 		Str("db_name", r.dbName).
 		Msg("Connecting to MongoDB")
 
-	// Set connect timeout to 15 seconds
-	ctxConn, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	clientOpts := options.Client().
-		ApplyURI(r.dbURL)
+	clientOpts, err := newClientOptions(&cfg.Database)
+	if err != nil {
+		log.Panic().Err(err).Msg("Invalid MongoDB options")
+	}
 
-	// Create new client and connect to MongoDB
-	client, err := mongo.Connect(ctxConn, clientOpts)
+	// Create new client, the connections are opened in background
+	client, err := mongo.Connect(context.Background(), clientOpts)
 	if err != nil {
 		log.Panic().Err(err).Msg("Connecting to MongoDB failed")
 	}
 
-	// Ping the primary
-	if err = client.Ping(ctxConn, nil); err != nil {
+	// Ping the primary until it is reachable
+	err = utils.Retry("Ping to MongoDB", &cfg.Database.ConnectBackoff, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		return client.Ping(ctx, readpref.Primary())
+	})
+	if err != nil {
 		log.Panic().Err(err).Msg("Ping to MongoDB failed")
 	}
 
@@ -71,6 +81,79 @@ func NewRepo() IRepo {
 	return r
 }
 
+// newClientOptions returns the client options of the uri with the pool settings of the config,
+// a zero setting keeps the uri or driver default.
+func newClientOptions(cfg *config.Database) (*options.ClientOptions, error) {
+	clientOpts := options.Client().
+		ApplyURI(cfg.URL).
+		SetMonitor(newMonitor(metrics.NewCommandMonitor(), otelmongo.NewMonitor()))
+
+	if v := cfg.MinPoolSize; v > 0 {
+		clientOpts.SetMinPoolSize(v)
+	}
+
+	if v := cfg.MaxPoolSize; v > 0 {
+		clientOpts.SetMaxPoolSize(v)
+	}
+
+	if v := cfg.MaxConnIdleTime; v > 0 {
+		clientOpts.SetMaxConnIdleTime(v)
+	}
+
+	if v := cfg.ConnectTimeout; v > 0 {
+		clientOpts.SetConnectTimeout(v)
+	}
+
+	if v := cfg.ServerSelectionTimeout; v > 0 {
+		clientOpts.SetServerSelectionTimeout(v)
+	}
+
+	if v := cfg.SocketTimeout; v > 0 {
+		clientOpts.SetSocketTimeout(v)
+	}
+
+	if v := cfg.ReadPreference; v != "" {
+		mode, err := readpref.ModeFromString(v)
+		if err != nil {
+			return nil, err
+		}
+
+		rp, err := readpref.New(mode)
+		if err != nil {
+			return nil, err
+		}
+		clientOpts.SetReadPreference(rp)
+	}
+
+	if v := cfg.WriteConcern.W; v != "" {
+		wcOpts := []writeconcern.Option{
+			writeconcern.WTimeout(cfg.WriteConcern.Timeout),
+			writeconcern.J(cfg.WriteConcern.Journal),
+		}
+
+		if v == "majority" {
+			wcOpts = append(wcOpts, writeconcern.WMajority())
+		} else if w, err := strconv.Atoi(v); err == nil {
+			wcOpts = append(wcOpts, writeconcern.W(w))
+		} else {
+			wcOpts = append(wcOpts, writeconcern.WTagSet(v))
+		}
+		clientOpts.SetWriteConcern(writeconcern.New(wcOpts...))
+	}
+
+	return clientOpts, clientOpts.Validate()
+}
+
+// redactURL returns the uri with the password replaced by xxxxx, for the logs.
+func redactURL(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "invalid uri"
+	}
+
+	return u.Redacted()
+}
+
 // Close closes the repository.
 func (r *Repo) Close() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
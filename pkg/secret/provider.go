@@ -0,0 +1,84 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	_ IProvider = (*FileProvider)(nil)
+	_ IProvider = (*EnvProvider)(nil)
+	_ IProvider = (*StubProvider)(nil)
+)
+
+// FileProvider reads the secrets from files, the relative paths are resolved from the directory.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a new file provider.
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{
+		dir: dir,
+	}
+}
+
+// Get returns the content of the file without the trailing newline.
+func (p *FileProvider) Get(_ context.Context, ref string) (string, error) {
+	path := ref
+	if !filepath.IsAbs(path) && p.dir != "" {
+		path = filepath.Join(p.dir, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// EnvProvider reads the secrets from environment variables.
+type EnvProvider struct{}
+
+// NewEnvProvider creates a new environment provider.
+func NewEnvProvider() *EnvProvider {
+	return &EnvProvider{}
+}
+
+// Get returns the value of the environment variable.
+func (p *EnvProvider) Get(_ context.Context, ref string) (string, error) {
+	val, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return val, nil
+}
+
+// StubProvider reads the secrets from a static map, for local runs and tests.
+type StubProvider struct {
+	secrets map[string]string
+}
+
+// NewStubProvider creates a new stub provider.
+func NewStubProvider(secrets map[string]string) *StubProvider {
+	return &StubProvider{
+		secrets: secrets,
+	}
+}
+
+// Get returns the secret of the map.
+func (p *StubProvider) Get(_ context.Context, ref string) (string, error) {
+	val, ok := p.secrets[ref]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return val, nil
+}
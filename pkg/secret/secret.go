@@ -0,0 +1,93 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+const (
+	// ProviderFile reads the secrets from files, e.g. Kubernetes secret mounts.
+	ProviderFile = "file"
+	// ProviderEnv reads the secrets from environment variables.
+	ProviderEnv = "env"
+	// ProviderVault reads the secrets from a HashiCorp Vault KV v2 compatible HTTP API.
+	ProviderVault = "vault"
+	// ProviderStub reads the secrets from a static map, for local runs and tests.
+	ProviderStub = "stub"
+)
+
+// ErrNotFound is returned when a secret does not exist.
+var ErrNotFound = errors.New("secret not found")
+
+// referencePattern matches the ${provider:ref} secret references.
+var referencePattern = regexp.MustCompile(`\$\{([a-z]+):([^}]+)\}`)
+
+// IProvider is the interface that must be implemented by a secret provider.
+type IProvider interface {
+	// Get returns the secret of the reference.
+	Get(ctx context.Context, ref string) (string, error)
+}
+
+// Resolver replaces the secret references of the values by the secrets of the providers.
+type Resolver struct {
+	mu        sync.RWMutex
+	providers map[string]IProvider
+}
+
+// NewResolver creates a new resolver without providers.
+func NewResolver() *Resolver {
+	return &Resolver{
+		providers: make(map[string]IProvider),
+	}
+}
+
+// Register registers the provider of the references starting with name.
+func (r *Resolver) Register(name string, provider IProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.providers[name] = provider
+}
+
+// HasReference returns true if the value contains a secret reference.
+func HasReference(value string) bool {
+	return referencePattern.MatchString(value)
+}
+
+// Resolve returns the value with the ${provider:ref} references replaced by their secrets,
+// e.g. "mongodb://root:${env:MONGO_PASSWORD}@localhost:27017".
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	var err error
+	resolved := referencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		if err != nil {
+			return match
+		}
+
+		parts := referencePattern.FindStringSubmatch(match)
+		name, ref := parts[1], parts[2]
+
+		r.mu.RLock()
+		provider, ok := r.providers[name]
+		r.mu.RUnlock()
+		if !ok {
+			err = fmt.Errorf("secret %s:%s: unknown provider %q", name, ref, name)
+			return match
+		}
+
+		var val string
+		if val, err = provider.Get(ctx, ref); err != nil {
+			err = fmt.Errorf("secret %s:%s: %w", name, ref, err)
+			return match
+		}
+
+		return val
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resolved, nil
+}
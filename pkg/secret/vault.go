@@ -0,0 +1,105 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ IProvider = (*VaultProvider)(nil)
+
+// VaultOption is the Vault provider option.
+type VaultOption struct {
+	// Address is the Vault address, e.g. http://127.0.0.1:8200.
+	Address string
+	// Token is the Vault token.
+	Token string
+	// Namespace is the Vault Enterprise namespace, if any.
+	Namespace string
+	// Mount is the KV v2 secrets engine mount.
+	Mount   string
+	Timeout time.Duration
+}
+
+// VaultProvider reads the secrets from a HashiCorp Vault KV v2 compatible HTTP API.
+// The references are "path#key", e.g. ${vault:mongodb#password}.
+type VaultProvider struct {
+	address   string
+	token     string
+	namespace string
+	mount     string
+	client    *http.Client
+}
+
+// vaultResponse is the response of a KV v2 read.
+type vaultResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+// NewVaultProvider creates a new Vault provider.
+func NewVaultProvider(opt *VaultOption) *VaultProvider {
+	mount := opt.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+
+	return &VaultProvider{
+		address:   strings.TrimRight(opt.Address, "/"),
+		token:     opt.Token,
+		namespace: opt.Namespace,
+		mount:     strings.Trim(mount, "/"),
+		client:    &http.Client{Timeout: opt.Timeout},
+	}
+}
+
+// Get returns the key of the secret at the path.
+func (p *VaultProvider) Get(ctx context.Context, ref string) (string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("reference %q must be path#key", ref)
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", p.address, p.mount, strings.Trim(path, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("vault responded %s", resp.Status)
+	}
+
+	body := &vaultResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(body); err != nil {
+		return "", err
+	}
+
+	val, ok := body.Data.Data[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return fmt.Sprint(val), nil
+}
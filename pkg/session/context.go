@@ -0,0 +1,21 @@
+package session
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// claimsKey is the context key of the verified access token claims.
+type claimsKey struct{}
+
+// WithClaims returns a context carrying the claims of the verified access token.
+func WithClaims(ctx context.Context, claims *jwt.RegisteredClaims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// Claims returns the claims of the access token verified by the interceptor, nil if none.
+func Claims(ctx context.Context) *jwt.RegisteredClaims {
+	claims, _ := ctx.Value(claimsKey{}).(*jwt.RegisteredClaims)
+	return claims
+}
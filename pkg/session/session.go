@@ -0,0 +1,255 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+	"github.com/xdorro/golang-grpc-base-project/pkg/redis"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils/constants"
+)
+
+const (
+	// FailOpen accepts the tokens when the revocation list is unavailable.
+	FailOpen = "open"
+	// FailClosed rejects the tokens when the revocation list is unavailable.
+	FailClosed = "closed"
+
+	// secondsBefore bounds the revocation times stored in seconds, the later ones are in microseconds.
+	secondsBefore = 1e12
+)
+
+var _ ISession = (*Session)(nil)
+
+// ISession is the interface that must be implemented by a session store.
+type ISession interface {
+	// Revoke revokes the session of the token, the access and refresh tokens share the session.
+	Revoke(ctx context.Context, claims *jwt.RegisteredClaims) error
+	// IsRevoked returns true if the session of the token, or all the sessions of its subject, are revoked.
+	IsRevoked(ctx context.Context, claims *jwt.RegisteredClaims) (bool, error)
+	// Track records the session of the subject until it expires.
+	Track(ctx context.Context, subject, sessionID string, expiresAt time.Time) error
+	// List returns the active sessions of the subject, the most recent first.
+	List(ctx context.Context, subject string) ([]*Info, error)
+	// RevokeAll revokes every session of the subject issued until now, tracked or not.
+	RevokeAll(ctx context.Context, subject string) error
+}
+
+// Info is an active session of a subject.
+type Info struct {
+	ID        string    `json:"id"`
+	Subject   string    `json:"subject"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Option session option.
+type Option struct {
+	Config *config.Config
+	Redis  redis.IRedis
+}
+
+// Session is a session store keeping the revoked sessions in redis.
+type Session struct {
+	failMode string
+
+	// options
+	redis redis.IRedis
+}
+
+// NewSession creates a new session store.
+func NewSession(opt *Option) ISession {
+	s := &Session{
+		failMode: opt.Config.Auth.RevocationFailMode,
+		redis:    opt.Redis,
+	}
+
+	if s.failMode != FailOpen {
+		s.failMode = FailClosed
+	}
+
+	return s
+}
+
+// Revoke revokes the session of the token until the token expires.
+func (s *Session) Revoke(ctx context.Context, claims *jwt.RegisteredClaims) error {
+	if claims.ID == "" {
+		return nil
+	}
+
+	expiration := utils.RefreshExpire
+	if claims.ExpiresAt != nil {
+		expiration = time.Until(claims.ExpiresAt.Time)
+	}
+
+	if expiration <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf(constants.RevokedSessionKey, claims.ID)
+	if err := redis.Set(ctx, s.redis, key, 1, expiration); err != nil {
+		return s.fail(ctx, err)
+	}
+
+	// the list of the active sessions is informative, the revocation list is authoritative
+	if claims.Subject != "" {
+		_ = redis.HDel(ctx, s.redis, fmt.Sprintf(constants.AuthSessionsKey, claims.Subject), claims.ID)
+	}
+
+	return nil
+}
+
+// IsRevoked returns true if the session of the token is revoked, or if it was issued before
+// the sessions of its subject were all revoked.
+func (s *Session) IsRevoked(ctx context.Context, claims *jwt.RegisteredClaims) (bool, error) {
+	if claims.ID == "" {
+		return false, nil
+	}
+
+	keys := []string{fmt.Sprintf(constants.RevokedSessionKey, claims.ID)}
+	if claims.Subject != "" {
+		keys = append(keys, fmt.Sprintf(constants.RevokedBeforeKey, claims.Subject))
+	}
+
+	values, err := redis.MGet(ctx, s.redis, keys...)
+	if err != nil {
+		return false, s.fail(ctx, err)
+	}
+
+	if len(values) > 0 && values[0] != nil {
+		return true, nil
+	}
+
+	if len(values) < 2 || values[1] == nil {
+		return false, nil
+	}
+
+	return issuedBefore(claims, fmt.Sprint(values[1])), nil
+}
+
+// issuedBefore returns true if the token was issued strictly before the revocation time, in
+// microseconds. The issue time is read from the session id, the issue date of the token is in
+// seconds so the tokens of older ids issued in the second of the revocation are revoked too.
+// The tokens without an issue date are revoked, and the revocation times stored in seconds
+// revoke the tokens of the whole second.
+func issuedBefore(claims *jwt.RegisteredClaims, value string) bool {
+	before, _ := strconv.ParseInt(value, 10, 64)
+	if before < secondsBefore {
+		before = (before + 1) * 1e6
+	}
+
+	if issuedAt, ok := idIssuedAt(claims.ID); ok {
+		return issuedAt < before
+	}
+
+	if claims.IssuedAt == nil {
+		return true
+	}
+
+	return claims.IssuedAt.Unix()*1e6 < before
+}
+
+// NewID returns a new session id prefixed with its issue time in microseconds, the revocation of
+// all the sessions of a subject compares it since the issue date of the tokens is in seconds.
+func NewID(issuedAt time.Time) string {
+	return strconv.FormatInt(issuedAt.UnixMicro(), 10) + "-" + uuid.NewString()
+}
+
+// idIssuedAt returns the issue time in microseconds of a session id made by NewID.
+func idIssuedAt(id string) (int64, bool) {
+	prefix, rest, ok := strings.Cut(id, "-")
+	if !ok || strings.Count(rest, "-") != 4 {
+		return 0, false
+	}
+
+	issuedAt, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return issuedAt, true
+}
+
+// RevokeAll revokes the sessions of the subject issued until now, until the longest token expires.
+// It fails when redis is unavailable whatever the fail mode, the caller must not assume the
+// sessions are gone.
+func (s *Session) RevokeAll(ctx context.Context, subject string) error {
+	if subject == "" {
+		return nil
+	}
+
+	key := fmt.Sprintf(constants.RevokedBeforeKey, subject)
+	if err := redis.Set(ctx, s.redis, key, time.Now().UnixMicro(), utils.RefreshExpire); err != nil {
+		return errs.Unavailable(ctx, err)
+	}
+
+	_ = redis.Del(ctx, s.redis, fmt.Sprintf(constants.AuthSessionsKey, subject))
+
+	return nil
+}
+
+// Track records the session of the subject, the hash expires with its latest session.
+func (s *Session) Track(ctx context.Context, subject, sessionID string, expiresAt time.Time) error {
+	expiration := time.Until(expiresAt)
+	if subject == "" || sessionID == "" || expiration <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf(constants.AuthSessionsKey, subject)
+	return redis.HSet(ctx, s.redis, key, map[string]any{sessionID: expiresAt.Unix()}, expiration)
+}
+
+// List returns the active sessions of the subject, the expired sessions are pruned.
+func (s *Session) List(ctx context.Context, subject string) ([]*Info, error) {
+	key := fmt.Sprintf(constants.AuthSessionsKey, subject)
+	fields, err := redis.HGetAll(ctx, s.redis, key)
+	if err != nil {
+		return nil, errs.Unavailable(ctx, err)
+	}
+
+	now := time.Now()
+	expired := make([]string, 0)
+	list := make([]*Info, 0, len(fields))
+	for id, value := range fields {
+		unix, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || !time.Unix(unix, 0).After(now) {
+			expired = append(expired, id)
+			continue
+		}
+
+		list = append(list, &Info{
+			ID:        id,
+			Subject:   subject,
+			ExpiresAt: time.Unix(unix, 0),
+		})
+	}
+
+	if len(expired) > 0 {
+		_ = redis.HDel(ctx, s.redis, key, expired...)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ExpiresAt.After(list[j].ExpiresAt)
+	})
+
+	return list, nil
+}
+
+// fail applies the fail mode to an unavailable revocation list.
+func (s *Session) fail(ctx context.Context, err error) error {
+	if s.failMode == FailOpen {
+		log.Ctx(ctx).Warn().Err(err).Msg("Revocation list unavailable, failing open")
+		return nil
+	}
+
+	return errs.Unavailable(ctx, err)
+}
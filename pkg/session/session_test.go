@@ -0,0 +1,134 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/utils"
+)
+
+func TestIssuedBefore(t *testing.T) {
+	revokedAt := time.Date(2026, 10, 19, 12, 0, 0, 500*int(time.Millisecond), time.UTC)
+	micros := strconv.FormatInt(revokedAt.UnixMicro(), 10)
+	seconds := strconv.FormatInt(revokedAt.Unix(), 10)
+
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		id       string
+		value    string
+		want     bool
+	}{
+		{
+			name:     "issued before the revocation",
+			issuedAt: revokedAt.Add(-time.Microsecond),
+			value:    micros,
+			want:     true,
+		},
+		{
+			name:     "issued later in the same second",
+			issuedAt: revokedAt.Add(100 * time.Millisecond),
+			value:    micros,
+		},
+		{
+			name:     "issued at the revocation",
+			issuedAt: revokedAt,
+			value:    micros,
+		},
+		{
+			name:  "without an issue date",
+			id:    "-",
+			value: micros,
+			want:  true,
+		},
+		{
+			name:     "id without the issue time, same second",
+			issuedAt: revokedAt.Add(100 * time.Millisecond),
+			id:       "12345678-9abc-def0-1234-56789abcdef0",
+			value:    micros,
+			want:     true,
+		},
+		{
+			name:     "id without the issue time, next second",
+			issuedAt: revokedAt.Add(time.Second),
+			id:       "12345678-9abc-def0-1234-56789abcdef0",
+			value:    micros,
+		},
+		{
+			name:     "revoked in seconds, same second",
+			issuedAt: revokedAt.Add(400 * time.Millisecond),
+			value:    seconds,
+			want:     true,
+		},
+		{
+			name:     "revoked in seconds, next second",
+			issuedAt: revokedAt.Add(time.Second),
+			value:    seconds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &jwt.RegisteredClaims{ID: tt.id}
+			if !tt.issuedAt.IsZero() {
+				claims.IssuedAt = jwt.NewNumericDate(tt.issuedAt)
+				if claims.ID == "" {
+					claims.ID = NewID(tt.issuedAt)
+				}
+			}
+
+			if got := issuedBefore(claims, tt.value); got != tt.want {
+				t.Errorf("issuedBefore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssuedAtPrecision(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+
+	revokedAt := time.Now()
+	value := strconv.FormatInt(revokedAt.UnixMicro(), 10)
+
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		want     bool
+	}{
+		{name: "issued a millisecond before", issuedAt: revokedAt.Add(-time.Millisecond), want: true},
+		{name: "issued a millisecond after", issuedAt: revokedAt.Add(time.Millisecond)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := utils.EncryptToken(key, &jwt.RegisteredClaims{
+				IssuedAt: jwt.NewNumericDate(tt.issuedAt),
+				ID:       NewID(tt.issuedAt),
+			})
+			if err != nil {
+				t.Fatalf("EncryptToken() error = %v", err)
+			}
+
+			claims, err := utils.DecryptToken(&key.PublicKey, token)
+			if err != nil {
+				t.Fatalf("DecryptToken() error = %v", err)
+			}
+
+			if got := claims.IssuedAt.Time; !got.Equal(tt.issuedAt.Truncate(time.Second)) {
+				t.Errorf("DecryptToken() issued at = %v, want it in seconds", got)
+			}
+
+			if got := issuedBefore(claims, value); got != tt.want {
+				t.Errorf("issuedBefore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package session
+
+import (
+	"github.com/google/wire"
+)
+
+// ProviderSessionSet is session providers.
+var ProviderSessionSet = wire.NewSet(
+	NewSession,
+	wire.Struct(new(Option), "*"),
+)
@@ -0,0 +1,192 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+	"github.com/xdorro/golang-grpc-base-project/pkg/logger"
+)
+
+const (
+	// ExporterNone disables the tracing, the spans are still propagated.
+	ExporterNone = "none"
+	// ExporterStdout writes the spans to the standard output.
+	ExporterStdout = "stdout"
+	// ExporterFile writes the spans to a file.
+	ExporterFile = "file"
+	// ExporterOTLP sends the spans to an OTLP gRPC collector.
+	ExporterOTLP = "otlp"
+
+	// instrumentationName is the name of the tracer of the project.
+	instrumentationName = "github.com/xdorro/golang-grpc-base-project"
+)
+
+var _ ITracing = (*Tracing)(nil)
+
+// ITracing is the interface that must be implemented by a tracing.
+type ITracing interface {
+	Close() error
+}
+
+// Tracing is a tracing struct.
+type Tracing struct {
+	mu          sync.Mutex
+	serviceName string
+	exporter    string
+	endpoint    string
+	insecure    bool
+	file        string
+	sampleRatio float64
+
+	provider *sdktrace.TracerProvider
+	closers  []func() error
+}
+
+// NewTracing creates the global tracer provider and the W3C trace-context propagator.
+func NewTracing(cfg *config.Config) ITracing {
+	t := &Tracing{
+		serviceName: cfg.App.Name,
+		exporter:    cfg.Tracing.Exporter,
+		endpoint:    cfg.Tracing.Endpoint,
+		insecure:    cfg.Tracing.Insecure,
+		file:        cfg.Tracing.File,
+		sampleRatio: cfg.Tracing.SampleRatio,
+	}
+
+	// incoming trace context is always honored, even without exporter
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if t.exporter == "" || t.exporter == ExporterNone {
+		return t
+	}
+
+	log.Info().
+		Str("exporter", t.exporter).
+		Float64("sample_ratio", t.sampleRatio).
+		Msg("Starting tracing")
+
+	exporter, err := t.newExporter()
+	if err != nil {
+		log.Panic().Err(err).Msg("Failed to create tracing exporter")
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(t.serviceName),
+	))
+	if err != nil {
+		log.Panic().Err(err).Msg("Failed to create tracing resource")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(t.sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	// Add provider to tracing
+	t.setProvider(provider)
+
+	log.Info().Msg("Starting tracing successfully.")
+
+	return t
+}
+
+// newExporter creates the configured span exporter.
+func (t *Tracing) newExporter() (sdktrace.SpanExporter, error) {
+	switch t.exporter {
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterFile:
+		if err := os.MkdirAll(filepath.Dir(t.file), 0o755); err != nil {
+			return nil, err
+		}
+
+		file, err := os.OpenFile(t.file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		t.closers = append(t.closers, file.Close)
+
+		return stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(t.endpoint),
+		}
+		if t.insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		return otlptracegrpc.New(ctx, opts...)
+	}
+}
+
+// Close flushes the pending spans and closes the exporter.
+func (t *Tracing) Close() error {
+	if t.provider == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := t.provider.Shutdown(ctx); err != nil {
+		log.Err(err).Msg("Failed to shutdown tracing")
+		return err
+	}
+
+	for _, closer := range t.closers {
+		_ = closer()
+	}
+
+	return nil
+}
+
+// setProvider adds a new tracer provider to the tracing.
+func (t *Tracing) setProvider(provider *sdktrace.TracerProvider) {
+	t.mu.Lock()
+	t.provider = provider
+	t.mu.Unlock()
+}
+
+// Start starts a span of the project tracer.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// WithLogger returns a context carrying a logger with the trace and span ids of the current span,
+// retrieved with log.Ctx.
+func WithLogger(ctx context.Context) context.Context {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return ctx
+	}
+
+	return logger.With(ctx, func(c zerolog.Context) zerolog.Context {
+		return c.
+			Str("trace_id", spanCtx.TraceID().String()).
+			Str("span_id", spanCtx.SpanID().String())
+	})
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/xdorro/golang-grpc-base-project/config"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+)
+
+func TestFileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spans", "trace.json")
+
+	cfg := &config.Config{}
+	cfg.App.Name = "test"
+	cfg.Tracing = config.Tracing{Exporter: ExporterFile, File: file, SampleRatio: 1}
+
+	tracing := NewTracing(cfg)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	})
+
+	// the incoming trace context is continued
+	header := http.Header{}
+	header.Set("traceparent", traceparent)
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	_, span := Start(ctx, "/user.v1.UserService/TestFileExporter")
+	if got := span.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("Start() trace id = %s, want %s", got, traceID)
+	}
+	span.End()
+
+	if err := tracing.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	if !strings.Contains(string(data), "/user.v1.UserService/TestFileExporter") {
+		t.Errorf("the exported spans miss the span: %s", data)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := zerolog.New(buf).WithContext(context.Background())
+
+	// no span, no ids
+	if got := WithLogger(ctx); got != ctx {
+		t.Error("WithLogger() changed the context without span")
+	}
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx = WithLogger(trace.ContextWithSpanContext(ctx, spanCtx))
+	log.Ctx(ctx).Info().Msg("test")
+
+	for _, want := range []string{spanCtx.TraceID().String(), spanCtx.SpanID().String()} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log line %s misses %s", buf.String(), want)
+		}
+	}
+}
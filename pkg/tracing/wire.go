@@ -0,0 +1,10 @@
+package tracing
+
+import (
+	"github.com/google/wire"
+)
+
+// ProviderTracingSet is tracing providers.
+var ProviderTracingSet = wire.NewSet(
+	NewTracing,
+)
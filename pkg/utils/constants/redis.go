@@ -3,6 +3,12 @@ package constants
 const (
 	// AuthSessionKey is the redis key of the auth session.
 	AuthSessionKey = "auth:%s:session:%s"
+	// AuthSessionsKey is the redis key of the hash of the active sessions of a user.
+	AuthSessionsKey = "auth:%s:sessions"
+	// RevokedSessionKey is the redis key of a revoked auth session.
+	RevokedSessionKey = "auth:revoked:%s"
+	// RevokedBeforeKey is the redis key of the time, in microseconds, before which the sessions of a user are revoked.
+	RevokedBeforeKey = "auth:%s:revoked_before"
 	// ListAuthPermissionsKey is the redis key of the list of auth permissions.
 	ListAuthPermissionsKey = "auth:permissions"
 )
@@ -1,12 +1,11 @@
 package utils
 
 import (
+	"crypto/rsa"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
-	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 )
 
 const (
@@ -19,17 +18,10 @@ const (
 )
 
 // EncryptToken encrypt token
-func EncryptToken(claims *jwt.RegisteredClaims) (string, error) {
-	signKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(viper.GetString("jwt.signKey")))
-	if err != nil {
-		log.Err(err).Msg("Error ParseRSAPrivateKeyFromPEM")
-		return "", err
-	}
-
+func EncryptToken(signKey *rsa.PrivateKey, claims *jwt.RegisteredClaims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodRS512, claims)
 	tokenString, err := token.SignedString(signKey)
 	if err != nil {
-		log.Err(err).Msg("Error encrypt token")
 		return "", err
 	}
 
@@ -37,13 +29,7 @@ func EncryptToken(claims *jwt.RegisteredClaims) (string, error) {
 }
 
 // DecryptToken decrypt token
-func DecryptToken(tokenString string) (*jwt.RegisteredClaims, error) {
-	verifyKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(viper.GetString("jwt.verifyKey")))
-	if err != nil {
-		log.Err(err).Msg("Error ParseRSAPublicKeyFromPEM")
-		return nil, err
-	}
-
+func DecryptToken(verifyKey *rsa.PublicKey, tokenString string) (*jwt.RegisteredClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (
 		interface{}, error,
 	) {
@@ -55,7 +41,6 @@ func DecryptToken(tokenString string) (*jwt.RegisteredClaims, error) {
 		return verifyKey, nil
 	})
 	if err != nil {
-		log.Err(err).Msg("Error decrypt token")
 		return nil, err
 	}
 
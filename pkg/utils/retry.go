@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Backoff is an exponential retry policy.
+type Backoff struct {
+	// Attempts is the maximum number of attempts, 0 retries forever.
+	Attempts int `mapstructure:"attempts"`
+	// Initial is the delay after the first failure.
+	Initial time.Duration `mapstructure:"initial"`
+	// Max caps the delay between two attempts.
+	Max time.Duration `mapstructure:"max"`
+}
+
+// Retry calls fn until it succeeds or the attempts are exhausted, doubling the delay after each failure.
+// It returns the last error.
+func Retry(name string, backoff *Backoff, fn func() error) error {
+	delay := backoff.Initial
+	if delay <= 0 {
+		delay = time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		if backoff.Attempts > 0 && attempt >= backoff.Attempts {
+			return err
+		}
+
+		log.Warn().
+			Err(err).
+			Int("attempt", attempt).
+			Dur("retry_in", delay).
+			Msgf("%s failed, retrying", name)
+		time.Sleep(delay)
+
+		if delay *= 2; backoff.Max > 0 && delay > backoff.Max {
+			delay = backoff.Max
+		}
+	}
+}
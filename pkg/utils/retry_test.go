@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	errFail := errors.New("fail")
+
+	tests := []struct {
+		name     string
+		attempts int
+		failures int
+		want     error
+		calls    int
+	}{
+		{name: "first attempt", attempts: 3, calls: 1},
+		{name: "after failures", attempts: 3, failures: 2, calls: 3},
+		{name: "attempts exhausted", attempts: 3, failures: 5, want: errFail, calls: 3},
+		{name: "forever", failures: 4, calls: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Retry("test", &Backoff{Attempts: tt.attempts, Initial: time.Millisecond, Max: 2 * time.Millisecond},
+				func() error {
+					calls++
+					if calls <= tt.failures {
+						return errFail
+					}
+
+					return nil
+				})
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Retry() error = %v, want %v", err, tt.want)
+			}
+
+			if calls != tt.calls {
+				t.Errorf("Retry() calls = %d, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 )
@@ -9,6 +10,16 @@ import (
 const (
 	// HeaderAuthorize header authorize
 	HeaderAuthorize = "authorization"
+	// HeaderAPIKey header api key
+	HeaderAPIKey = "x-api-key"
+	// HeaderForwardedFor header forwarded for
+	HeaderForwardedFor = "x-forwarded-for"
+	// HeaderRealIP header real ip
+	HeaderRealIP = "x-real-ip"
+	// HeaderRequestID header request id
+	HeaderRequestID = "x-request-id"
+	// HeaderIncludeDeleted header include deleted
+	HeaderIncludeDeleted = "x-include-deleted"
 )
 
 // TotalPage returns the total number of pages.
@@ -59,3 +70,85 @@ func AuthFromHeader(header http.Header, expectedScheme string) (string, error) {
 
 	return splits[1], nil
 }
+
+// ParseProxies parses the trusted proxies, IP addresses or CIDR ranges.
+func ParseProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an IP address or a CIDR range", proxy)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an IP address or a CIDR range", proxy)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// ClientIP returns the client ip from the peer address. The proxy headers are only read when the
+// peer is a trusted proxy, the forwarded addresses are then read from the right and the first
+// address that is not a trusted proxy is the client.
+func ClientIP(header http.Header, addr string, trusted []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	if !isTrusted(host, trusted) {
+		return host
+	}
+
+	if val := header.Get(HeaderForwardedFor); val != "" {
+		hops := strings.Split(val, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+
+			if host = hop; !isTrusted(hop, trusted) {
+				break
+			}
+		}
+
+		return host
+	}
+
+	if val := strings.TrimSpace(header.Get(HeaderRealIP)); net.ParseIP(val) != nil {
+		return val
+	}
+
+	return host
+}
+
+// isTrusted returns true if the ip is in one of the trusted networks.
+func isTrusted(host string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseProxies(t *testing.T) {
+	networks, err := ParseProxies([]string{"10.0.0.0/8", "192.168.1.10", "::1"})
+	if err != nil {
+		t.Fatalf("ParseProxies() error = %v", err)
+	}
+
+	want := []string{"10.0.0.0/8", "192.168.1.10/32", "::1/128"}
+	for i, network := range networks {
+		if network.String() != want[i] {
+			t.Errorf("ParseProxies()[%d] = %s, want %s", i, network, want[i])
+		}
+	}
+
+	for _, proxy := range []string{"10.0.0.0/33", "proxy.example.com", ""} {
+		if _, err = ParseProxies([]string{proxy}); err == nil {
+			t.Errorf("ParseProxies(%q) error = nil, want an error", proxy)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("ParseProxies() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		addr    string
+		forward string
+		realIP  string
+		want    string
+	}{
+		{
+			name: "peer address",
+			addr: "203.0.113.7:51234",
+			want: "203.0.113.7",
+		},
+		{
+			name:    "headers of an untrusted peer",
+			addr:    "203.0.113.7:51234",
+			forward: "198.51.100.1",
+			realIP:  "198.51.100.2",
+			want:    "203.0.113.7",
+		},
+		{
+			name:    "forwarded by a trusted proxy",
+			addr:    "10.0.0.2:51234",
+			forward: "198.51.100.1",
+			want:    "198.51.100.1",
+		},
+		{
+			name:    "spoofed entry before the client",
+			addr:    "10.0.0.2:51234",
+			forward: "1.2.3.4, 198.51.100.1, 10.0.0.3",
+			want:    "198.51.100.1",
+		},
+		{
+			name:    "only trusted proxies",
+			addr:    "10.0.0.2:51234",
+			forward: "10.0.0.4, 10.0.0.3",
+			want:    "10.0.0.4",
+		},
+		{
+			name:    "invalid forwarded entry",
+			addr:    "10.0.0.2:51234",
+			forward: "198.51.100.1, unknown",
+			want:    "10.0.0.2",
+		},
+		{
+			name:   "real ip of a trusted proxy",
+			addr:   "10.0.0.2:51234",
+			realIP: "198.51.100.2",
+			want:   "198.51.100.2",
+		},
+		{
+			name: "address without port",
+			addr: "203.0.113.7",
+			want: "203.0.113.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.forward != "" {
+				header.Set(HeaderForwardedFor, tt.forward)
+			}
+			if tt.realIP != "" {
+				header.Set(HeaderRealIP, tt.realIP)
+			}
+
+			if got := ClientIP(header, tt.addr, trusted); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
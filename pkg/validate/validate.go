@@ -0,0 +1,189 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+)
+
+// FieldRule is a declarative constraint of a message field.
+//
+// String rules apply to every item of a repeated string field.
+type FieldRule struct {
+	// Field is the proto name of the field.
+	Field string
+	// Required rejects empty values, optional fields are only checked when set.
+	Required bool
+	// MinLen and MaxLen are the rune length bounds of a string.
+	MinLen int
+	MaxLen int
+	// MaxBytes is the byte length bound of a string, for the values limited in bytes such as the
+	// bcrypt passwords.
+	MaxBytes int
+	// Pattern is a regular expression a string must match.
+	Pattern *regexp.Regexp
+	// Email requires a valid email address.
+	Email bool
+	// In is the list of allowed string values.
+	In []string
+	// Gte and Lte are the bounds of an integer, checked when not nil.
+	Gte *int64
+	Lte *int64
+	// MaxItems is the maximum size of a repeated field.
+	MaxItems int
+}
+
+// Rules are the field rules of the messages, by message full name.
+type Rules map[protoreflect.FullName][]*FieldRule
+
+// Int64 returns a pointer to the value, for the Gte and Lte rules.
+func Int64(v int64) *int64 {
+	return &v
+}
+
+// Validate validates the message against the rules and returns the field violations.
+func (r Rules) Validate(msg any) []*errs.FieldViolation {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	message := m.ProtoReflect()
+	rules, ok := r[message.Descriptor().FullName()]
+	if !ok {
+		return nil
+	}
+
+	violations := make([]*errs.FieldViolation, 0)
+	for _, rule := range rules {
+		fd := message.Descriptor().Fields().ByName(protoreflect.Name(rule.Field))
+		if fd == nil {
+			continue
+		}
+
+		violations = append(violations, rule.validate(message, fd)...)
+	}
+
+	return violations
+}
+
+// validate validates the field value of the message.
+func (rule *FieldRule) validate(message protoreflect.Message, fd protoreflect.FieldDescriptor) []*errs.FieldViolation {
+	field := fd.JSONName()
+
+	if fd.HasPresence() && !message.Has(fd) {
+		if rule.Required {
+			return []*errs.FieldViolation{{Field: field, Description: "is required"}}
+		}
+
+		return nil
+	}
+
+	value := message.Get(fd)
+	if fd.IsList() {
+		list := value.List()
+		if rule.Required && list.Len() == 0 {
+			return []*errs.FieldViolation{{Field: field, Description: "is required"}}
+		}
+
+		if rule.MaxItems > 0 && list.Len() > rule.MaxItems {
+			return []*errs.FieldViolation{{Field: field, Description: fmt.Sprintf("must have at most %d items", rule.MaxItems)}}
+		}
+
+		violations := make([]*errs.FieldViolation, 0)
+		for i := 0; i < list.Len(); i++ {
+			violations = append(violations, rule.validateValue(fmt.Sprintf("%s[%d]", field, i), fd, list.Get(i))...)
+		}
+
+		return violations
+	}
+
+	return rule.validateValue(field, fd, value)
+}
+
+// validateValue validates a single value.
+func (rule *FieldRule) validateValue(field string, fd protoreflect.FieldDescriptor, v protoreflect.Value) []*errs.FieldViolation {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return rule.validateString(field, v.String())
+	case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Sint32Kind, protoreflect.Sint64Kind,
+		protoreflect.Sfixed32Kind, protoreflect.Sfixed64Kind:
+		return rule.validateInt(field, v.Int())
+	}
+
+	return nil
+}
+
+// ValidateString validates a string value of the field, for the values that are not proto messages.
+func (rule *FieldRule) ValidateString(value string) []*errs.FieldViolation {
+	return rule.validateString(rule.Field, value)
+}
+
+// validateString validates a string value.
+func (rule *FieldRule) validateString(field, value string) []*errs.FieldViolation {
+	violation := func(desc string) []*errs.FieldViolation {
+		return []*errs.FieldViolation{{Field: field, Description: desc}}
+	}
+
+	if value == "" {
+		if rule.Required {
+			return violation("is required")
+		}
+
+		return nil
+	}
+
+	length := utf8.RuneCountInString(value)
+	if rule.MinLen > 0 && length < rule.MinLen {
+		return violation(fmt.Sprintf("must be at least %d characters", rule.MinLen))
+	}
+
+	if rule.MaxLen > 0 && length > rule.MaxLen {
+		return violation(fmt.Sprintf("must be at most %d characters", rule.MaxLen))
+	}
+
+	if rule.MaxBytes > 0 && len(value) > rule.MaxBytes {
+		return violation(fmt.Sprintf("must be at most %d bytes", rule.MaxBytes))
+	}
+
+	if rule.Pattern != nil && !rule.Pattern.MatchString(value) {
+		return violation(fmt.Sprintf("must match %s", rule.Pattern.String()))
+	}
+
+	if rule.Email {
+		if addr, err := mail.ParseAddress(value); err != nil || addr.Address != value {
+			return violation("must be a valid email address")
+		}
+	}
+
+	if len(rule.In) > 0 {
+		for _, in := range rule.In {
+			if value == in {
+				return nil
+			}
+		}
+
+		return violation(fmt.Sprintf("must be one of %v", rule.In))
+	}
+
+	return nil
+}
+
+// validateInt validates an integer value.
+func (rule *FieldRule) validateInt(field string, value int64) []*errs.FieldViolation {
+	if rule.Gte != nil && value < *rule.Gte {
+		return []*errs.FieldViolation{{Field: field, Description: fmt.Sprintf("must be greater than or equal to %d", *rule.Gte)}}
+	}
+
+	if rule.Lte != nil && value > *rule.Lte {
+		return []*errs.FieldViolation{{Field: field, Description: fmt.Sprintf("must be less than or equal to %d", *rule.Lte)}}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package validate
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	userv1 "github.com/xdorro/proto-base-project/proto-gen-go/user/v1"
+
+	auditv1 "github.com/xdorro/golang-grpc-base-project/pkg/api/audit/v1"
+	"github.com/xdorro/golang-grpc-base-project/pkg/errs"
+)
+
+var testRules = Rules{
+	"user.v1.CreateUserRequest": {
+		{Field: "name", MinLen: 2, MaxLen: 5},
+		{Field: "email", Required: true, Email: true},
+		{Field: "role", In: []string{"admin", "user"}},
+		{Field: "status", Gte: Int64(0), Lte: Int64(3)},
+		{Field: "unknown", Required: true},
+	},
+	"audit.v1.Audit": {
+		{Field: "targets", MaxItems: 2, Pattern: regexp.MustCompile(`^[a-z]+$`)},
+	},
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  any
+		want []*errs.FieldViolation
+	}{
+		{
+			name: "valid",
+			msg:  &userv1.CreateUserRequest{Name: "Ann", Email: "ann@example.com", Role: "user", Status: 1},
+			want: []*errs.FieldViolation{},
+		},
+		{
+			name: "invalid fields",
+			msg:  &userv1.CreateUserRequest{Name: "A", Email: "Ann <ann@example.com>", Role: "root", Status: 4},
+			want: []*errs.FieldViolation{
+				{Field: "name", Description: "must be at least 2 characters"},
+				{Field: "email", Description: "must be a valid email address"},
+				{Field: "role", Description: "must be one of [admin user]"},
+				{Field: "status", Description: "must be less than or equal to 3"},
+			},
+		},
+		{
+			name: "required and optional fields",
+			msg:  &userv1.CreateUserRequest{Name: "Ånnaé"},
+			want: []*errs.FieldViolation{{Field: "email", Description: "is required"}},
+		},
+		{
+			name: "repeated items",
+			msg:  &auditv1.Audit{Targets: []string{"ok", "Bad"}},
+			want: []*errs.FieldViolation{{Field: "targets[1]", Description: "must match ^[a-z]+$"}},
+		},
+		{
+			name: "too many items",
+			msg:  &auditv1.Audit{Targets: []string{"a", "b", "c"}},
+			want: []*errs.FieldViolation{{Field: "targets", Description: "must have at most 2 items"}},
+		},
+		{
+			name: "message without rules",
+			msg:  &userv1.FindAllUsersRequest{Page: -1},
+		},
+		{
+			name: "not a proto message",
+			msg:  map[string]string{"email": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testRules.Validate(tt.msg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateString(t *testing.T) {
+	rule := &FieldRule{Field: "email", Required: true, Email: true}
+
+	if got := rule.ValidateString("user@example.com"); len(got) != 0 {
+		t.Errorf("ValidateString() = %v, want no violation", got)
+	}
+
+	want := []*errs.FieldViolation{{Field: "email", Description: "is required"}}
+	if got := rule.ValidateString(""); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateString() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateStringMaxBytes(t *testing.T) {
+	rule := &FieldRule{Field: "password", MaxLen: 72, MaxBytes: 72}
+
+	tests := []struct {
+		value string
+		want  []*errs.FieldViolation
+	}{
+		{strings.Repeat("a", 72), nil},
+		{strings.Repeat("é", 36), nil},
+		// 37 runes within MaxLen, but 74 bytes
+		{strings.Repeat("é", 37), []*errs.FieldViolation{{Field: "password", Description: "must be at most 72 bytes"}}},
+	}
+	for _, tt := range tests {
+		if got := rule.ValidateString(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ValidateString(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}